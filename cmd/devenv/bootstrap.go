@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nauticalab/devenv-engine/internal/bootstrap"
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Bootstrap command flags
+	bootstrapOptions = bootstrap.DefaultOptions()
+	bootstrapYes     bool
+)
+
+// bootstrapCmd represents the bootstrap command
+var bootstrapCmd = &cobra.Command{
+	Use:   "bootstrap [directory]",
+	Short: "Create a new config repository",
+	Long: `Create the initial layout of a config repository in directory (default: .):
+
+  developers/devenv.yaml          Global config, filled in from your answers
+  .github/workflows/devenv.yml    CI workflow running devenv test on every change
+  .gitignore                      Ignores generated manifests in build/
+
+The namespace, domain and default resources are asked for unless given as
+flags. Existing files are never overwritten. Add developers afterwards with
+a developers/<name>/devenv-config.yaml each, or with devenv clone.
+
+Examples:
+  devenv bootstrap
+  devenv bootstrap ./devenv-configs --namespace ml-devenv --domain dev.example.com --yes`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		prompt := newPrompter(os.Stdin, bootstrapYes)
+		for _, question := range []struct {
+			flag   string
+			text   string
+			answer *string
+		}{
+			{"namespace", "Kubernetes namespace", &bootstrapOptions.Namespace},
+			{"domain", "Domain for HTTP access (empty to skip)", &bootstrapOptions.HostName},
+			{"cpu", "Default CPU per developer", &bootstrapOptions.CPU},
+			{"memory", "Default memory per developer", &bootstrapOptions.Memory},
+		} {
+			if !cmd.Flags().Changed(question.flag) {
+				*question.answer = prompt.askDefault(question.text, *question.answer)
+			}
+		}
+		if version != "dev" {
+			bootstrapOptions.Version = version
+		}
+
+		created, err := bootstrap.Write(dir, bootstrapOptions)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("\n✅ Created in %s:\n", dir)
+		for _, path := range created {
+			fmt.Printf("   %s\n", path)
+		}
+		developersDir := filepath.Join(dir, "developers")
+		fmt.Printf("\nNext steps:\n")
+		fmt.Printf("   1. Add a developer: %s\n", filepath.Join(developersDir, "<name>", config.DeveloperConfigFile))
+		fmt.Printf("   2. Check it:        devenv validate --config-dir %s\n", developersDir)
+		fmt.Printf("   3. Generate:        devenv generate --all-developers --config-dir %s\n", developersDir)
+	},
+}
+
+func init() {
+	bootstrapCmd.Flags().StringVar(&bootstrapOptions.Namespace, "namespace", bootstrapOptions.Namespace, "Kubernetes namespace for all environments")
+	bootstrapCmd.Flags().StringVar(&bootstrapOptions.HostName, "domain", "", "Domain for HTTP access to environments (hostName)")
+	bootstrapCmd.Flags().StringVar(&bootstrapOptions.CPU, "cpu", bootstrapOptions.CPU, "Default CPU per developer")
+	bootstrapCmd.Flags().StringVar(&bootstrapOptions.Memory, "memory", bootstrapOptions.Memory, "Default memory per developer")
+	bootstrapCmd.Flags().BoolVarP(&bootstrapYes, "yes", "y", false, "Do not prompt; use flags and defaults")
+}
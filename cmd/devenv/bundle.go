@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nauticalab/devenv-engine/internal/bundle"
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/nauticalab/devenv-engine/internal/templates"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// Export and import command flags
+	exportConfigDir string
+	exportOutput    string
+	importConfigDir string
+	importNamespace string
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export <developer>",
+	Short: "Export a developer environment into a portable bundle",
+	Long: `Pack a developer's environment into a .tar.gz bundle (default: <developer>.devenv.tgz):
+
+  bundle.yaml           Developer, source namespace and where the data lives
+  devenv-config.yaml    The config merged with devenv.yaml, complete on its own
+  manifests/            The rendered manifests
+
+Volume data is not copied. bundle.yaml lists the PersistentVolumeClaims and
+host paths holding it, to snapshot and restore in the target cluster.
+Named environments are not included.
+
+Examples:
+  devenv export alice
+  devenv export alice -o /tmp/alice.tgz --config-dir ./developers`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		developerName := args[0]
+
+		globalConfig, err := config.LoadGlobalConfig(exportConfigDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading global config in %s: %v\n", exportConfigDir, err)
+			os.Exit(1)
+		}
+		loadDataSources(exportConfigDir, globalConfig)
+		cfg, err := config.LoadDeveloperConfigWithBaseConfig(exportConfigDir, developerName, globalConfig)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config for developer %s: %v\n", developerName, err)
+			os.Exit(1)
+		}
+
+		manifests := make(map[string][]byte)
+		collect := func(filename string, content []byte) error {
+			manifests[filename] = content
+			return nil
+		}
+		renderer := templates.NewDevRenderer("")
+		setupRenderer(renderer)
+		renderer.SetExtras(os.DirFS(filepath.Join(cfg.DeveloperDir, templates.ExtrasDir)))
+		if err := renderer.RenderAllTo(cfg, collect); err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering manifests for developer %s: %v\n", developerName, err)
+			os.Exit(1)
+		}
+
+		b, err := bundle.New(cfg, manifests, version)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		path := exportOutput
+		if path == "" {
+			path = developerName + ".devenv.tgz"
+		}
+		file, err := os.Create(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := b.Write(file); err != nil {
+			file.Close()
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("📦 Exported %s to %s (%d manifests)\n", developerName, path, len(manifests))
+		printBundleData(b.Manifest)
+	},
+}
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <bundle>",
+	Short: "Create a developer config from an exported bundle",
+	Long: `Create <developer>/devenv-config.yaml from a bundle written by devenv export.
+
+The bundle's merged config becomes the developer's config, so it does not
+depend on the source devenv.yaml. With --namespace the environment moves
+to another namespace. The new config is validated like devenv validate
+does, and the command exits non-zero if it is invalid (e.g., its sshPort
+is already used in this repository).
+
+Restore the volumes listed by the command before generating and applying
+the manifests.
+
+Examples:
+  devenv import alice.devenv.tgz
+  devenv import alice.devenv.tgz --namespace devenv-eu --config-dir ./developers`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file, err := os.Open(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		b, err := bundle.Read(file)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", args[0], err)
+			os.Exit(1)
+		}
+
+		developerName := b.Manifest.Developer
+		if strings.ContainsAny(developerName, `/\`) || strings.HasPrefix(developerName, ".") {
+			fmt.Fprintf(os.Stderr, "Error: %q is not a valid developer name\n", developerName)
+			os.Exit(1)
+		}
+		targetDir := filepath.Join(importConfigDir, developerName)
+		if _, err := os.Stat(targetDir); err == nil {
+			fmt.Fprintf(os.Stderr, "Error: %s already exists\n", targetDir)
+			os.Exit(1)
+		}
+
+		content := b.Config
+		if importNamespace != "" {
+			content, err = setConfigNamespace(content, importNamespace)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if err := os.MkdirAll(targetDir, 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating %s: %v\n", targetDir, err)
+			os.Exit(1)
+		}
+		targetPath := filepath.Join(targetDir, config.DeveloperConfigFile)
+		if err := os.WriteFile(targetPath, content, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", targetPath, err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Created %s from %s (exported from namespace %s)\n", targetPath, args[0], b.Manifest.Namespace)
+		printBundleData(b.Manifest)
+
+		result, err := newValidatorSet(importConfigDir).validateSingle(developerName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Validation failed: %v\n", err)
+			os.Exit(1)
+		}
+		printValidationResult(result, developerName, []string{developerName})
+		if !result.IsValid {
+			fmt.Printf("Fix %s and run devenv validate %s\n", targetPath, developerName)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportConfigDir, "config-dir", "./developers", "Directory containing developer configuration files")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Bundle path (default: <developer>.devenv.tgz)")
+	importCmd.Flags().StringVar(&importConfigDir, "config-dir", "./developers", "Directory containing developer configuration files")
+	importCmd.Flags().StringVar(&importNamespace, "namespace", "", "Namespace for the imported environment (default: the source namespace)")
+}
+
+// printBundleData lists where the environment's volume data lives in the
+// source cluster.
+func printBundleData(manifest bundle.Manifest) {
+	if len(manifest.Data) == 0 {
+		return
+	}
+	fmt.Printf("\nVolume data (not included; snapshot and restore separately):\n")
+	for _, source := range manifest.Data {
+		if source.Claim != "" {
+			fmt.Printf("   %s: PersistentVolumeClaim %s/%s\n", source.Volume, manifest.Namespace, source.Claim)
+		} else {
+			fmt.Printf("   %s: host path %s\n", source.Volume, source.HostPath)
+		}
+	}
+}
+
+// setConfigNamespace sets 'namespace' in a developer config, keeping its
+// comments and key order.
+func setConfigNamespace(data []byte, namespace string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse bundled config: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("bundled config is not a YAML mapping")
+	}
+	setMappingScalar(doc.Content[0], "namespace", namespace)
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
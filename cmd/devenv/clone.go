@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/nauticalab/devenv-engine/internal/validation"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// Clone command flags
+	cloneConfigDir string
+	cloneSSHKeys   []string
+	cloneUID       int
+	cloneGitName   string
+	cloneGitEmail  string
+	cloneSSHPort   int
+	cloneYes       bool
+)
+
+// cloneCmd represents the clone command
+var cloneCmd = &cobra.Command{
+	Use:   "clone <source-developer> <new-developer>",
+	Short: "Create a developer config by copying another developer's",
+	Long: `Create <new-developer>/devenv-config.yaml from an existing developer's config.
+
+Resources, packages, volumes, repositories and other settings are copied
+as-is, with comments. Fields that identify the source developer are
+replaced:
+- name is set to the new developer
+- sshPort is set to the lowest free port in sshPortRange
+- sshPublicKey, uid and git name/email are asked for (or taken from flags);
+  an empty answer removes the field so it falls back to devenv.yaml
+
+Named environments are not copied. The new config is validated like
+devenv validate does, and the command exits non-zero if it is invalid.
+
+Examples:
+  devenv clone alice bob
+  devenv clone alice bob --ssh-key "ssh-ed25519 AAAA... bob@laptop" --git-email bob@example.com --yes`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		source, target := args[0], args[1]
+		if target == "" || strings.ContainsAny(target, `/\`) || strings.HasPrefix(target, ".") {
+			fmt.Fprintf(os.Stderr, "Error: %q is not a valid developer name\n", target)
+			os.Exit(1)
+		}
+
+		targetDir := filepath.Join(cloneConfigDir, target)
+		if _, err := os.Stat(targetDir); err == nil {
+			fmt.Fprintf(os.Stderr, "Error: %s already exists\n", targetDir)
+			os.Exit(1)
+		}
+
+		data, err := os.ReadFile(filepath.Join(cloneConfigDir, source, config.DeveloperConfigFile))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading config for developer %s: %v\n", source, err)
+			os.Exit(1)
+		}
+
+		content, err := cloneDeveloperConfig(cmd, data, target, newPrompter(os.Stdin, cloneYes))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := os.MkdirAll(targetDir, 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating %s: %v\n", targetDir, err)
+			os.Exit(1)
+		}
+		targetPath := filepath.Join(targetDir, config.DeveloperConfigFile)
+		if err := os.WriteFile(targetPath, content, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", targetPath, err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Created %s from %s\n", targetPath, source)
+
+		result, err := newValidatorSet(cloneConfigDir).validateSingle(target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Validation failed: %v\n", err)
+			os.Exit(1)
+		}
+		printValidationResult(result, target, []string{target})
+		if !result.IsValid {
+			fmt.Printf("Fix %s and run devenv validate %s\n", targetPath, target)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	cloneCmd.Flags().StringVar(&cloneConfigDir, "config-dir", "./developers", "Directory containing developer configuration files")
+	cloneCmd.Flags().StringArrayVar(&cloneSSHKeys, "ssh-key", nil, "SSH public key of the new developer (repeatable)")
+	cloneCmd.Flags().IntVar(&cloneUID, "uid", 0, "UID of the new developer (default: asked for when the source sets uid)")
+	cloneCmd.Flags().StringVar(&cloneGitName, "git-name", "", "Git user name of the new developer")
+	cloneCmd.Flags().StringVar(&cloneGitEmail, "git-email", "", "Git email of the new developer")
+	cloneCmd.Flags().IntVar(&cloneSSHPort, "ssh-port", 0, "SSH port of the new developer (default: lowest free port in sshPortRange)")
+	cloneCmd.Flags().BoolVarP(&cloneYes, "yes", "y", false, "Do not prompt; fields without a flag are removed")
+}
+
+// cloneDeveloperConfig rewrites the identity fields of a developer config
+// for target. The document is edited as a YAML node tree so comments and
+// key order survive.
+func cloneDeveloperConfig(cmd *cobra.Command, data []byte, target string, prompt *prompter) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse source config: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("source config is not a YAML mapping")
+	}
+	root := doc.Content[0]
+
+	setMappingScalar(root, "name", target)
+
+	sshPort := cloneSSHPort
+	if sshPort == 0 {
+		globalConfig, err := config.LoadGlobalConfig(cloneConfigDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load global config in %s: %w", cloneConfigDir, err)
+		}
+		allocated, err := validation.AllocateSSHPort(cloneConfigDir, globalConfig.SSHPortRange)
+		if err != nil {
+			return nil, err
+		}
+		sshPort = allocated
+	}
+	setMappingInt(root, "sshPort", sshPort)
+	fmt.Printf("🔌 sshPort: %d\n", sshPort)
+
+	// SSH keys: one key stays a string, several become a list
+	keys := cloneSSHKeys
+	if !cmd.Flags().Changed("ssh-key") {
+		if key := prompt.ask("SSH public key of " + target); key != "" {
+			keys = []string{key}
+		}
+	}
+	switch len(keys) {
+	case 0:
+		deleteMappingKey(root, "sshPublicKey")
+	case 1:
+		setMappingScalar(root, "sshPublicKey", keys[0])
+	default:
+		list := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, key := range keys {
+			list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key})
+		}
+		setMappingValue(root, "sshPublicKey", list)
+	}
+
+	if cmd.Flags().Changed("uid") {
+		setMappingInt(root, "uid", cloneUID)
+	} else if mappingValue(root, "uid") != nil {
+		// A copied UID would give both developers the same file ownership
+		if answer := prompt.ask("UID of " + target + " (empty for the devenv.yaml default)"); answer != "" {
+			uid, err := strconv.Atoi(answer)
+			if err != nil {
+				return nil, fmt.Errorf("invalid uid %q: must be a number", answer)
+			}
+			setMappingInt(root, "uid", uid)
+		} else {
+			deleteMappingKey(root, "uid")
+		}
+	}
+
+	if git := mappingValue(root, "git"); git != nil && git.Kind == yaml.MappingNode {
+		for _, field := range []struct{ key, flag, value, question string }{
+			{"name", "git-name", cloneGitName, "Git user name of " + target},
+			{"email", "git-email", cloneGitEmail, "Git email of " + target},
+		} {
+			value := field.value
+			if !cmd.Flags().Changed(field.flag) {
+				value = prompt.ask(field.question)
+			}
+			if value != "" {
+				setMappingScalar(git, field.key, value)
+			} else {
+				deleteMappingKey(git, field.key)
+			}
+		}
+		if len(git.Content) == 0 {
+			deleteMappingKey(root, "git")
+		}
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// mappingValue returns the value node of key in a mapping node, or nil.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue replaces the value of key, keeping its comments, or
+// appends key when it is missing.
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			old := mapping.Content[i+1]
+			value.LineComment, value.HeadComment, value.FootComment = old.LineComment, old.HeadComment, old.FootComment
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+// setMappingScalar sets key to a string, quoted only when YAML needs it.
+func setMappingScalar(mapping *yaml.Node, key, value string) {
+	setMappingValue(mapping, key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value})
+}
+
+// setMappingInt sets key to an integer.
+func setMappingInt(mapping *yaml.Node, key string, value int) {
+	setMappingValue(mapping, key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(value)})
+}
+
+// deleteMappingKey removes key and its value from a mapping node.
+func deleteMappingKey(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
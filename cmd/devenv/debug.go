@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/nauticalab/devenv-engine/internal/templates"
+	"gopkg.in/yaml.v3"
+)
+
+// debugContextLines is the number of template lines shown around an error.
+const debugContextLines = 2
+
+// debugTemplateOutput prints the data context of one template for a
+// developer, then the rendered manifest or the error with the failing
+// template line, and exits non-zero if rendering failed. Nothing is written.
+func debugTemplateOutput(templateName, developerName string) {
+	globalConfig, err := config.LoadGlobalConfig(configDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading global config in %s: %v\n", configDir, err)
+		os.Exit(1)
+	}
+	loadDataSources(configDir, globalConfig)
+
+	// System templates (e.g., namespace) render from the global config alone
+	systemRenderer := templates.NewSystemRenderer("")
+	setupRenderer(systemRenderer)
+	if slices.Contains(systemRenderer.Templates(), templateName) {
+		content, renderErr := systemRenderer.RenderToBytes(templateName, globalConfig)
+		source, _ := systemRenderer.TemplateSource(templateName)
+		printTemplateDebug(templateName, "devenv.yaml", globalConfig, content, source, renderErr)
+		return
+	}
+
+	devRenderer := templates.NewDevRenderer("")
+	setupRenderer(devRenderer)
+	if !slices.Contains(devRenderer.Templates(), templateName) {
+		available := append(devRenderer.Templates(), systemRenderer.Templates()...)
+		slices.Sort(available)
+		fmt.Fprintf(os.Stderr, "Error: unknown template %q (available: %s)\n", templateName, strings.Join(available, ", "))
+		os.Exit(1)
+	}
+
+	var cfg *config.DevEnvConfig
+	if envName != "" {
+		cfg, err = config.LoadDeveloperEnvironment(configDir, developerName, envName, globalConfig)
+	} else {
+		cfg, err = config.LoadDeveloperConfigWithBaseConfig(configDir, developerName, globalConfig)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config for developer %s: %v\n", developerName, err)
+		os.Exit(1)
+	}
+
+	target := developerName
+	if cfg.Environment != "" {
+		target += " (environment: " + cfg.Environment + ")"
+	}
+	content, renderErr := devRenderer.RenderToBytes(templateName, cfg)
+	source, _ := devRenderer.TemplateSource(templateName)
+	printTemplateDebug(templateName, target, cfg, content, source, renderErr)
+}
+
+// printTemplateDebug prints the sections of --debug-template output.
+func printTemplateDebug(templateName, target string, data any, content, source []byte, renderErr error) {
+	dataYAML, err := yaml.Marshal(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding data context: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("# ---- Data context: %s for %s ----\n", templateName, target)
+	os.Stdout.Write(dataYAML)
+
+	if renderErr != nil {
+		fmt.Printf("# ---- Error rendering %s ----\n", templateName)
+		fmt.Println(renderErr)
+		if line, column, ok := templates.ErrorPosition(renderErr, templateName); ok {
+			fmt.Print(templateExcerpt(source, line, column))
+		}
+		os.Exit(1)
+	}
+
+	fmt.Printf("# ---- Rendered: %s ----\n", templates.OutputFilename(templateName))
+	os.Stdout.Write(content)
+}
+
+// templateExcerpt shows the template lines around line (1-based), marking
+// column (0-based, in bytes) with a caret unless it is -1.
+func templateExcerpt(source []byte, line, column int) string {
+	lines := strings.Split(string(source), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+
+	var excerpt strings.Builder
+	first := max(line-debugContextLines, 1)
+	last := min(line+debugContextLines, len(lines))
+	width := len(fmt.Sprint(last))
+	for n := first; n <= last; n++ {
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&excerpt, "%s %*d | %s\n", marker, width, n, lines[n-1])
+		if n == line && column >= 0 {
+			fmt.Fprintf(&excerpt, "  %*s | %s^\n", width, "", strings.Repeat(" ", column))
+		}
+	}
+	return excerpt.String()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/nauticalab/devenv-engine/internal/manifests"
+)
+
+// diffOutput receives the diffs in --diff mode. Like JSON mode, progress
+// messages are redirected to stderr so stdout carries only the patch.
+var diffOutput io.Writer
+
+// setupDiffOutput redirects os.Stdout to stderr for the rest of the command
+// and sends diffs to the original stdout.
+func setupDiffOutput() {
+	diffOutput = os.Stdout
+	os.Stdout = os.Stderr
+}
+
+// changedFiles counts the manifests that differ from the output directory
+// in --diff mode. Batch workers update it concurrently.
+var changedFiles atomic.Int64
+
+// diffManifestWriter returns a write function that prints a unified diff
+// between each rendered manifest and the file at the same path in dir,
+// instead of writing it.
+func diffManifestWriter(dir string) func(filename string, content []byte) error {
+	prefix, err := filepath.Rel(outputDir, dir)
+	if err != nil {
+		prefix = filepath.Base(dir)
+	}
+	prefix = filepath.ToSlash(prefix)
+	return func(filename string, content []byte) error {
+		name := path.Join(prefix, filename)
+		oldName := "a/" + name
+
+		existing, err := os.ReadFile(filepath.Join(dir, filename))
+		if errors.Is(err, fs.ErrNotExist) {
+			oldName = "/dev/null"
+		} else if err != nil {
+			return err
+		}
+
+		diff := manifests.UnifiedDiff(oldName, "b/"+name, existing, content)
+		if diff == "" {
+			return nil
+		}
+		changedFiles.Add(1)
+		// One write per file keeps diffs from concurrent workers apart
+		_, err = io.WriteString(diffOutput, diff)
+		return err
+	}
+}
+
+// printDiffSummary reports how many manifests --diff found changed.
+func printDiffSummary() {
+	switch count := changedFiles.Load(); count {
+	case 0:
+		fmt.Printf("\n✅ No changes: %s is up to date\n", outputDir)
+	case 1:
+		fmt.Printf("\n📝 1 file would change in %s\n", outputDir)
+	default:
+		fmt.Printf("\n📝 %d files would change in %s\n", count, outputDir)
+	}
+}
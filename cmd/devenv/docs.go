@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nauticalab/devenv-engine/internal/docs"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Docs command flags
+	docsConfigDir string
+	docsOutputDir string
+	docsFormat    string
+	docsGroupBy   string
+)
+
+// docsCmd represents the docs command
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate an overview page of all developer environments",
+	Long: `Generate a page listing every developer and named environment with its
+profile, image, resources, SSH port and HTTP URL, for publishing on an
+internal portal.
+
+Only merged config values that are safe to publish are included. SSH keys,
+environment variables, extraValues, git identities and auth settings are
+left out, and invalid configs are only marked as such; their errors are
+printed here instead.
+
+With --group-by, environments are grouped by an extraValues key, e.g.
+extraValues.team.
+
+Examples:
+  devenv docs
+  devenv docs --format markdown --group-by team -o ./portal`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !slices.Contains(docs.Formats, docsFormat) {
+			fmt.Fprintf(os.Stderr, "Error: invalid --format %q (supported: %s)\n", docsFormat, strings.Join(docs.Formats, ", "))
+			os.Exit(1)
+		}
+
+		site, err := docs.Collect(docsConfigDir, docsGroupBy)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, group := range site.Groups {
+			for _, env := range group.Environments {
+				if env.Error != "" {
+					fmt.Fprintf(os.Stderr, "⚠️  %s: %s\n", env.Instance, env.Error)
+				}
+			}
+		}
+
+		if err := os.MkdirAll(docsOutputDir, 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating %s: %v\n", docsOutputDir, err)
+			os.Exit(1)
+		}
+		path := filepath.Join(docsOutputDir, docs.Filename(docsFormat))
+		file, err := os.Create(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := docs.Render(site, docsFormat, file); err != nil {
+			file.Close()
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		fmt.Printf("📚 Wrote %s (%d developers)\n", path, site.Developers)
+	},
+}
+
+func init() {
+	docsCmd.Flags().StringVar(&docsConfigDir, "config-dir", "./developers", "Directory containing developer configuration files")
+	docsCmd.Flags().StringVarP(&docsOutputDir, "output", "o", "./site", "Directory the page is written to")
+	docsCmd.Flags().StringVar(&docsFormat, "format", docs.FormatHTML, "Page format: html or markdown")
+	docsCmd.Flags().StringVar(&docsGroupBy, "group-by", "", "Group environments by this extraValues key (e.g., team)")
+}
@@ -1,14 +1,27 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/nauticalab/devenv-engine/internal/archive"
 	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/nauticalab/devenv-engine/internal/datasources"
+	"github.com/nauticalab/devenv-engine/internal/hooks"
+	"github.com/nauticalab/devenv-engine/internal/manifests"
+	"github.com/nauticalab/devenv-engine/internal/provenance"
 	"github.com/nauticalab/devenv-engine/internal/templates"
+	"github.com/nauticalab/devenv-engine/internal/validation"
 	"github.com/spf13/cobra"
 )
 
@@ -19,10 +32,45 @@ type DeveloperJob struct {
 
 // ProcessingResult represents the outcome of processing one developer
 type ProcessingResult struct {
-	Developer string
-	Success   bool
-	Error     error
-	Duration  time.Duration
+	Developer   string
+	Environment string // Set when a named environment was generated
+	Success     bool
+	Error       error
+	Warnings    []config.ValidationIssue
+	Duration    time.Duration
+}
+
+// MarshalJSON renders the result for --output-format json, flattening the
+// error to its message and the duration to seconds.
+func (r ProcessingResult) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Developer       string                   `json:"developer"`
+		Environment     string                   `json:"environment,omitempty"`
+		Success         bool                     `json:"success"`
+		Error           string                   `json:"error,omitempty"`
+		Warnings        []config.ValidationIssue `json:"warnings,omitempty"`
+		DurationSeconds float64                  `json:"durationSeconds"`
+	}{
+		Developer:       r.Developer,
+		Environment:     r.Environment,
+		Success:         r.Success,
+		Warnings:        r.Warnings,
+		DurationSeconds: r.Duration.Seconds(),
+	}
+	if r.Error != nil {
+		out.Error = r.Error.Error()
+	}
+	return json.Marshal(out)
+}
+
+// generateReport is the document emitted by generate with --output-format json.
+type generateReport struct {
+	Success   bool               `json:"success"`
+	DryRun    bool               `json:"dryRun"`
+	OutputDir string             `json:"outputDir,omitempty"`
+	Archive   string             `json:"archive,omitempty"`
+	Error     string             `json:"error,omitempty"` // Fatal error that stopped generation
+	Results   []ProcessingResult `json:"results"`
 }
 
 var (
@@ -31,16 +79,105 @@ var (
 	configDir string // Input directory for developer configs
 	dryRun    bool
 	allDevs   bool
+	archiveTo string // Optional .tar.gz path that receives all rendered manifests
+	envName   string // Optional named environment (environments/<name>.yaml) to apply
+	stampTime bool   // Include the generation time in provenance headers
+	// Byte-identical outputs for identical inputs (no timestamps, sorted archive)
+	reproducible bool
+	watchMode    bool // Keep running and regenerate when configs change
+	keepGoing    bool // Write the templates that rendered even if others fail
+	diffMode     bool // Print a diff against the output directory instead of writing
+	// Template whose data context and output are printed instead of generating
+	debugTemplate string
+	progressFile  string // Optional path receiving JSON-lines progress events
+	progressFD    int    // Optional inherited file descriptor receiving progress events
+	templateDir   string // Optional directory whose template_files/ override the embedded templates
+	singleFile    bool   // Write each developer's manifests into one <developer>.yaml
+	stdoutMode    bool   // Print the manifests to stdout instead of writing them
+	// Check rendered manifests against the Kubernetes schemas before writing
+	validateOutput bool
 )
 
+// templateFiles is set when --template-dir is used; renderers read templates
+// from it instead of the embedded ones.
+var templateFiles fs.FS
+
+// dataSources resolves lookup calls in templates. It is replaced whenever
+// devenv.yaml is loaded, so each run (and each --watch regeneration) sees
+// fresh values.
+var dataSources *datasources.Registry
+
+// manifestArchive is set when --archive is used; rendered manifests are
+// streamed into it instead of being written to the output directory.
+var manifestArchive *archive.Writer
+
+// runIndex collects the targets rendered in this run for index.yaml.
+var runIndex *provenance.Index
+
 var generateCmd = &cobra.Command{
 	Use:   "generate [developer-name]",
 	Short: "Generate manifests for a developer environment",
 	Long: `Generate Kubernetes manifests for a specific developer or all developers.
 
+All templates of a developer are rendered and every failing template is
+reported. By default nothing is written for a developer with a failing
+template; with --keep-going the manifests that rendered are written anyway.
+
+With --diff, nothing is written: manifests are rendered in memory and a
+unified diff against the files in the output directory is printed to
+stdout (progress goes to stderr).
+
+With --debug-template, nothing is written: the merged config a template is
+rendered with is printed as YAML, followed by the rendered manifest or, if
+rendering fails, the error and the failing template line.
+
+With --watch, generate keeps running after the first run and regenerates the
+manifests of a developer whenever a file in their config directory changes
+(all developers when devenv.yaml changes). Template changes are not
+watched.
+
+With --template-dir, templates are read from the template_files/ directory
+inside it, falling back to the templates compiled into the binary for every
+file it does not contain. The directory has the layout of
+internal/templates, so overriding the StatefulSet only takes
+<dir>/template_files/dev/manifests/statefulset.tmpl. Overriding a partial
+in template_files/dev/partials/, such as labels.tmpl, changes every template
+that includes it.
+
+Commands listed under hooks.preGenerate and hooks.postGenerate in
+devenv.yaml run before and after the manifests are written, with the run
+context in DEVENV_* environment variables. A failing preGenerate hook stops
+generation; a failing postGenerate hook makes generate exit non-zero. Hooks
+do not run with --dry-run, --diff or --stdout, nor on --watch regenerations.
+
+With --validate-output, every rendered manifest is checked against the
+Kubernetes schemas of the kinds devenv generates before anything is
+written; unknown fields, wrong types and invalid values are reported with
+their file and line, and fail the developer like a template error.
+
+With --stdout, nothing is written: the manifests are printed to stdout as
+one multi-document YAML stream, ready for kubectl apply -f -, and progress
+goes to stderr.
+
+With --single-file, the manifests of a developer are written as one
+multi-document <developer>.yaml (<developer>-<env>.yaml for environments)
+in the output directory instead of a directory per developer.
+
+With --progress-file or --progress-fd, one JSON object per line is written
+as developers start, succeed or fail, so wrappers can follow long runs.
+
 Examples:
   devenv generate eywalker
-  devenv generate --all-developers --output ./manifests`,
+  devenv generate eywalker --env gpu
+  devenv generate --all-developers --output ./manifests
+  devenv generate --all-developers --archive manifests.tgz
+  devenv generate --all-developers --diff
+  devenv generate --debug-template statefulset eywalker
+  devenv generate --all-developers --template-dir ./site-templates
+  devenv generate --all-developers --single-file
+  devenv generate eywalker --stdout | kubectl apply -f -
+  devenv generate --all-developers --validate-output
+  devenv generate --all-developers --watch`,
 	Args: cobra.MaximumNArgs(1), // At max 1 argument
 	Run: func(cmd *cobra.Command, args []string) {
 		//Validation logic
@@ -49,22 +186,142 @@ Examples:
 			os.Exit(1)
 		}
 
+		if reproducible && stampTime {
+			fmt.Fprintf(os.Stderr, "Error: --timestamp cannot be used with --reproducible\n")
+			os.Exit(1)
+		}
+
+		if allDevs && envName != "" {
+			fmt.Fprintf(os.Stderr, "Error: --env can only be used with a single developer\n")
+			os.Exit(1)
+		}
+
+		if watchMode && (dryRun || archiveTo != "" || outputFormat != outputFormatText) {
+			fmt.Fprintf(os.Stderr, "Error: --watch cannot be used with --dry-run, --archive or --output-format json\n")
+			os.Exit(1)
+		}
+
+		if diffMode && (dryRun || archiveTo != "" || watchMode || outputFormat != outputFormatText) {
+			fmt.Fprintf(os.Stderr, "Error: --diff cannot be used with --dry-run, --archive, --watch or --output-format json\n")
+			os.Exit(1)
+		}
+
+		if stdoutMode && (dryRun || archiveTo != "" || watchMode || diffMode || outputFormat != outputFormatText) {
+			fmt.Fprintf(os.Stderr, "Error: --stdout cannot be used with --dry-run, --archive, --watch, --diff or --output-format json\n")
+			os.Exit(1)
+		}
+
+		if templateDir != "" {
+			info, err := os.Stat(filepath.Join(templateDir, "template_files"))
+			if err != nil || !info.IsDir() {
+				fmt.Fprintf(os.Stderr, "Error: %s does not contain template_files/\n", templateDir)
+				os.Exit(1)
+			}
+			templateFiles = templates.WithOverrides(os.DirFS(templateDir))
+		}
+
+		if debugTemplate != "" {
+			if allDevs || len(args) == 0 || dryRun || archiveTo != "" || watchMode || diffMode || stdoutMode || outputFormat != outputFormatText {
+				fmt.Fprintf(os.Stderr, "Error: --debug-template needs a single developer and cannot be used with --dry-run, --archive, --watch, --diff, --stdout or --output-format json\n")
+				os.Exit(1)
+			}
+			debugTemplateOutput(debugTemplate, args[0])
+			return
+		}
+
 		if !allDevs && len(args) == 0 {
 			fmt.Fprintf(os.Stderr, "Error: Please specify a developer name or use --all-developers\n")
 			cmd.Help()
 			os.Exit(1)
 		}
 
-		// Execute the logic (placeholder for now)
+		if err := setupOutputFormat(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := openProgressStream(progressFile, progressFD); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if archiveTo != "" && !dryRun {
+			openManifestArchive()
+		}
+		if diffMode {
+			setupDiffOutput()
+		}
+		if stdoutMode {
+			setupStdoutOutput()
+		}
+		runIndex = provenance.NewIndex(provenanceInfo())
+
+		// Hooks only run when manifests are actually written
+		runHooks := !dryRun && !diffMode && !stdoutMode
+		var hookConfig config.HooksConfig
+		var hookContext hooks.Context
+		if runHooks {
+			hookConfig, hookContext = loadGenerateHooks(args)
+			hookContext.Hook = hooks.PreGenerate
+			if err := hooks.Run(hookConfig.PreGenerate, hookContext, os.Stdout, os.Stderr); err != nil {
+				exitGeneration("Error: %v", err)
+			}
+		}
+
+		// Execute the logic
+		var results []ProcessingResult
+		var developerName string
+		runStart := time.Now()
 		if allDevs {
 			fmt.Println("Generating manifests for all developers...")
 			if verbose {
 				fmt.Printf("Output directory: %s\n", outputDir)
 			}
-			generateAllDevelopersWithProgress()
+			results = generateAllDevelopersWithProgress()
 		} else {
-			developerName := args[0]
-			generateSingleDeveloper(developerName)
+			developerName = args[0]
+			progress.batchStarted(1)
+			progress.started(developerName)
+			results = generateSingleDeveloper(developerName)
+			for _, result := range results {
+				progress.finished(result)
+			}
+		}
+		progress.batchFinished(results, time.Since(runStart))
+
+		if diffMode {
+			printDiffSummary()
+		} else if !dryRun && !stdoutMode {
+			writeRunIndex()
+		}
+		closeManifestArchive()
+
+		var hookErr error
+		if runHooks {
+			hookContext.Hook = hooks.PostGenerate
+			hookContext.Failed = failedDevelopers(results)
+			if hookErr = hooks.Run(hookConfig.PostGenerate, hookContext, os.Stdout, os.Stderr); hookErr != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", hookErr)
+			}
+		}
+
+		// Initial failures are expected while iterating; keep watching
+		if watchMode {
+			watchConfigDir(developerName)
+			return
+		}
+
+		progress.close()
+		report := newGenerateReport(results)
+		if hookErr != nil {
+			report.Success = false
+			report.Error = hookErr.Error()
+		}
+		if jsonMode() {
+			writeJSON(report)
+		}
+		if !report.Success {
+			os.Exit(1) // Exit with error if any failures
 		}
 	},
 }
@@ -75,16 +332,66 @@ func init() {
 	generateCmd.Flags().StringVar(&configDir, "config-dir", "./developers", "Directory containing developer configuration files")
 	generateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be generated without creating files")
 	generateCmd.Flags().BoolVar(&allDevs, "all-developers", false, "Generate manifests for all developers")
+	generateCmd.Flags().StringVar(&archiveTo, "archive", "", "Stream rendered manifests into a .tar.gz archive instead of the output directory")
+	generateCmd.Flags().BoolVar(&stampTime, "timestamp", false, "Include the generation time in file headers and index.yaml (output is no longer reproducible)")
+	generateCmd.Flags().BoolVar(&reproducible, "reproducible", false, "Produce byte-identical output for identical inputs (no timestamps, archive entries sorted with a fixed time from SOURCE_DATE_EPOCH or 1970)")
+	generateCmd.Flags().BoolVar(&watchMode, "watch", false, "Keep running and regenerate manifests when files in the config directory change")
+	generateCmd.Flags().StringVar(&debugTemplate, "debug-template", "", "Print the data context and rendered output of one template (e.g., statefulset) instead of generating")
+	generateCmd.Flags().BoolVar(&diffMode, "diff", false, "Print a unified diff against the files in the output directory instead of writing them")
+	generateCmd.Flags().BoolVar(&keepGoing, "keep-going", false, "Write the manifests that rendered even if other templates fail (failures are still reported)")
+	generateCmd.Flags().StringVar(&envName, "env", "", "Apply the developer's named environment (environments/<name>.yaml) on top of devenv-config.yaml")
+	generateCmd.Flags().StringVar(&progressFile, "progress-file", "", "Write JSON-lines progress events (started/succeeded/failed per developer) to a file")
+	generateCmd.Flags().IntVar(&progressFD, "progress-fd", 0, "Write JSON-lines progress events to an inherited file descriptor (3 or higher)")
+	generateCmd.Flags().BoolVar(&validateOutput, "validate-output", false, "Check rendered manifests against the Kubernetes schemas and fail with line-level errors")
+	generateCmd.Flags().BoolVar(&stdoutMode, "stdout", false, "Print the manifests to stdout as one YAML stream instead of writing them (progress goes to stderr)")
+	generateCmd.Flags().BoolVar(&singleFile, "single-file", false, "Write each developer's manifests into one <developer>.yaml with --- separators")
+	generateCmd.Flags().StringVar(&templateDir, "template-dir", "", "Directory whose template_files/ override the embedded templates per file")
+	generateCmd.Flags().StringVar(&outputFormat, "output-format", outputFormatText, "Output format for results: text or json (progress goes to stderr in json mode)")
 
 }
 
-func generateAllDevelopersWithProgress() {
+// newGenerateReport summarizes per-developer results; generation succeeds
+// only if every developer succeeded.
+func newGenerateReport(results []ProcessingResult) generateReport {
+	report := generateReport{
+		Success: true,
+		DryRun:  dryRun,
+		Archive: archiveTo,
+		Results: results,
+	}
+	if archiveTo == "" {
+		report.OutputDir = outputDir
+	}
+	for _, result := range results {
+		if !result.Success {
+			report.Success = false
+		}
+	}
+	return report
+}
+
+// exitGeneration reports an error that stops generation before or outside
+// per-developer processing and exits. In JSON mode a failed report is still
+// emitted so CI always receives a document.
+func exitGeneration(format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	fmt.Fprintln(os.Stderr, message)
+	if jsonMode() {
+		report := newGenerateReport(nil)
+		report.Success = false
+		report.Error = message
+		writeJSON(report)
+	}
+	os.Exit(1)
+}
+
+func generateAllDevelopersWithProgress() []ProcessingResult {
 	// Step 1: Load global config once
 	globalConfig, err := config.LoadGlobalConfig(configDir)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading global config in %s: %v\n", configDir, err)
-		os.Exit(1)
+		exitGeneration("Error loading global config in %s: %v", configDir, err)
 	}
+	loadDataSources(configDir, globalConfig)
 
 	if verbose {
 		fmt.Printf("Generating system manifests in %s\n", outputDir)
@@ -93,25 +400,37 @@ func generateAllDevelopersWithProgress() {
 	// Step 2: Generate system manifests once
 	if !dryRun {
 		if err := generateSystemManifests(globalConfig, outputDir); err != nil {
-			fmt.Fprintf(os.Stderr, "Error generating system manifests: %v\n", err)
-			os.Exit(1)
+			exitGeneration("Error generating system manifests: %v", err)
 		}
 	}
 
 	// Step 3: Discover all developers
 	developers, err := findAllDevelopers(configDir)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error discovering developers: %v\n", err)
-		os.Exit(1)
+		exitGeneration("Error discovering developers: %v", err)
 	}
 
+	progress.batchStarted(len(developers))
 	if len(developers) == 0 {
 		fmt.Printf("No developers found in %s\n", configDir)
-		return
+		return nil
 	}
 
 	fmt.Printf("Found %d developers to process.\n", len(developers))
 
+	// Refuse to render developers whose names map to the same resources
+	nameResult, err := validation.NewNameValidator(configDir).ValidateAll()
+	if err != nil {
+		exitGeneration("Error checking developer names: %v", err)
+	}
+	if !nameResult.IsValid {
+		var collisions []string
+		for _, collision := range nameResult.Errors {
+			collisions = append(collisions, "❌ Name Collision: "+collision.Message)
+		}
+		exitGeneration("%s", strings.Join(collisions, "\n"))
+	}
+
 	// Step 4: Set up channels for worker communication
 	const numWorkers = 4
 	jobs := make(chan DeveloperJob, len(developers))
@@ -131,13 +450,16 @@ func generateAllDevelopersWithProgress() {
 	// Step 7: Collect results
 	var successCount, failureCount int
 	var failures []ProcessingResult
+	collected := make([]ProcessingResult, 0, len(developers))
 
 	for i := 0; i < len(developers); i++ {
 		result := <-results
+		collected = append(collected, result)
 		if result.Success {
 			successCount++
 			fmt.Printf("[%d/%d] ✅ %s (%.1fs)\n",
 				i+1, len(developers), result.Developer, result.Duration.Seconds())
+			printValidationWarnings(result.Developer, result.Warnings)
 		} else {
 			failureCount++
 			failures = append(failures, result)
@@ -158,33 +480,47 @@ func generateAllDevelopersWithProgress() {
 		for _, failure := range failures {
 			fmt.Printf("  - %s: %v\n", failure.Developer, failure.Error)
 		}
-		os.Exit(1) // Exit with error if any failures
 	}
+
+	// Workers finish in any order; report results deterministically
+	sort.Slice(collected, func(i, j int) bool {
+		return collected[i].Developer < collected[j].Developer
+	})
+	return collected
 }
 
 func developerWorker(jobs <-chan DeveloperJob, results chan<- ProcessingResult, globalConfig *config.BaseConfig) {
 	for job := range jobs {
 		startTime := time.Now()
-		err := processSingleDeveloperForBatchWithError(job.Name, globalConfig)
+		progress.started(job.Name)
+		warnings, err := processSingleDeveloperForBatchWithError(job.Name, globalConfig)
 
-		results <- ProcessingResult{
+		result := ProcessingResult{
 			Developer: job.Name,
 			Success:   err == nil,
 			Error:     err,
+			Warnings:  warnings,
 			Duration:  time.Since(startTime),
 		}
+		progress.finished(result)
+		results <- result
 	}
 }
 
 // processSingleDeveloperForBatchWithError processes a single developer for batch mode
-func processSingleDeveloperForBatchWithError(developerName string, globalConfig *config.BaseConfig) error {
+// and returns any non-blocking validation warnings found while loading.
+func processSingleDeveloperForBatchWithError(developerName string, globalConfig *config.BaseConfig) ([]config.ValidationIssue, error) {
 	if verbose {
 		fmt.Printf("Processing developer: %s\n", developerName)
 	}
 
 	cfg, err := config.LoadDeveloperConfigWithBaseConfig(configDir, developerName, globalConfig)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if verbose {
+		printDerivedValues(cfg)
 	}
 
 	// Create user-specific output directory
@@ -192,36 +528,19 @@ func processSingleDeveloperForBatchWithError(developerName string, globalConfig
 
 	if !dryRun {
 		if err := generateDeveloperManifests(cfg, userOutputDir); err != nil {
-			return fmt.Errorf("failed to generate manifests: %w", err)
+			return cfg.Warnings, fmt.Errorf("failed to generate manifests: %w", err)
 		}
 	}
 
-	return nil
+	return cfg.Warnings, nil
 }
 
 func findAllDevelopers(configDir string) ([]string, error) {
-	var developers []string
-
-	entries, err := os.ReadDir(configDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config directory: %w", err)
-	}
-
-	for _, entry := range entries {
-		if entry.IsDir() {
-			// Check to make sure devenv-config.yaml exists in this directory
-			configPath := filepath.Join(configDir, entry.Name(), "devenv-config.yaml")
-			if _, err := os.Stat(configPath); err == nil {
-				developers = append(developers, entry.Name())
-			}
-		}
-	}
-
-	return developers, nil
+	return config.ListDeveloperDirs(configDir)
 }
 
 // generateSingleDeveloper handles generation for a single developer
-func generateSingleDeveloper(developerName string) {
+func generateSingleDeveloper(developerName string) []ProcessingResult {
 	fmt.Printf("Generating manifests for developer: %s\n", developerName)
 
 	if verbose {
@@ -230,74 +549,437 @@ func generateSingleDeveloper(developerName string) {
 		fmt.Printf("Dry run mode: %t\n", dryRun)
 	}
 
-	userOutputDir := filepath.Join(outputDir, developerName)
+	startTime := time.Now()
 
 	globalConfig, err := config.LoadGlobalConfig(configDir)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading global config in %s: %v\n", configDir, err)
-		os.Exit(1)
+		exitGeneration("Error loading global config in %s: %v", configDir, err)
 	}
+	loadDataSources(configDir, globalConfig)
 
 	if err := generateSystemManifests(globalConfig, outputDir); err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating system manifests: %v\n", err)
-		os.Exit(1)
+		exitGeneration("Error generating system manifests: %v", err)
 	}
 
-	cfg, err := config.LoadDeveloperConfigWithBaseConfig(configDir, developerName, globalConfig)
+	var cfg *config.DevEnvConfig
+	if envName != "" {
+		cfg, err = config.LoadDeveloperEnvironment(configDir, developerName, envName, globalConfig)
+	} else {
+		cfg, err = config.LoadDeveloperConfigWithBaseConfig(configDir, developerName, globalConfig)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config for developer %s: %v\n", developerName, err)
-		os.Exit(1)
+		return []ProcessingResult{{
+			Developer:   developerName,
+			Environment: envName,
+			Error:       fmt.Errorf("failed to load config: %w", err),
+			Duration:    time.Since(startTime),
+		}}
 	}
 
-	fmt.Printf("✅ Successfully loaded configuration for developer: %s\n", cfg.Name)
+	userOutputDir := developerOutputDir(cfg, developerName)
+	if cfg.Environment != "" {
+		fmt.Printf("✅ Successfully loaded configuration for developer: %s (environment: %s)\n", cfg.Name, cfg.Environment)
+	} else {
+		fmt.Printf("✅ Successfully loaded configuration for developer: %s\n", cfg.Name)
+	}
+	printValidationWarnings(developerName, cfg.Warnings)
 
 	if verbose {
+		printDerivedValues(cfg)
 		printConfigSummary(cfg)
 	}
 
 	if !dryRun {
 		if err := generateDeveloperManifests(cfg, userOutputDir); err != nil {
 			fmt.Fprintf(os.Stderr, "Error generating manifests: %v\n", err)
-			os.Exit(1)
+			return []ProcessingResult{{
+				Developer:   developerName,
+				Environment: envName,
+				Error:       fmt.Errorf("failed to generate manifests: %w", err),
+				Warnings:    cfg.Warnings,
+				Duration:    time.Since(startTime),
+			}}
 		}
 	} else {
-		fmt.Printf("🔍 Dry run - would generate manifests to: %s\n", userOutputDir)
+		target := userOutputDir
+		if singleFile {
+			target += ".yaml"
+		}
+		fmt.Printf("🔍 Dry run - would generate manifests to: %s\n", target)
+	}
+
+	return []ProcessingResult{{
+		Developer:   developerName,
+		Environment: envName,
+		Success:     true,
+		Warnings:    cfg.Warnings,
+		Duration:    time.Since(startTime),
+	}}
+}
+
+// developerOutputDir returns the directory a developer's manifests are
+// written to. Environments render next to the developer's default manifests.
+func developerOutputDir(cfg *config.DevEnvConfig, developerName string) string {
+	if cfg.Environment != "" {
+		return filepath.Join(outputDir, developerName+"-"+cfg.Environment)
 	}
+	return filepath.Join(outputDir, developerName)
 }
 
 func generateSystemManifests(cfg *config.BaseConfig, outputDir string) error {
+	source, err := provenance.HashSources(configDir, globalConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to hash config files: %w", err)
+	}
+
 	// Create template renderer
 	renderer := templates.NewSystemRenderer(outputDir)
+	setupRenderer(renderer)
+	renderer.SetHeader(provenance.Header(runIndex.Info(), source))
+	renderer.SetKeepGoing(keepGoing)
 
 	// Render all main templates
-	if err := renderer.RenderAll(cfg); err != nil {
+	if err := renderManifests(renderer, cfg, outputDir); err != nil {
 		return fmt.Errorf("failed to render templates: %w", err)
 	}
 
-	fmt.Printf("🎉 Successfully generated system manifests\n")
+	recordTarget(outputDir, "", "", source, renderer.Filenames())
+	if !diffMode {
+		fmt.Printf("🎉 Successfully generated system manifests\n")
+	}
 
 	return nil
 }
 
 // generateDeveloperManifests creates Kubernetes manifests for a developer
 func generateDeveloperManifests(cfg *config.DevEnvConfig, outputDir string) error {
+	sources := []string{globalConfigPath(), filepath.Join(cfg.DeveloperDir, config.DeveloperConfigFile)}
+	if cfg.Environment != "" {
+		sources = append(sources, filepath.Join(cfg.DeveloperDir, config.EnvironmentsDir, cfg.Environment+".yaml"))
+	}
+	sources = append(sources, extraManifestSources(cfg)...)
+	source, err := provenance.HashSources(configDir, sources...)
+	if err != nil {
+		return fmt.Errorf("failed to hash config files: %w", err)
+	}
+
 	// Create template renderer
 	renderer := templates.NewDevRenderer(outputDir)
+	setupRenderer(renderer)
+	renderer.SetExtras(os.DirFS(filepath.Join(cfg.DeveloperDir, templates.ExtrasDir)))
+	renderer.SetKeepGoing(keepGoing)
+	header := provenance.Header(runIndex.Info(), source)
 
-	// Render all main templates
-	if err := renderer.RenderAll(cfg); err != nil {
+	// Render all main templates. On stdout, a developer's manifests are
+	// joined too, so concurrent batch workers never interleave them.
+	if singleFile || stdoutMode {
+		err = renderSingleFile(renderer, cfg, outputDir, header)
+	} else {
+		renderer.SetHeader(header)
+		err = renderManifests(renderer, cfg, outputDir)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to render templates: %w", err)
 	}
 
-	fmt.Printf("🎉 Successfully generated manifests for %s\n", cfg.Name)
+	recordTarget(outputDir, cfg.Name, cfg.Environment, source, renderer.Filenames())
+	if !diffMode {
+		fmt.Printf("🎉 Successfully generated manifests for %s\n", cfg.Name)
+	}
+
+	return nil
+}
+
+// extraManifestSources returns the paths of the extra manifests in the
+// developer's extras/ directory, so they are part of the config hash.
+func extraManifestSources(cfg *config.DevEnvConfig) []string {
+	var sources []string
+	for _, pattern := range []string{"*.yaml", "*.yml", "*.yaml.tmpl", "*.yml.tmpl"} {
+		matches, _ := filepath.Glob(filepath.Join(cfg.DeveloperDir, templates.ExtrasDir, pattern))
+		sources = append(sources, matches...)
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// setupRenderer makes renderer read templates from --template-dir, if set,
+// check its output with --validate-output, and resolve lookup from the
+// data sources in devenv.yaml.
+func setupRenderer[T config.BaseConfig | config.DevEnvConfig](renderer *templates.Renderer[T]) {
+	if templateFiles != nil {
+		renderer.SetFS(templateFiles)
+	}
+	if validateOutput {
+		renderer.SetValidate(validateManifest)
+	}
+	if dataSources != nil {
+		renderer.SetLookup(dataSources.Lookup)
+	}
+}
+
+// validateManifest checks a rendered manifest for --validate-output: the
+// structural checks of devenv test plus the Kubernetes schemas.
+func validateManifest(filename string, content []byte) error {
+	problems := manifests.Check(filename, content)
+	problems = append(problems, manifests.CheckSchema(filename, content)...)
+	return errors.Join(problems...)
+}
+
+// loadDataSources sets up the data sources declared in globalConfig, the
+// devenv.yaml of dir.
+func loadDataSources(dir string, globalConfig *config.BaseConfig) {
+	dataSources = datasources.New(dir, globalConfig.DataSources)
+}
+
+// renderManifests renders all templates of renderer to stdout, into the
+// archive, as a diff against dir, or into dir, depending on the flags.
+func renderManifests[T config.BaseConfig | config.DevEnvConfig](renderer *templates.Renderer[T], cfg *T, dir string) error {
+	switch {
+	case stdoutMode:
+		return renderer.RenderAllTo(cfg, stdoutManifestWriter())
+	case manifestArchive != nil:
+		return renderer.RenderAllTo(cfg, archiveManifestWriter(dir))
+	case diffMode:
+		return renderer.RenderAllTo(cfg, diffManifestWriter(dir))
+	default:
+		return renderer.RenderAll(cfg)
+	}
+}
+
+// renderSingleFile renders all manifests of renderer into one
+// multi-document file named after dir and placed next to it (e.g.,
+// "build/alice.yaml" for "build/alice"), with header once at the top. The
+// file goes to stdout, the archive, diff or output directory like other
+// manifests.
+func renderSingleFile(renderer *templates.Renderer[config.DevEnvConfig], cfg *config.DevEnvConfig, dir string, header []byte) error {
+	parent, filename := filepath.Dir(dir), filepath.Base(dir)+".yaml"
+	if err := checkSingleFilename(parent, filename, stdoutMode); err != nil {
+		return err
+	}
+
+	// With --keep-going, the manifests that rendered are still joined
+	var documents [][]byte
+	renderErr := renderer.RenderAllTo(cfg, func(_ string, content []byte) error {
+		documents = append(documents, content)
+		return nil
+	})
+	if len(documents) == 0 {
+		return renderErr
+	}
 
+	var write func(filename string, content []byte) error
+	switch {
+	case stdoutMode:
+		write = stdoutManifestWriter()
+	case manifestArchive != nil:
+		write = archiveManifestWriter(parent)
+	case diffMode:
+		write = diffManifestWriter(parent)
+	default:
+		write = func(filename string, content []byte) error {
+			outputPath := filepath.Join(parent, filename)
+			if err := os.MkdirAll(parent, 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(outputPath, content, 0644); err != nil {
+				return err
+			}
+			fmt.Printf("✅ Generated %s\n", outputPath)
+			return nil
+		}
+	}
+	if err := write(filename, manifests.Join(header, documents)); err != nil {
+		return errors.Join(renderErr, fmt.Errorf("failed to write %s: %w", filename, err))
+	}
+	return renderErr
+}
+
+// checkSingleFilename rejects a --single-file filename that would replace a
+// file generated for the whole output directory (index.yaml or a system
+// manifest). Output on stdout writes no such files.
+func checkSingleFilename(parent, filename string, stdout bool) error {
+	if stdout {
+		return nil
+	}
+	if filename == provenance.IndexFilename || slices.Contains(templates.NewSystemRenderer(parent).Filenames(), filename) {
+		return fmt.Errorf("%s would replace a file generated for the whole output directory; use a directory per developer instead of --single-file", filename)
+	}
 	return nil
 }
 
+// loadGenerateHooks returns the hooks configured in devenv.yaml and the
+// context they run with: the developers this run generates and where the
+// manifests are written.
+func loadGenerateHooks(args []string) (config.HooksConfig, hooks.Context) {
+	globalConfig, err := config.LoadGlobalConfig(configDir)
+	if err != nil {
+		exitGeneration("Error loading global config in %s: %v", configDir, err)
+	}
+
+	developers := args
+	if allDevs {
+		developers, err = findAllDevelopers(configDir)
+		if err != nil {
+			exitGeneration("Error discovering developers: %v", err)
+		}
+	}
+
+	hookContext := hooks.Context{
+		ConfigDir:   configDir,
+		Archive:     archiveTo,
+		Environment: envName,
+		Developers:  developers,
+	}
+	if archiveTo == "" {
+		hookContext.OutputDir = outputDir
+	}
+	return globalConfig.Hooks, hookContext
+}
+
+// failedDevelopers returns the developers whose generation failed.
+func failedDevelopers(results []ProcessingResult) []string {
+	var failed []string
+	for _, result := range results {
+		if !result.Success {
+			failed = append(failed, result.Developer)
+		}
+	}
+	return failed
+}
+
+// provenanceInfo identifies this build of devenv in generated headers.
+func provenanceInfo() provenance.Info {
+	info := provenance.Info{Version: version, Commit: gitCommit}
+	if stampTime {
+		info.GeneratedAt = time.Now().Truncate(time.Second)
+	}
+	return info
+}
+
+// globalConfigPath returns the path of devenv.yaml in the config directory.
+func globalConfigPath() string {
+	return filepath.Join(configDir, "devenv.yaml")
+}
+
+// recordTarget adds manifests rendered into dir to the run index, with
+// paths relative to the output directory.
+func recordTarget(dir, developer, environment string, source provenance.Source, filenames []string) {
+	prefix, err := filepath.Rel(outputDir, dir)
+	if err != nil {
+		prefix = filepath.Base(dir)
+	}
+	prefix = filepath.ToSlash(prefix)
+
+	files := make([]string, len(filenames))
+	for i, filename := range filenames {
+		files[i] = path.Join(prefix, filename)
+	}
+	if singleFile && developer != "" {
+		files = []string{prefix + ".yaml"}
+	}
+	runIndex.Add(provenance.Target{
+		Name:        prefix,
+		Developer:   developer,
+		Environment: environment,
+		ConfigHash:  source.Hash,
+		Sources:     source.Files,
+		Files:       files,
+	})
+}
+
+// writeRunIndex writes index.yaml, summarizing every target rendered in
+// this run, to the output directory or archive.
+func writeRunIndex() {
+	content, err := runIndex.Marshal()
+	if err != nil {
+		exitGeneration("Error writing %s: %v", provenance.IndexFilename, err)
+	}
+
+	if manifestArchive != nil {
+		err = manifestArchive.WriteFile(provenance.IndexFilename, content)
+	} else if err = os.MkdirAll(outputDir, 0755); err == nil {
+		err = os.WriteFile(filepath.Join(outputDir, provenance.IndexFilename), content, 0644)
+	}
+	if err != nil {
+		exitGeneration("Error writing %s: %v", provenance.IndexFilename, err)
+	}
+	if verbose {
+		fmt.Printf("📇 Wrote run index: %s\n", provenance.IndexFilename)
+	}
+}
+
+// openManifestArchive creates the --archive tarball that rendered manifests
+// are streamed into.
+func openManifestArchive() {
+	w, err := archive.Create(archiveTo)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if reproducible {
+		modTime, err := sourceDateEpoch()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		w.SetReproducible(modTime)
+	}
+	manifestArchive = w
+}
+
+// sourceDateEpoch returns the time recorded in reproducible archives: the
+// SOURCE_DATE_EPOCH environment variable (seconds since the Unix epoch, see
+// reproducible-builds.org) or the epoch itself when unset.
+func sourceDateEpoch() (time.Time, error) {
+	value := os.Getenv("SOURCE_DATE_EPOCH")
+	if value == "" {
+		return time.Unix(0, 0), nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: must be seconds since the Unix epoch", value)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// closeManifestArchive flushes and closes the --archive tarball, if any.
+func closeManifestArchive() {
+	if manifestArchive == nil {
+		return
+	}
+	if err := manifestArchive.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing archive %s: %v\n", archiveTo, err)
+		os.Exit(1)
+	}
+	manifestArchive = nil
+	fmt.Printf("📦 Wrote manifests archive: %s\n", archiveTo)
+}
+
+// archiveManifestWriter returns a write function that stores manifests for
+// dir in the archive, at the same relative path they would have under the
+// output directory (e.g., "alice/statefulset.yaml").
+func archiveManifestWriter(dir string) func(filename string, content []byte) error {
+	prefix, err := filepath.Rel(outputDir, dir)
+	if err != nil {
+		prefix = filepath.Base(dir)
+	}
+	prefix = filepath.ToSlash(prefix)
+	return func(filename string, content []byte) error {
+		return manifestArchive.WriteFile(path.Join(prefix, filename), content)
+	}
+}
+
 // Helper function to print config summary
 func printConfigSummary(cfg *config.DevEnvConfig) {
 	fmt.Printf("\nConfiguration Summary:\n")
 	fmt.Printf("  Name: %s\n", cfg.Name)
+	if cfg.Environment != "" {
+		fmt.Printf("  Environment: %s (resources named after %s)\n", cfg.Environment, cfg.InstanceName())
+	}
+	if cfg.Profile != "" {
+		fmt.Printf("  Profile: %s\n", cfg.Profile)
+	}
 
 	sshKeys, _ := cfg.GetSSHKeys()
 	fmt.Printf("  SSH Keys: %d configured\n", len(sshKeys))
@@ -306,6 +988,8 @@ func printConfigSummary(cfg *config.DevEnvConfig) {
 		fmt.Printf("  SSH Port: %d\n", cfg.SSHPort)
 	}
 
+	fmt.Printf("  Pod DNS: %s\n", cfg.PodFQDN())
+
 	if cfg.Git.Name != "" {
 		fmt.Printf("  Git: %s <%s>\n", cfg.Git.Name, cfg.Git.Email)
 	}
@@ -319,10 +1003,18 @@ func printConfigSummary(cfg *config.DevEnvConfig) {
 	if hasCPU || hasMem {
 		var parts []string
 		if hasCPU {
-			parts = append(parts, fmt.Sprintf("CPU=%s", cpuStr))
+			part := fmt.Sprintf("CPU=%s", cpuStr)
+			if limit := cfg.CPULimit(); limit != cpuStr {
+				part += fmt.Sprintf(" (limit %s)", limit)
+			}
+			parts = append(parts, part)
 		}
 		if hasMem {
-			parts = append(parts, fmt.Sprintf("Memory=%s", memStr))
+			part := fmt.Sprintf("Memory=%s", memStr)
+			if limit := cfg.MemoryLimit(); limit != memStr {
+				part += fmt.Sprintf(" (limit %s)", limit)
+			}
+			parts = append(parts, part)
 		}
 		fmt.Printf("  Resources: %s\n", strings.Join(parts, ", "))
 	}
@@ -334,6 +1026,28 @@ func printConfigSummary(cfg *config.DevEnvConfig) {
 	fmt.Printf("  Developer Config Dir: %s\n", cfg.GetDeveloperDir())
 }
 
+// printDerivedValues reports fields that were synthesized from derivedDefaults
+// patterns so users know the value did not come from their config file. Like
+// the config summary, it is only printed with --verbose.
+func printDerivedValues(cfg *config.DevEnvConfig) {
+	for _, d := range cfg.Derived {
+		fmt.Printf("ℹ️  %s: %s derived as %q from pattern %q\n", cfg.Name, d.Field, d.Value, d.Pattern)
+	}
+}
+
+// printValidationWarnings prints non-blocking validation issues for a developer.
+// When running under GitHub Actions, each warning is also emitted as a
+// workflow annotation so it surfaces on the pull request.
+func printValidationWarnings(developerName string, warnings []config.ValidationIssue) {
+	configPath := filepath.Join(configDir, developerName, config.DeveloperConfigFile)
+	for _, warning := range warnings {
+		fmt.Printf("⚠️  %s: %s\n", developerName, warning.Message)
+		if os.Getenv("GITHUB_ACTIONS") == "true" {
+			fmt.Printf("::warning file=%s,title=%s::%s\n", configPath, warning.Rule, warning.Message)
+		}
+	}
+}
+
 // Helper function to format CPU value for display
 func formatCPU(cpu any) string {
 	if cpu == nil {
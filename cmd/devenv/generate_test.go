@@ -0,0 +1,19 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSingleFilename(t *testing.T) {
+	// A developer named like a system manifest only conflicts when files
+	// are written
+	assert.NoError(t, checkSingleFilename("build", "grafana-dashboard.yaml", true))
+	assert.ErrorContains(t, checkSingleFilename("build", "grafana-dashboard.yaml", false),
+		"grafana-dashboard.yaml would replace a file generated for the whole output directory")
+
+	assert.ErrorContains(t, checkSingleFilename("build", "index.yaml", false),
+		"index.yaml would replace a file generated for the whole output directory")
+	assert.NoError(t, checkSingleFilename("build", "alice.yaml", false))
+}
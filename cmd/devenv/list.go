@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported values for list --output.
+const (
+	listOutputTable = "table"
+	listOutputJSON  = "json"
+	listOutputYAML  = "yaml"
+)
+
+var (
+	// List command flags
+	listConfigDir string
+	listOutput    string
+)
+
+// listEntry is one developer in the output of devenv list.
+type listEntry struct {
+	Name    string `json:"name" yaml:"name"`
+	Image   string `json:"image,omitempty" yaml:"image,omitempty"`
+	CPU     string `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+	Memory  string `json:"memory,omitempty" yaml:"memory,omitempty"`
+	GPU     int    `json:"gpu" yaml:"gpu"`
+	SSHPort int    `json:"sshPort,omitempty" yaml:"sshPort,omitempty"`
+	IsAdmin bool   `json:"isAdmin" yaml:"isAdmin"`
+	Volumes int    `json:"volumes" yaml:"volumes"`
+	Expires string `json:"expires,omitempty" yaml:"expires,omitempty"`
+	Expired bool   `json:"expired,omitempty" yaml:"expired,omitempty"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"` // Set when the config could not be loaded
+}
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all developers with their key settings",
+	Long: `List every developer in the config directory with the image, CPU and
+memory requests, GPUs, SSH port, admin flag, number of extra volumes and
+expiry date of their merged config.
+
+Developers whose config cannot be loaded are reported on stderr; in json
+and yaml output they are included with an error field.
+
+Examples:
+  devenv list
+  devenv list --output json --config-dir ./developers`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		switch listOutput {
+		case listOutputTable, listOutputJSON, listOutputYAML:
+		default:
+			fmt.Fprintf(os.Stderr, "Error: invalid --output %q (supported: %s, %s, %s)\n", listOutput, listOutputTable, listOutputJSON, listOutputYAML)
+			os.Exit(1)
+		}
+
+		globalConfig, err := config.LoadGlobalConfig(listConfigDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading global config in %s: %v\n", listConfigDir, err)
+			os.Exit(1)
+		}
+		developers, err := findAllDevelopers(listConfigDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		entries := make([]listEntry, 0, len(developers))
+		for _, developerName := range developers {
+			cfg, err := config.LoadDeveloperConfigWithBaseConfig(listConfigDir, developerName, globalConfig)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  %s: %v\n", developerName, err)
+				entries = append(entries, listEntry{Name: developerName, Error: err.Error()})
+				continue
+			}
+			entries = append(entries, listEntry{
+				Name:    cfg.Name,
+				Image:   cfg.ContainerImage(),
+				CPU:     cfg.CPU(),
+				Memory:  cfg.Memory(),
+				GPU:     cfg.GPU(),
+				SSHPort: cfg.SSHPort,
+				IsAdmin: cfg.IsAdmin,
+				Volumes: len(cfg.Volumes),
+				Expires: cfg.Expires,
+				Expired: cfg.Expired(),
+			})
+		}
+
+		if err := printListEntries(entries); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	listCmd.Flags().StringVar(&listConfigDir, "config-dir", "./developers", "Directory containing developer configuration files")
+	listCmd.Flags().StringVarP(&listOutput, "output", "o", listOutputTable, "Output format: table, json or yaml")
+}
+
+// printListEntries writes the entries to stdout in the --output format. The
+// table leaves out developers that could not be loaded, as they were
+// already reported on stderr.
+func printListEntries(entries []listEntry) error {
+	switch listOutput {
+	case listOutputJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(entries)
+	case listOutputYAML:
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(entries); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tIMAGE\tCPU\tMEMORY\tGPU\tSSH PORT\tADMIN\tVOLUMES\tEXPIRES")
+	for _, entry := range entries {
+		if entry.Error != "" {
+			continue
+		}
+		sshPort := "-"
+		if entry.SSHPort != 0 {
+			sshPort = strconv.Itoa(entry.SSHPort)
+		}
+		admin := "no"
+		if entry.IsAdmin {
+			admin = "yes"
+		}
+		expires := "-"
+		if entry.Expires != "" {
+			expires = entry.Expires
+			if entry.Expired {
+				expires += " (expired)"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%d\t%s\n",
+			entry.Name, entry.Image, entry.CPU, entry.Memory, entry.GPU, sshPort, admin, entry.Volumes, expires)
+	}
+	return w.Flush()
+}
@@ -7,6 +7,15 @@
 //	devenv generate eywalker
 //	devenv generate --all-developers
 //	devenv validate eywalker
+//	devenv test
+//	devenv schema > devenv-config.schema.json
+//	devenv templates dev eywalker
+//	devenv clone eywalker newdev
+//	devenv bootstrap
+//	devenv docs --group-by team
+//	devenv export eywalker
+//	devenv ports assign
+//	devenv list --output json
 //
 // Use --help with any command for detailed usage information.
 package main
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Supported values for --output-format.
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+// outputFormat is bound to --output-format on generate and validate.
+var outputFormat string
+
+// jsonOutput receives the JSON document in JSON mode. Human-readable
+// progress messages are redirected to stderr so stdout carries only JSON.
+var jsonOutput io.Writer
+
+// setupOutputFormat validates --output-format and, in JSON mode, redirects
+// os.Stdout to stderr for the rest of the command.
+func setupOutputFormat() error {
+	switch outputFormat {
+	case outputFormatText:
+		return nil
+	case outputFormatJSON:
+		jsonOutput = os.Stdout
+		os.Stdout = os.Stderr
+		return nil
+	default:
+		return fmt.Errorf("invalid --output-format %q (supported: %s, %s)", outputFormat, outputFormatText, outputFormatJSON)
+	}
+}
+
+// jsonMode reports whether results should be emitted as JSON.
+func jsonMode() bool {
+	return jsonOutput != nil
+}
+
+// writeJSON writes v as an indented JSON document to the JSON output stream.
+func writeJSON(v any) {
+	encoder := json.NewEncoder(jsonOutput)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing JSON output: %v\n", err)
+		os.Exit(1)
+	}
+}
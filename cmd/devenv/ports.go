@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/nauticalab/devenv-engine/internal/validation"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// Ports command flags
+	portsConfigDir string
+	portsDryRun    bool
+)
+
+// portsCmd groups commands for SSH port assignments
+var portsCmd = &cobra.Command{
+	Use:   "ports",
+	Short: "List and assign SSH ports",
+	Long: `List the SSH ports used by developers and named environments, and assign
+free ports to developers without one.
+
+Ports are assigned from sshPortRange in devenv.yaml (default: the whole
+Kubernetes NodePort range, 30000-32767) and written to the developers'
+devenv-config.yaml, so the config repository stays the record of which
+port belongs to whom.`,
+}
+
+// portsListCmd represents the ports list command
+var portsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the SSH ports in use",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		globalConfig := loadPortsGlobalConfig()
+		assignments, err := validation.ListSSHPorts(portsConfigDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		first, last := validation.SSHPortBounds(globalConfig.SSHPortRange)
+		taken := make(map[int]bool)
+		for _, port := range globalConfig.SSHPortRange.Reserved {
+			if port >= first && port <= last {
+				taken[port] = true
+			}
+		}
+		for _, assignment := range assignments {
+			note := ""
+			switch {
+			case slices.Contains(globalConfig.SSHPortRange.Reserved, assignment.Port):
+				note = "  ❌ reserved"
+			case assignment.Port < first || assignment.Port > last:
+				note = "  ⚠️  outside sshPortRange"
+			default:
+				taken[assignment.Port] = true
+			}
+			fmt.Printf("%d  %s%s\n", assignment.Port, assignment, note)
+		}
+		fmt.Printf("\n🔌 %d of %d ports in %d-%d are free", last-first+1-len(taken), last-first+1, first, last)
+		if len(globalConfig.SSHPortRange.Reserved) > 0 {
+			fmt.Printf(" (%d reserved)", len(globalConfig.SSHPortRange.Reserved))
+		}
+		fmt.Println()
+	},
+}
+
+// portsAssignCmd represents the ports assign command
+var portsAssignCmd = &cobra.Command{
+	Use:   "assign [developer...]",
+	Short: "Assign free SSH ports to developers without one",
+	Long: `Set sshPort in the devenv-config.yaml of each given developer that has none,
+or of every such developer when no developer is given. Each developer gets
+the lowest free port in sshPortRange that is not reserved. Developers that
+already have a port are left unchanged.
+
+Examples:
+  devenv ports assign
+  devenv ports assign alice bob --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		globalConfig := loadPortsGlobalConfig()
+		index, err := config.IndexDevelopers(portsConfigDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var targets []config.DeveloperIndexEntry
+		for _, name := range args {
+			i := slices.IndexFunc(index, func(entry config.DeveloperIndexEntry) bool { return entry.Developer == name })
+			if i < 0 {
+				fmt.Fprintf(os.Stderr, "Error: developer %s not found in %s\n", name, portsConfigDir)
+				os.Exit(1)
+			}
+			targets = append(targets, index[i])
+		}
+		if len(args) == 0 {
+			targets = index
+		}
+
+		assignments, err := validation.ListSSHPorts(portsConfigDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		used := make(map[int]bool, len(assignments))
+		for _, assignment := range assignments {
+			used[assignment.Port] = true
+		}
+		for _, port := range globalConfig.SSHPortRange.Reserved {
+			used[port] = true
+		}
+		first, last := validation.SSHPortBounds(globalConfig.SSHPortRange)
+
+		assigned := 0
+		for _, entry := range targets {
+			if entry.Err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  Skipping %s: %v\n", entry.Developer, entry.Err)
+				continue
+			}
+			if entry.SSHPort != 0 {
+				if len(args) > 0 {
+					fmt.Printf("   %s already uses port %d\n", entry.Developer, entry.SSHPort)
+				}
+				continue
+			}
+
+			port := first
+			for port <= last && used[port] {
+				port++
+			}
+			if port > last {
+				fmt.Fprintf(os.Stderr, "Error: all SSH ports in %d-%d are in use\n", first, last)
+				os.Exit(1)
+			}
+			used[port] = true
+
+			if portsDryRun {
+				fmt.Printf("🔍 Would assign port %d to %s\n", port, entry.Developer)
+			} else {
+				if err := writeSSHPort(entry.ConfigPath, port); err != nil {
+					fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", entry.ConfigPath, err)
+					os.Exit(1)
+				}
+				fmt.Printf("✅ Assigned port %d to %s\n", port, entry.Developer)
+			}
+			assigned++
+		}
+		if assigned == 0 {
+			fmt.Println("✅ Every developer already has an SSH port")
+		}
+	},
+}
+
+func init() {
+	portsCmd.PersistentFlags().StringVar(&portsConfigDir, "config-dir", "./developers", "Directory containing developer configuration files")
+	portsAssignCmd.Flags().BoolVar(&portsDryRun, "dry-run", false, "Show the ports that would be assigned without writing them")
+
+	portsCmd.AddCommand(portsListCmd)
+	portsCmd.AddCommand(portsAssignCmd)
+}
+
+// loadPortsGlobalConfig loads devenv.yaml for its sshPortRange.
+func loadPortsGlobalConfig() *config.BaseConfig {
+	globalConfig, err := config.LoadGlobalConfig(portsConfigDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading global config in %s: %v\n", portsConfigDir, err)
+		os.Exit(1)
+	}
+	return globalConfig
+}
+
+// writeSSHPort sets sshPort in a developer config file.
+func writeSSHPort(configPath string, port int) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+	data, err = setSSHPort(data, port)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, data, 0644)
+}
+
+// setSSHPort returns the config data with sshPort set to port. A missing
+// key is appended to a block mapping in the file's line endings, so the rest
+// of the file is untouched. Otherwise (an existing key such as "sshPort: 0",
+// a flow mapping, or a line that would not extend the mapping, e.g. after a
+// "..." document end marker) the YAML is re-encoded, keeping comments and
+// key order. Either result is parsed again and must only differ from data
+// in sshPort.
+func setSSHPort(data []byte, port int) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config is not a YAML mapping")
+	}
+	root := doc.Content[0]
+
+	var want map[string]any
+	if err := doc.Decode(&want); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	want["sshPort"] = port
+
+	if mappingValue(root, "sshPort") == nil && root.Style&yaml.FlowStyle == 0 {
+		newline := "\n"
+		if bytes.Contains(data, []byte("\r\n")) {
+			newline = "\r\n"
+		}
+		appended := slices.Clone(data)
+		if len(appended) > 0 && !bytes.HasSuffix(appended, []byte("\n")) {
+			appended = append(appended, newline...)
+		}
+		appended = append(appended, fmt.Sprintf("sshPort: %d%s", port, newline)...)
+		if decodesTo(appended, want) {
+			return appended, nil
+		}
+	}
+
+	setMappingInt(root, "sshPort", port)
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	if !decodesTo(out.Bytes(), want) {
+		return nil, fmt.Errorf("failed to set sshPort: the updated config does not parse to the same values")
+	}
+	return out.Bytes(), nil
+}
+
+// decodesTo reports whether the first YAML document in data decodes to want.
+func decodesTo(data []byte, want map[string]any) bool {
+	var got map[string]any
+	return yaml.Unmarshal(data, &got) == nil && reflect.DeepEqual(got, want)
+}
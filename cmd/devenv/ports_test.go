@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSSHPort(t *testing.T) {
+	cases := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{
+			name:   "block mapping",
+			config: "# Alice's environment\nname: alice\npackages:\n  apt: [vim] # editor\n",
+			want:   "# Alice's environment\nname: alice\npackages:\n  apt: [vim] # editor\nsshPort: 30100\n",
+		},
+		{
+			name:   "no trailing newline",
+			config: "name: alice",
+			want:   "name: alice\nsshPort: 30100\n",
+		},
+		{
+			name:   "flow mapping",
+			config: "{name: alice, packages: {apt: [vim]}}\n",
+			want:   "{name: alice, packages: {apt: [vim]}, sshPort: 30100}\n",
+		},
+		{
+			name:   "document end marker",
+			config: "name: alice\n...\n",
+			want:   "name: alice\nsshPort: 30100\n",
+		},
+		{
+			name:   "existing sshPort",
+			config: "name: alice\nsshPort: 0 # assigned by devenv ports assign\nimage: ubuntu:24.04\n",
+			want:   "name: alice\nsshPort: 30100 # assigned by devenv ports assign\nimage: ubuntu:24.04\n",
+		},
+		{
+			name:   "CRLF line endings",
+			config: "name: alice\r\nimage: ubuntu:24.04\r\n",
+			want:   "name: alice\r\nimage: ubuntu:24.04\r\nsshPort: 30100\r\n",
+		},
+		{
+			name:   "CRLF without trailing newline",
+			config: "name: alice\r\nimage: ubuntu:24.04",
+			want:   "name: alice\r\nimage: ubuntu:24.04\r\nsshPort: 30100\r\n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "devenv-config.yaml")
+			require.NoError(t, os.WriteFile(configPath, []byte(tc.config), 0o644))
+
+			require.NoError(t, writeSSHPort(configPath, 30100))
+			data, err := os.ReadFile(configPath)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(data))
+		})
+	}
+}
+
+func TestWriteSSHPort_Errors(t *testing.T) {
+	for name, config := range map[string]string{
+		"not a mapping": "- alice\n",
+		"invalid YAML":  "name: [alice\n",
+		"empty file":    "",
+	} {
+		t.Run(name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "devenv-config.yaml")
+			require.NoError(t, os.WriteFile(configPath, []byte(config), 0o644))
+
+			assert.Error(t, writeSSHPort(configPath, 30100))
+			data, err := os.ReadFile(configPath)
+			require.NoError(t, err)
+			assert.Equal(t, config, string(data), "the file is left unchanged")
+		})
+	}
+}
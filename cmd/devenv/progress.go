@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Progress event types written to the --progress-file stream.
+const (
+	progressBatchStarted  = "batchStarted"
+	progressStarted       = "started"
+	progressSucceeded     = "succeeded"
+	progressFailed        = "failed"
+	progressBatchFinished = "batchFinished"
+)
+
+// progressEvent is one JSON line of the progress stream.
+type progressEvent struct {
+	Event           string    `json:"event"`
+	Time            time.Time `json:"time"`
+	Developer       string    `json:"developer,omitempty"`
+	Environment     string    `json:"environment,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	DurationSeconds *float64  `json:"durationSeconds,omitempty"`
+	Total           *int      `json:"total,omitempty"`     // batchStarted
+	Succeeded       *int      `json:"succeeded,omitempty"` // batchFinished
+	Failed          *int      `json:"failed,omitempty"`    // batchFinished
+}
+
+// progressStream writes progress events as JSON lines while generate runs,
+// so wrappers can follow long batch runs. A nil stream discards events.
+type progressStream struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// progress is set when --progress-file or --progress-fd is used.
+var progress *progressStream
+
+// openProgressStream opens the progress stream selected by the flags, if any.
+func openProgressStream(path string, fd int) error {
+	var file *os.File
+	switch {
+	case path != "" && fd != 0:
+		return fmt.Errorf("--progress-file and --progress-fd cannot be used together")
+	case path != "":
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create progress file: %w", err)
+		}
+		file = f
+	case fd != 0:
+		if fd < 3 {
+			return fmt.Errorf("--progress-fd must be 3 or higher (0-2 are stdin, stdout and stderr)")
+		}
+		file = os.NewFile(uintptr(fd), "progress")
+	default:
+		return nil
+	}
+	progress = &progressStream{file: file, encoder: json.NewEncoder(file)}
+	return nil
+}
+
+// emit writes one event. Write errors are reported once and stop the stream
+// rather than failing generation.
+func (p *progressStream) emit(event progressEvent) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.encoder == nil {
+		return
+	}
+	event.Time = time.Now().UTC()
+	if err := p.encoder.Encode(event); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Writing progress events: %v (no further events are written)\n", err)
+		p.encoder = nil
+	}
+}
+
+// batchStarted reports how many developers a run is about to generate.
+func (p *progressStream) batchStarted(total int) {
+	p.emit(progressEvent{Event: progressBatchStarted, Total: &total})
+}
+
+// started reports that a developer's generation has begun.
+func (p *progressStream) started(developer string) {
+	p.emit(progressEvent{Event: progressStarted, Developer: developer, Environment: envName})
+}
+
+// finished reports the outcome of a developer's generation.
+func (p *progressStream) finished(result ProcessingResult) {
+	seconds := result.Duration.Seconds()
+	event := progressEvent{
+		Event:           progressSucceeded,
+		Developer:       result.Developer,
+		Environment:     result.Environment,
+		DurationSeconds: &seconds,
+	}
+	if !result.Success {
+		event.Event = progressFailed
+		if result.Error != nil {
+			event.Error = result.Error.Error()
+		}
+	}
+	p.emit(event)
+}
+
+// batchFinished reports the totals of a run.
+func (p *progressStream) batchFinished(results []ProcessingResult, duration time.Duration) {
+	succeeded, failed := 0, 0
+	for _, result := range results {
+		if result.Success {
+			succeeded++
+		} else {
+			failed++
+		}
+	}
+	seconds := duration.Seconds()
+	p.emit(progressEvent{Event: progressBatchFinished, Succeeded: &succeeded, Failed: &failed, DurationSeconds: &seconds})
+}
+
+// close closes the progress stream, if any.
+func (p *progressStream) close() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.file.Close()
+	p.encoder = nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// prompter asks questions on the terminal, one answer per line.
+type prompter struct {
+	reader *bufio.Reader
+	skip   bool // Accept every default without asking (--yes)
+}
+
+func newPrompter(input io.Reader, skip bool) *prompter {
+	return &prompter{reader: bufio.NewReader(input), skip: skip}
+}
+
+// ask prints question and returns the trimmed answer; at end of input it
+// returns "".
+func (p *prompter) ask(question string) string {
+	return p.askDefault(question, "")
+}
+
+// askDefault is like ask but offers defaultValue, which an empty answer
+// accepts.
+func (p *prompter) askDefault(question, defaultValue string) string {
+	if p.skip {
+		return defaultValue
+	}
+	if defaultValue != "" {
+		fmt.Printf("%s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Printf("%s: ", question)
+	}
+	answer, _ := p.reader.ReadString('\n')
+	if answer = strings.TrimSpace(answer); answer != "" {
+		return answer
+	}
+	return defaultValue
+}
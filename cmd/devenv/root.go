@@ -27,4 +27,14 @@ func init() {
 	rootCmd.AddCommand(generateCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(testCmd)
+	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(templatesCmd)
+	rootCmd.AddCommand(cloneCmd)
+	rootCmd.AddCommand(bootstrapCmd)
+	rootCmd.AddCommand(docsCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(portsCmd)
+	rootCmd.AddCommand(listCmd)
 }
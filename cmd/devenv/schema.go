@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Schema command flags
+	schemaOutput string
+)
+
+// schemaCmd represents the schema command
+var schemaCmd = &cobra.Command{
+	Use:   "schema [" + strings.Join(config.SchemaKinds, "|") + "]",
+	Short: "Print the JSON Schema of config files",
+	Long: `Print the JSON Schema of a config file kind, generated from the same
+types devenv uses to parse configs. Point your editor at it for completion
+and inline errors, or validate configs in CI without the devenv binary.
+
+Kinds:
+  developer     <developer>/devenv-config.yaml (default)
+  environment   <developer>/environments/<name>.yaml
+  global        devenv.yaml
+
+The schema covers structure, types, and per-field formats. Cross-field
+rules, port conflicts, and validation settings are only checked by
+devenv validate.
+
+Examples:
+  devenv schema > devenv-config.schema.json
+  devenv schema global -o devenv.schema.json`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: config.SchemaKinds,
+	Run: func(cmd *cobra.Command, args []string) {
+		kind := config.SchemaDeveloper
+		if len(args) > 0 {
+			kind = args[0]
+		}
+
+		schema, err := config.JSONSchema(kind)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding schema: %v\n", err)
+			os.Exit(1)
+		}
+		data = append(data, '\n')
+
+		if schemaOutput == "" {
+			os.Stdout.Write(data)
+			return
+		}
+		if err := os.WriteFile(schemaOutput, data, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing schema: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "✅ Wrote %s schema to %s\n", kind, schemaOutput)
+	},
+}
+
+func init() {
+	// Schema command specific flags
+	schemaCmd.Flags().StringVarP(&schemaOutput, "output", "o", "", "Write the schema to a file instead of stdout")
+}
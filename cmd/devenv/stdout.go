@@ -0,0 +1,44 @@
+package main
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"github.com/nauticalab/devenv-engine/internal/manifests"
+)
+
+// manifestStream receives the manifests in --stdout mode. Like --diff,
+// progress messages are redirected to stderr so stdout carries only YAML.
+var manifestStream io.Writer
+
+var (
+	// manifestStreamMu keeps the writes of concurrent batch workers apart
+	manifestStreamMu sync.Mutex
+	// streamedManifests counts the writes so far, which are separated by ---
+	streamedManifests int
+)
+
+// setupStdoutOutput redirects os.Stdout to stderr for the rest of the
+// command and sends manifests to the original stdout.
+func setupStdoutOutput() {
+	manifestStream = os.Stdout
+	os.Stdout = os.Stderr
+}
+
+// stdoutManifestWriter returns a write function that appends each rendered
+// manifest to the --stdout stream as one or more YAML documents.
+func stdoutManifestWriter() func(filename string, content []byte) error {
+	return func(_ string, content []byte) error {
+		manifestStreamMu.Lock()
+		defer manifestStreamMu.Unlock()
+		if streamedManifests > 0 {
+			if _, err := io.WriteString(manifestStream, "---\n"); err != nil {
+				return err
+			}
+		}
+		streamedManifests++
+		_, err := manifestStream.Write(manifests.Join(nil, [][]byte{content}))
+		return err
+	}
+}
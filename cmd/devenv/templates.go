@@ -0,0 +1,321 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/nauticalab/devenv-engine/internal/manifests"
+	"github.com/nauticalab/devenv-engine/internal/templates"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Templates dev command flags
+	templatesDevConfigDir   string
+	templatesDevTemplateDir string
+	templatesDevAddr        string
+	templatesDevEnv         string
+)
+
+// templatesCmd groups commands for template maintainers
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Tools for developing the manifest templates",
+}
+
+// templatesDevCmd represents the templates dev command
+var templatesDevCmd = &cobra.Command{
+	Use:   "dev <developer-name>",
+	Short: "Serve a developer's manifests, re-rendered whenever templates or configs change",
+	Long: `Render a developer's manifests from the template files on disk instead of
+the templates compiled into the binary, and serve them over HTTP.
+
+Whenever a template or a config file changes, the manifests are rendered
+again and the page in the browser reloads, showing each manifest, the diff
+against its previous render, and template errors with their position.
+Nothing is written to disk.
+
+--template-dir is the directory containing template_files/, i.e. the
+internal/templates directory of a devenv checkout.
+
+Examples:
+  devenv templates dev eywalker
+  devenv templates dev eywalker --env gpu --addr 127.0.0.1:9000`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		info, err := os.Stat(filepath.Join(templatesDevTemplateDir, "template_files"))
+		if err != nil || !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "Error: %s does not contain template_files/ (set --template-dir)\n", templatesDevTemplateDir)
+			os.Exit(1)
+		}
+
+		server := &templateDevServer{
+			developer: args[0],
+			files:     os.DirFS(templatesDevTemplateDir),
+			previous:  make(map[string][]byte),
+		}
+		server.render()
+
+		listener, err := net.Listen("tcp", templatesDevAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listening on %s: %v\n", templatesDevAddr, err)
+			os.Exit(1)
+		}
+		go func() {
+			if err := http.Serve(listener, server.handler()); err != nil {
+				fmt.Fprintf(os.Stderr, "Error serving HTTP: %v\n", err)
+				os.Exit(1)
+			}
+		}()
+		fmt.Printf("🌐 Serving %s's manifests at http://%s/\n", server.developer, listener.Addr())
+
+		roots := []string{templatesDevTemplateDir, templatesDevConfigDir}
+		if err := watchDirs(roots, "", func([]string) { server.render() }); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	templatesDevCmd.Flags().StringVar(&templatesDevConfigDir, "config-dir", "./developers", "Directory containing developer configuration files")
+	templatesDevCmd.Flags().StringVar(&templatesDevTemplateDir, "template-dir", "./internal/templates", "Directory containing template_files/")
+	templatesDevCmd.Flags().StringVar(&templatesDevAddr, "addr", "127.0.0.1:8800", "Address to serve the rendered manifests on")
+	templatesDevCmd.Flags().StringVar(&templatesDevEnv, "env", "", "Apply the developer's named environment (environments/<name>.yaml)")
+
+	templatesCmd.AddCommand(templatesDevCmd)
+}
+
+// renderedManifest is the result of rendering one template.
+type renderedManifest struct {
+	Name    string // Output filename, e.g. "statefulset.yaml"
+	Content string
+	Error   string
+	Excerpt string // Template lines around the error, if it has a position
+	Diff    string // Against the previous successful render, empty if unchanged
+}
+
+// templateDevServer renders a developer's manifests from template files on
+// disk and serves the latest results.
+type templateDevServer struct {
+	developer string
+	files     fs.FS
+
+	mu         sync.Mutex
+	version    int // Incremented on every render so pages know to reload
+	renderedAt time.Time
+	loadError  string
+	manifests  []renderedManifest
+	previous   map[string][]byte // Last successful render per filename
+}
+
+// render loads the configs and renders every template again, keeping the
+// previous output of each manifest to diff against.
+func (s *templateDevServer) render() {
+	startTime := time.Now()
+	var results []renderedManifest
+	loadError := ""
+
+	globalConfig, err := config.LoadGlobalConfig(templatesDevConfigDir)
+	if err == nil {
+		loadDataSources(templatesDevConfigDir, globalConfig)
+		var cfg *config.DevEnvConfig
+		if templatesDevEnv != "" {
+			cfg, err = config.LoadDeveloperEnvironment(templatesDevConfigDir, s.developer, templatesDevEnv, globalConfig)
+		} else {
+			cfg, err = config.LoadDeveloperConfigWithBaseConfig(templatesDevConfigDir, s.developer, globalConfig)
+		}
+		if err == nil {
+			systemRenderer := templates.NewSystemRenderer("")
+			setupRenderer(systemRenderer)
+			systemRenderer.SetFS(s.files)
+			results = append(results, renderEachTemplate(systemRenderer, globalConfig)...)
+
+			devRenderer := templates.NewDevRenderer("")
+			setupRenderer(devRenderer)
+			devRenderer.SetFS(s.files)
+			results = append(results, renderEachTemplate(devRenderer, cfg)...)
+		}
+	}
+	if err != nil {
+		loadError = err.Error()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	failed := 0
+	for i := range results {
+		result := &results[i]
+		if result.Error != "" {
+			failed++
+			continue
+		}
+		if previous, ok := s.previous[result.Name]; ok {
+			result.Diff = manifests.UnifiedDiff("previous/"+result.Name, "current/"+result.Name, previous, []byte(result.Content))
+		}
+		s.previous[result.Name] = []byte(result.Content)
+	}
+	s.version++
+	s.renderedAt = time.Now()
+	s.loadError = loadError
+	s.manifests = results
+
+	stamp := s.renderedAt.Format("15:04:05")
+	switch {
+	case loadError != "":
+		fmt.Printf("[%s] ❌ %s\n", stamp, loadError)
+	case failed > 0:
+		fmt.Printf("[%s] ❌ %d of %d templates failed\n", stamp, failed, len(results))
+	default:
+		fmt.Printf("[%s] ✅ rendered %d templates (%.1fs)\n", stamp, len(results), time.Since(startTime).Seconds())
+	}
+}
+
+// renderEachTemplate renders every template of renderer, recording
+// errors per template instead of stopping.
+func renderEachTemplate[T config.BaseConfig | config.DevEnvConfig](renderer *templates.Renderer[T], cfg *T) []renderedManifest {
+	var results []renderedManifest
+	for _, templateName := range renderer.Templates() {
+		result := renderedManifest{Name: templates.OutputFilename(templateName)}
+		content, err := renderer.RenderToBytes(templateName, cfg)
+		if err != nil {
+			result.Error = err.Error()
+			if line, column, ok := templates.ErrorPosition(err, templateName); ok {
+				source, _ := renderer.TemplateSource(templateName)
+				result.Excerpt = templateExcerpt(source, line, column)
+			}
+		}
+		result.Content = string(content)
+		results = append(results, result)
+	}
+	return results
+}
+
+// handler serves the manifest page, each manifest as plain text, and the
+// render version polled by the page to reload itself.
+func (s *templateDevServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err := templateDevPage.Execute(w, map[string]any{
+			"Developer":   s.developer,
+			"Environment": templatesDevEnv,
+			"Version":     s.version,
+			"RenderedAt":  s.renderedAt.Format("15:04:05"),
+			"LoadError":   s.loadError,
+			"Manifests":   s.manifests,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Rendering page: %v\n", err)
+		}
+	})
+	mux.HandleFunc("GET /version", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, s.version)
+	})
+	mux.HandleFunc("GET /manifests/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		manifest, err := s.find(r.PathValue("name"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if manifest.Error != "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintln(w, manifest.Error)
+			return
+		}
+		fmt.Fprint(w, manifest.Content)
+	})
+	return mux
+}
+
+// find returns the latest render of a manifest. The caller holds s.mu.
+func (s *templateDevServer) find(name string) (renderedManifest, error) {
+	for _, manifest := range s.manifests {
+		if manifest.Name == name {
+			return manifest, nil
+		}
+	}
+	return renderedManifest{}, errors.New("no manifest named " + name)
+}
+
+// diffLineClass returns the CSS class of a unified diff line.
+func diffLineClass(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		return "file"
+	case strings.HasPrefix(line, "@@"):
+		return "hunk"
+	case strings.HasPrefix(line, "+"):
+		return "add"
+	case strings.HasPrefix(line, "-"):
+		return "del"
+	default:
+		return ""
+	}
+}
+
+// templateDevPage lists every manifest with its error, diff and content.
+// It polls /version and reloads when a new render is available.
+var templateDevPage = template.Must(template.New("page").Funcs(template.FuncMap{
+	"lines":     func(s string) []string { return strings.Split(strings.TrimSuffix(s, "\n"), "\n") },
+	"lineClass": diffLineClass,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>devenv templates: {{.Developer}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+pre { background: #f6f8fa; padding: 1em; overflow-x: auto; }
+.error { color: #b31d28; }
+.add { background: #e6ffed; }
+.del { background: #ffeef0; }
+.hunk { color: #6f42c1; }
+.file { font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{.Developer}}{{with .Environment}} ({{.}}){{end}}</h1>
+<p>Render #{{.Version}} at {{.RenderedAt}}. This page reloads when templates or configs change.</p>
+{{with .LoadError}}<pre class="error">{{.}}</pre>{{end}}
+{{range .Manifests}}
+<h2 id="{{.Name}}">{{.Name}} {{if .Error}}❌{{else if .Diff}}📝{{else}}✅{{end}}</h2>
+{{if .Error}}
+<pre class="error">{{.Error}}</pre>
+{{with .Excerpt}}<pre>{{.}}</pre>{{end}}
+{{else}}
+{{with .Diff}}<pre>{{range lines .}}<span class="{{lineClass .}}">{{.}}</span>
+{{end}}</pre>{{end}}
+<details><summary>Manifest (<a href="/manifests/{{.Name}}">raw</a>)</summary><pre>{{.Content}}</pre></details>
+{{end}}
+{{end}}
+<script>
+const version = "{{.Version}}";
+setInterval(async () => {
+  try {
+    const response = await fetch("/version");
+    if ((await response.text()) !== version) location.reload();
+  } catch (e) {}
+}, 1000);
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/nauticalab/devenv-engine/internal/manifests"
+	"github.com/nauticalab/devenv-engine/internal/templates"
+	"github.com/nauticalab/devenv-engine/internal/validation"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Test command flags
+	testConfigDir string
+	goldenDir     string
+	policyDir     string
+	updateGolden  bool
+)
+
+// policyTimeout bounds a single policy hook run.
+const policyTimeout = time.Minute
+
+// Golden comparison outcomes reported per target.
+const (
+	goldenMatch    = "match"
+	goldenMismatch = "mismatch"
+	goldenMissing  = "missing" // No golden files committed; not a failure
+	goldenUpdated  = "updated"
+)
+
+// testCmd represents the test command
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Test a config repository: validate, render, check manifests and compare with goldens",
+	Long: `Test every configuration in a config repository, as a single CI entrypoint.
+
+The command:
+- Runs the full validate pipeline for all developers
+- Renders the system manifests, every developer and every named environment in memory
+- Checks each rendered manifest against the Kubernetes object schema
+  (kinds, apiVersions, names, labels, ConfigMap data)
+- Runs each executable in the policy directory with the rendered manifests
+  on stdin; a non-zero exit fails the target
+- Compares the manifests with committed golden outputs when present
+
+Golden outputs use the layout of 'devenv generate' output: system manifests
+at the root, one directory per developer and '<developer>-<environment>' per
+environment. Use --update-golden to write them.
+
+Examples:
+  devenv test
+  devenv test --config-dir ./developers --golden-dir ./golden
+  devenv test --update-golden`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := setupOutputFormat(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		report := runConfigTests()
+		if jsonMode() {
+			writeJSON(report)
+		}
+		if !report.Success {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	// Test command specific flags
+	testCmd.Flags().StringVar(&testConfigDir, "config-dir", "./developers", "Directory containing developer configuration files")
+	testCmd.Flags().StringVar(&goldenDir, "golden-dir", "./golden", "Directory containing committed golden manifests")
+	testCmd.Flags().StringVar(&policyDir, "policy-dir", "./policies", "Directory containing executable policy hooks")
+	testCmd.Flags().BoolVar(&updateGolden, "update-golden", false, "Write rendered manifests to the golden directory instead of comparing")
+	testCmd.Flags().StringVar(&outputFormat, "output-format", outputFormatText, "Output format for results: text or json (progress goes to stderr in json mode)")
+}
+
+// testTarget is one set of manifests rendered together: the system
+// manifests, a developer, or a developer's named environment.
+type testTarget struct {
+	Name        string // Golden subdirectory; empty for the system manifests
+	Developer   string
+	Environment string
+}
+
+// displayName returns the name shown in reports.
+func (t testTarget) displayName() string {
+	if t.Name == "" {
+		return "system"
+	}
+	return t.Name
+}
+
+// testResult is the outcome of testing one target.
+type testResult struct {
+	Target      string   `json:"target"`
+	Developer   string   `json:"developer,omitempty"`
+	Environment string   `json:"environment,omitempty"`
+	Success     bool     `json:"success"`
+	Golden      string   `json:"golden,omitempty"`
+	Failures    []string `json:"failures,omitempty"`
+}
+
+// testReport is the document emitted by test with --output-format json.
+type testReport struct {
+	Success    bool           `json:"success"`
+	Error      string         `json:"error,omitempty"` // Fatal error that stopped the run
+	Validation validateReport `json:"validation"`
+	Results    []testResult   `json:"results"`
+}
+
+// runConfigTests validates the config repository and tests every target.
+func runConfigTests() testReport {
+	report := testReport{Results: []testResult{}}
+	fail := func(format string, args ...any) testReport {
+		report.Error = fmt.Sprintf(format, args...)
+		fmt.Fprintf(os.Stderr, "❌ %s\n", report.Error)
+		return report
+	}
+
+	fmt.Printf("🧪 Testing configurations in %s...\n", testConfigDir)
+
+	globalConfig, err := config.LoadGlobalConfig(testConfigDir)
+	if err != nil {
+		return fail("Error loading global config in %s: %v", testConfigDir, err)
+	}
+	loadDataSources(testConfigDir, globalConfig)
+	developers, err := config.ListDeveloperDirs(testConfigDir)
+	if err != nil {
+		return fail("Error discovering developers: %v", err)
+	}
+	hooks, err := manifests.ListPolicyHooks(policyDir)
+	if err != nil {
+		return fail("Error reading policy hooks in %s: %v", policyDir, err)
+	}
+
+	// Step 1: Validate exactly like devenv validate
+	result, err := validateConfigRepository(testConfigDir)
+	if err != nil {
+		return fail("Validation failed: %v", err)
+	}
+	report.Validation = newValidateReport(result, developers)
+	if !jsonMode() {
+		printValidationResult(result, "", developers)
+	}
+
+	// Step 2: Render and check every target
+	targets := []testTarget{{}}
+	for _, developer := range developers {
+		targets = append(targets, testTarget{Name: developer, Developer: developer})
+		environments, err := config.ListEnvironments(testConfigDir, developer)
+		if err != nil {
+			return fail("Error listing environments of %s: %v", developer, err)
+		}
+		for _, environment := range environments {
+			targets = append(targets, testTarget{
+				Name:        developer + "-" + environment,
+				Developer:   developer,
+				Environment: environment,
+			})
+		}
+	}
+
+	fmt.Printf("\nTesting %d targets (%d policy hooks)...\n", len(targets), len(hooks))
+	report.Success = result.IsValid
+	var failed int
+	for _, target := range targets {
+		targetResult := testConfigTarget(target, globalConfig, hooks)
+		report.Results = append(report.Results, targetResult)
+		if !targetResult.Success {
+			report.Success = false
+			failed++
+		}
+		printTestResult(targetResult)
+	}
+
+	fmt.Println()
+	switch {
+	case failed > 0:
+		fmt.Printf("❌ %d of %d targets failed\n", failed, len(targets))
+	case !result.IsValid:
+		fmt.Println("❌ All targets passed, but validation failed")
+	default:
+		fmt.Printf("✅ All %d targets passed\n", len(targets))
+	}
+	return report
+}
+
+// validateConfigRepository runs every validator over all developers.
+func validateConfigRepository(configDir string) (*validation.ValidationResult, error) {
+	result := &validation.ValidationResult{
+		Errors:   []validation.ValidationError{},
+		Warnings: []validation.ValidationWarning{},
+		IsValid:  true,
+	}
+	for _, validate := range []func() (*validation.ValidationResult, error){
+		validation.NewConfigValidator(configDir).ValidateAll,
+		validation.NewPortValidator(configDir).ValidateAll,
+		validation.NewNameValidator(configDir).ValidateAll,
+	} {
+		partial, err := validate()
+		if err != nil {
+			return nil, err
+		}
+		result.Merge(partial)
+	}
+	return result, nil
+}
+
+// testConfigTarget renders a target in memory, checks the manifests, runs
+// the policy hooks, and compares the manifests with the golden files.
+func testConfigTarget(target testTarget, globalConfig *config.BaseConfig, hooks []string) testResult {
+	result := testResult{
+		Target:      target.displayName(),
+		Developer:   target.Developer,
+		Environment: target.Environment,
+	}
+
+	files, err := renderTestTarget(target, globalConfig)
+	if err != nil {
+		result.Failures = []string{err.Error()}
+		return result
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, problem := range manifests.Check(name, files[name]) {
+			result.Failures = append(result.Failures, problem.Error())
+		}
+	}
+
+	stream := manifests.Stream(files)
+	env := []string{
+		"DEVENV_TARGET=" + target.displayName(),
+		"DEVENV_DEVELOPER=" + target.Developer,
+		"DEVENV_ENVIRONMENT=" + target.Environment,
+	}
+	for _, hook := range hooks {
+		ctx, cancel := context.WithTimeout(context.Background(), policyTimeout)
+		err := manifests.RunPolicyHook(ctx, hook, stream, env)
+		cancel()
+		if err != nil {
+			result.Failures = append(result.Failures, err.Error())
+		}
+	}
+
+	dir := filepath.Join(goldenDir, target.Name)
+	if updateGolden {
+		if err := manifests.WriteGolden(dir, files); err != nil {
+			result.Failures = append(result.Failures, err.Error())
+		} else {
+			result.Golden = goldenUpdated
+		}
+	} else {
+		golden, err := manifests.ReadGolden(dir)
+		switch {
+		case err != nil:
+			result.Failures = append(result.Failures, fmt.Sprintf("failed to read golden files: %v", err))
+		case len(golden) == 0:
+			result.Golden = goldenMissing
+		default:
+			diffs := manifests.DiffGolden(golden, files)
+			result.Failures = append(result.Failures, diffs...)
+			result.Golden = goldenMatch
+			if len(diffs) > 0 {
+				result.Golden = goldenMismatch
+			}
+		}
+	}
+
+	result.Success = len(result.Failures) == 0
+	return result
+}
+
+// renderTestTarget loads the config for target and renders its manifests
+// into memory, keyed by filename.
+func renderTestTarget(target testTarget, globalConfig *config.BaseConfig) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	collect := func(filename string, content []byte) error {
+		files[filename] = content
+		return nil
+	}
+
+	if target.Developer == "" {
+		renderer := templates.NewSystemRenderer("")
+		setupRenderer(renderer)
+		if err := renderer.RenderAllTo(globalConfig, collect); err != nil {
+			return nil, err
+		}
+		return files, nil
+	}
+
+	var cfg *config.DevEnvConfig
+	var err error
+	if target.Environment != "" {
+		cfg, err = config.LoadDeveloperEnvironment(testConfigDir, target.Developer, target.Environment, globalConfig)
+	} else {
+		cfg, err = config.LoadDeveloperConfigWithBaseConfig(testConfigDir, target.Developer, globalConfig)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	renderer := templates.NewDevRenderer("")
+	setupRenderer(renderer)
+	renderer.SetExtras(os.DirFS(filepath.Join(cfg.DeveloperDir, templates.ExtrasDir)))
+	if err := renderer.RenderAllTo(cfg, collect); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// printTestResult prints the outcome of one target.
+func printTestResult(result testResult) {
+	golden := ""
+	if result.Golden != "" {
+		golden = fmt.Sprintf(" (golden: %s)", result.Golden)
+	}
+	if result.Success {
+		fmt.Printf("✅ %s%s\n", result.Target, golden)
+		return
+	}
+	fmt.Printf("❌ %s%s\n", result.Target, golden)
+	for _, failure := range result.Failures {
+		fmt.Printf("   ❌ %s\n", failure)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/nauticalab/devenv-engine/internal/config"
 	"github.com/nauticalab/devenv-engine/internal/validation"
 	"github.com/spf13/cobra"
 )
@@ -11,34 +12,52 @@ import (
 var (
 	// Validate command flags
 	validateConfigDir string
+	validateAllDevs   bool
 )
 
 // validateCmd represents the validate command
 var validateCmd = &cobra.Command{
-	Use:   "validate [developer-name]",
+	Use:   "validate [developer-name|--all]",
 	Short: "Validate developer environment configurations",
-	Long: `Validate developer environment configurations for common issues.
+	Long: `Validate developer environment configurations without generating manifests.
 
-This command checks for:
+Each developer config is loaded and merged with devenv.yaml exactly as
+generate does, then checked for:
+- Schema errors (types, required fields, formats)
+- SSH keys, resources, and cross-field rules (e.g., httpPort requires hostName)
 - SSH port conflicts between developers
 - SSH ports outside valid NodePort range (30000-32767)
+- Developer names that collide case-insensitively
 - Missing or invalid configuration files
 
+Results are grouped per developer. The command exits non-zero if any
+error is found; warnings alone do not fail validation.
+
 Examples:
-  devenv validate                    # Validate all configurations
+  devenv validate --all              # Validate all configurations
   devenv validate eywalker          # Validate specific developer (includes conflict checking)
   devenv validate --config-dir ./configs`,
 	Args: cobra.MaximumNArgs(1), // At most 1 argument (developer name)
 	Run: func(cmd *cobra.Command, args []string) {
-		validator := validation.NewPortValidator(validateConfigDir)
+		if validateAllDevs && len(args) > 0 {
+			fmt.Fprintf(os.Stderr, "error: Cannot specify developer name with --all flag\n")
+			os.Exit(1)
+		}
+
+		if err := setupOutputFormat(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		validators := newValidatorSet(validateConfigDir)
 
 		if len(args) == 0 {
-			// Validate all developers
-			validateAll(validator)
+			// Validate all developers (the default without arguments)
+			validateAll(validators)
 		} else {
 			// Validate single developer (with conflict checking)
 			developerName := args[0]
-			validateSingle(validator, developerName)
+			validateSingle(validators, developerName)
 		}
 	},
 }
@@ -46,19 +65,77 @@ Examples:
 func init() {
 	// Validate command specific flags
 	validateCmd.Flags().StringVar(&validateConfigDir, "config-dir", "./developers", "Directory containing developer configuration files")
+	validateCmd.Flags().BoolVar(&validateAllDevs, "all", false, "Validate all developers (default when no developer name is given)")
+	validateCmd.Flags().StringVar(&outputFormat, "output-format", outputFormatText, "Output format for results: text or json (progress goes to stderr in json mode)")
+}
+
+// validatorSet bundles the validators that make up the full pipeline.
+type validatorSet struct {
+	configs *validation.ConfigValidator
+	ports   *validation.PortValidator
+	names   *validation.NameValidator
+}
+
+// newValidatorSet returns the full validation pipeline for configDir.
+func newValidatorSet(configDir string) validatorSet {
+	return validatorSet{
+		configs: validation.NewConfigValidator(configDir),
+		ports:   validation.NewPortValidator(configDir),
+		names:   validation.NewNameValidator(configDir),
+	}
+}
+
+// validateSingle runs every validator for one developer, including
+// conflicts with other developers, and merges their results.
+func (v validatorSet) validateSingle(developerName string) (*validation.ValidationResult, error) {
+	result := &validation.ValidationResult{
+		Errors:   []validation.ValidationError{},
+		Warnings: []validation.ValidationWarning{},
+		IsValid:  true,
+	}
+	for _, validate := range []func(string) (*validation.ValidationResult, error){
+		v.configs.ValidateSingle,
+		v.ports.ValidateSingle,
+		v.names.ValidateSingle,
+	} {
+		partial, err := validate(developerName)
+		if err != nil {
+			return nil, err
+		}
+		result.Merge(partial)
+	}
+	return result, nil
 }
 
 // validateAll validates all developer configurations
-func validateAll(validator *validation.PortValidator) {
+func validateAll(validators validatorSet) {
 	fmt.Println("🔍 Validating all developer configurations...")
 
-	result, err := validator.ValidateAll()
+	developers, err := config.ListDeveloperDirs(validateConfigDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Validation failed: %v\n", err)
 		os.Exit(1)
 	}
 
-	printValidationResult(result, "")
+	result := &validation.ValidationResult{
+		Errors:   []validation.ValidationError{},
+		Warnings: []validation.ValidationWarning{},
+		IsValid:  true,
+	}
+	for _, validate := range []func() (*validation.ValidationResult, error){
+		validators.configs.ValidateAll,
+		validators.ports.ValidateAll,
+		validators.names.ValidateAll,
+	} {
+		partial, err := validate()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Validation failed: %v\n", err)
+			os.Exit(1)
+		}
+		result.Merge(partial)
+	}
+
+	reportValidationResult(result, "", developers)
 
 	if !result.IsValid {
 		os.Exit(1)
@@ -66,61 +143,169 @@ func validateAll(validator *validation.PortValidator) {
 }
 
 // validateSingle validates a single developer configuration (including conflicts)
-func validateSingle(validator *validation.PortValidator, developerName string) {
+func validateSingle(validators validatorSet, developerName string) {
 	fmt.Printf("🔍 Validating configuration for developer: %s\n", developerName)
 
-	result, err := validator.ValidateSingle(developerName)
+	result, err := validators.validateSingle(developerName)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Validation failed: %v\n", err)
 		os.Exit(1)
 	}
 
-	printValidationResult(result, developerName)
+	reportValidationResult(result, developerName, []string{developerName})
 
 	if !result.IsValid {
 		os.Exit(1)
 	}
 }
 
-// printValidationResult prints the validation results in a user-friendly format
-func printValidationResult(result *validation.ValidationResult, targetUser string) {
-	// Print warnings first
-	for _, warning := range result.Warnings {
-		fmt.Printf("⚠️  Warning: %s\n", warning.Message)
-		if warning.FilePath != "" && verbose {
-			fmt.Printf("   File: %s\n", warning.FilePath)
-		}
+// developerValidation is the per-developer summary of a validation run.
+type developerValidation struct {
+	Developer string                         `json:"developer"`
+	Valid     bool                           `json:"valid"`
+	Errors    []validation.ValidationError   `json:"errors"`
+	Warnings  []validation.ValidationWarning `json:"warnings"`
+}
+
+// validateReport is the document emitted by validate with --output-format json.
+// Errors and Warnings hold every finding; Developers groups them per developer.
+type validateReport struct {
+	*validation.ValidationResult
+	Developers []developerValidation `json:"developers"`
+}
+
+// validationGroups splits a result into global, per-developer, and
+// cross-developer findings.
+type validationGroups struct {
+	globalErrors     []validation.ValidationError
+	globalWarnings   []validation.ValidationWarning
+	sharedErrors     []validation.ValidationError
+	errorsByUser     map[string][]validation.ValidationError
+	warningsByUser   map[string][]validation.ValidationWarning
+	involvedInShared map[string]bool
+}
+
+func groupValidationResult(result *validation.ValidationResult) validationGroups {
+	groups := validationGroups{
+		errorsByUser:     make(map[string][]validation.ValidationError),
+		warningsByUser:   make(map[string][]validation.ValidationWarning),
+		involvedInShared: make(map[string]bool),
 	}
 
-	// Print errors with context-specific messaging
 	for _, err := range result.Errors {
-		switch err.Type {
-		case "conflict":
-			if targetUser != "" {
-				// Single user validation - show from their perspective
-				fmt.Printf("❌ Port Conflict: %s\n", err.Message)
-			} else {
-				// All users validation - show general conflict
-				fmt.Printf("❌ Port Conflict: Port %d is assigned to multiple developers: %v\n", err.Port, err.Users)
-			}
-			if verbose {
-				fmt.Printf("   Affected users: %v\n", err.Users)
-			}
-		case "out_of_range":
-			fmt.Printf("❌ Invalid Port Range: %s\n", err.Message)
-			if verbose && err.FilePath != "" {
-				fmt.Printf("   File: %s\n", err.FilePath)
+		switch len(err.Users) {
+		case 0:
+			groups.globalErrors = append(groups.globalErrors, err)
+		case 1:
+			groups.errorsByUser[err.Users[0]] = append(groups.errorsByUser[err.Users[0]], err)
+		default:
+			groups.sharedErrors = append(groups.sharedErrors, err)
+			for _, user := range err.Users {
+				groups.involvedInShared[user] = true
 			}
-		case "invalid":
-			fmt.Printf("❌ Configuration Error: %s\n", err.Message)
-			if verbose && err.FilePath != "" {
-				fmt.Printf("   File: %s\n", err.FilePath)
+		}
+	}
+	for _, warning := range result.Warnings {
+		if warning.User == "" {
+			groups.globalWarnings = append(groups.globalWarnings, warning)
+		} else {
+			groups.warningsByUser[warning.User] = append(groups.warningsByUser[warning.User], warning)
+		}
+	}
+	return groups
+}
+
+// reportValidationResult writes the result in the selected output format.
+func reportValidationResult(result *validation.ValidationResult, targetUser string, developers []string) {
+	if !jsonMode() {
+		printValidationResult(result, targetUser, developers)
+		return
+	}
+
+	writeJSON(newValidateReport(result, developers))
+}
+
+// newValidateReport builds the JSON document for a validation result with a
+// summary for each of developers.
+func newValidateReport(result *validation.ValidationResult, developers []string) validateReport {
+	groups := groupValidationResult(result)
+	report := validateReport{ValidationResult: result, Developers: []developerValidation{}}
+	for _, developer := range developers {
+		summary := developerValidation{
+			Developer: developer,
+			Errors:    groups.errorsByUser[developer],
+			Warnings:  groups.warningsByUser[developer],
+		}
+		// Cross-developer errors (e.g., port conflicts) count against each developer involved
+		for _, err := range groups.sharedErrors {
+			for _, user := range err.Users {
+				if user == developer {
+					summary.Errors = append(summary.Errors, err)
+				}
 			}
+		}
+		if summary.Errors == nil {
+			summary.Errors = []validation.ValidationError{}
+		}
+		if summary.Warnings == nil {
+			summary.Warnings = []validation.ValidationWarning{}
+		}
+		summary.Valid = len(summary.Errors) == 0
+		report.Developers = append(report.Developers, summary)
+	}
+	return report
+}
+
+// printValidationResult prints the validation results grouped per developer,
+// followed by global and cross-developer issues and a summary.
+func printValidationResult(result *validation.ValidationResult, targetUser string, developers []string) {
+	groups := groupValidationResult(result)
+	errorsByUser, warningsByUser := groups.errorsByUser, groups.warningsByUser
+	globalErrors, globalWarnings, sharedErrors := groups.globalErrors, groups.globalWarnings, groups.sharedErrors
+	involvedInShared := groups.involvedInShared
+
+	// Print global findings first (devenv.yaml, empty config directory)
+	for _, warning := range globalWarnings {
+		printValidationWarning(warning, "")
+	}
+	for _, err := range globalErrors {
+		printValidationError(err, targetUser, "")
+	}
+
+	// Per-developer report
+	for _, developer := range developers {
+		errs, warnings := errorsByUser[developer], warningsByUser[developer]
+		switch {
+		case len(errs) > 0 || involvedInShared[developer]:
+			fmt.Printf("\n❌ %s\n", developer)
+		case len(warnings) > 0:
+			fmt.Printf("\n⚠️  %s\n", developer)
 		default:
-			fmt.Printf("❌ Error: %s\n", err.Message)
+			if targetUser == "" {
+				fmt.Printf("✅ %s\n", developer)
+			}
+			continue
+		}
+		for _, warning := range warnings {
+			printValidationWarning(warning, "   ")
+		}
+		for _, err := range errs {
+			printValidationError(err, targetUser, "   ")
+		}
+		if involvedInShared[developer] {
+			fmt.Println("   ❌ Involved in cross-developer issues (see below)")
 		}
 	}
 
+	if len(sharedErrors) > 0 {
+		fmt.Println("\nCross-developer issues:")
+		for _, err := range sharedErrors {
+			printValidationError(err, targetUser, "   ")
+		}
+	}
+
+	fmt.Println()
+
 	// Print summary
 	if len(result.Errors) == 0 && len(result.Warnings) == 0 {
 		if targetUser != "" {
@@ -141,7 +326,10 @@ func printValidationResult(result *validation.ValidationResult, targetUser strin
 		if len(result.Errors) > 0 {
 			fmt.Println("\n💡 Suggestions:")
 			hasConflicts := false
+			hasReserved := false
 			hasRangeErrors := false
+			hasNameCollisions := false
+			hasConfigErrors := false
 
 			for _, err := range result.Errors {
 				if err.Type == "conflict" && !hasConflicts {
@@ -150,14 +338,71 @@ func printValidationResult(result *validation.ValidationResult, targetUser strin
 					} else {
 						fmt.Println("   • Assign unique SSH ports to each developer")
 					}
-					fmt.Printf("   • Valid port range: %d-%d\n", validation.NodePortMin, validation.NodePortMax)
+					fmt.Printf("   • Valid port range: %d-%d; devenv ports list shows the ports in use\n", validation.NodePortMin, validation.NodePortMax)
 					hasConflicts = true
 				}
+				if err.Type == "reserved" && !hasReserved {
+					fmt.Println("   • Remove the reserved sshPort and run devenv ports assign <developer> to pick a free one")
+					hasReserved = true
+				}
 				if err.Type == "out_of_range" && !hasRangeErrors {
 					fmt.Printf("   • Use ports between %d and %d (Kubernetes NodePort range)\n", validation.NodePortMin, validation.NodePortMax)
 					hasRangeErrors = true
 				}
+				if err.Type == "name_collision" && !hasNameCollisions {
+					fmt.Println("   • Give each developer and environment a unique lowercase name; names are compared case-insensitively")
+					hasNameCollisions = true
+				}
+				if err.Type == "config" && !hasConfigErrors {
+					fmt.Println("   • Rules shown in [brackets] can be tuned in devenv.yaml via validation.disabledRules or validation.warnRules")
+					hasConfigErrors = true
+				}
 			}
 		}
 	}
 }
+
+// printValidationWarning prints a single warning with the given indentation.
+func printValidationWarning(warning validation.ValidationWarning, indent string) {
+	if warning.Rule != "" {
+		fmt.Printf("%s⚠️  Warning: %s [%s]\n", indent, warning.Message, warning.Rule)
+	} else {
+		fmt.Printf("%s⚠️  Warning: %s\n", indent, warning.Message)
+	}
+	if warning.FilePath != "" && verbose {
+		fmt.Printf("%s   File: %s\n", indent, warning.FilePath)
+	}
+}
+
+// printValidationError prints a single error with context-specific messaging.
+func printValidationError(err validation.ValidationError, targetUser, indent string) {
+	switch err.Type {
+	case "conflict":
+		// The message names the environments involved, not just the developers
+		fmt.Printf("%s❌ Port Conflict: %s\n", indent, err.Message)
+		if verbose {
+			fmt.Printf("%s   Affected users: %v\n", indent, err.Users)
+		}
+	case "reserved":
+		fmt.Printf("%s❌ Reserved Port: %s\n", indent, err.Message)
+	case "out_of_range":
+		fmt.Printf("%s❌ Invalid Port Range: %s\n", indent, err.Message)
+		if verbose && err.FilePath != "" {
+			fmt.Printf("%s   File: %s\n", indent, err.FilePath)
+		}
+	case "name_collision":
+		fmt.Printf("%s❌ Name Collision: %s\n", indent, err.Message)
+	case "config":
+		fmt.Printf("%s❌ %s [%s]\n", indent, err.Message, err.Rule)
+		if verbose && err.FilePath != "" {
+			fmt.Printf("%s   File: %s\n", indent, err.FilePath)
+		}
+	case "invalid":
+		fmt.Printf("%s❌ Configuration Error: %s\n", indent, err.Message)
+		if verbose && err.FilePath != "" {
+			fmt.Printf("%s   File: %s\n", indent, err.FilePath)
+		}
+	default:
+		fmt.Printf("%s❌ Error: %s\n", indent, err.Message)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"runtime/debug"
 
 	"github.com/spf13/cobra"
 )
@@ -20,3 +21,38 @@ var versionCmd = &cobra.Command{
 		}
 	},
 }
+
+func init() {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		applyBuildInfo(info)
+	}
+}
+
+// applyBuildInfo fills the build-time variables that were not set with
+// -ldflags from the build info the Go toolchain embeds, so binaries built
+// with plain go build or go install still report their module version,
+// VCS revision and Go version. A revision built from a modified checkout
+// is marked "-dirty".
+func applyBuildInfo(info *debug.BuildInfo) {
+	if version == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		version = info.Main.Version
+	}
+	if goVersion == "unknown" && info.GoVersion != "" {
+		goVersion = info.GoVersion
+	}
+
+	settings := make(map[string]string, len(info.Settings))
+	for _, setting := range info.Settings {
+		settings[setting.Key] = setting.Value
+	}
+	if gitCommit == "unknown" && settings["vcs.revision"] != "" {
+		gitCommit = settings["vcs.revision"]
+		if settings["vcs.modified"] == "true" {
+			gitCommit += "-dirty"
+		}
+	}
+	if buildTime == "unknown" && settings["vcs.time"] != "" {
+		// The commit time; the actual build time is only known to -ldflags
+		buildTime = settings["vcs.time"] + " (commit)"
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/nauticalab/devenv-engine/internal/config"
+)
+
+// watchDebounce is how long watch mode waits after the last change before
+// regenerating, so an editor's multi-step save triggers a single run.
+const watchDebounce = 200 * time.Millisecond
+
+// globalChange marks a change to devenv.yaml in the set of changed targets.
+const globalChange = ""
+
+// watchSession holds the state of a generate --watch run.
+type watchSession struct {
+	developer    string // Only this developer is regenerated; empty for all developers
+	globalConfig *config.BaseConfig
+}
+
+// watchConfigDir regenerates manifests whenever files in the config
+// directory change, until interrupted. Errors are printed and watching
+// continues, so a config can be fixed and saved again.
+func watchConfigDir(developerName string) {
+	session := &watchSession{developer: developerName}
+	var err error
+	session.globalConfig, err = config.LoadGlobalConfig(configDir)
+	if err != nil {
+		fmt.Printf("❌ devenv.yaml: %v\n", err)
+	}
+
+	err = watchDirs([]string{configDir}, outputDir, func(paths []string) {
+		changed := make(map[string]bool)
+		for _, path := range paths {
+			if target, ok := watchTarget(path); ok {
+				changed[target] = true
+			}
+		}
+		if len(changed) > 0 {
+			session.regenerate(changed)
+		}
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error watching %s: %v\n", configDir, err)
+		os.Exit(1)
+	}
+}
+
+// watchDirs watches roots and the directories below them, except skipDir,
+// and calls onChange with the paths changed since the last call once no
+// change has been seen for watchDebounce. It returns when interrupted.
+// Editor swap and backup files are ignored.
+func watchDirs(roots []string, skipDir string, onChange func(paths []string)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	for _, root := range roots {
+		if err := addWatchDirs(watcher, root, skipDir); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("\n👀 Watching %s for changes (Ctrl-C to stop)...\n", strings.Join(roots, ", "))
+
+	changed := make(map[string]bool)
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println("\n👋 Stopped watching")
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// New directories (e.g., a new developer) are not watched automatically
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := addWatchDirs(watcher, event.Name, skipDir); err != nil {
+						fmt.Fprintf(os.Stderr, "⚠️  Cannot watch %s: %v\n", event.Name, err)
+					}
+				}
+			}
+			if !isEditorFile(event.Name) {
+				changed[event.Name] = true
+				debounce = time.After(watchDebounce)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "⚠️  Watch error: %v\n", err)
+
+		case <-debounce:
+			paths := make([]string, 0, len(changed))
+			for path := range changed {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			onChange(paths)
+			changed = make(map[string]bool)
+			debounce = nil
+		}
+	}
+}
+
+// addWatchDirs watches root and every directory below it, skipping hidden
+// directories and skipDir (e.g., the output directory, so generated files
+// never trigger another run). An empty skipDir skips nothing.
+func addWatchDirs(watcher *fsnotify.Watcher, root, skipDir string) error {
+	var absSkip string
+	if skipDir != "" {
+		absSkip, _ = filepath.Abs(skipDir)
+	}
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if abs, _ := filepath.Abs(path); absSkip != "" && abs == absSkip {
+			return filepath.SkipDir
+		}
+		if path != root && strings.HasPrefix(entry.Name(), ".") {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// isEditorFile reports whether path is a hidden, swap or backup file that
+// editors write while saving.
+func isEditorFile(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasPrefix(base, ".") || strings.HasSuffix(base, "~") || strings.HasSuffix(base, ".swp")
+}
+
+// watchTarget maps a changed path to the developer whose manifests it
+// affects, or globalChange for devenv.yaml.
+func watchTarget(path string) (string, bool) {
+	rel, err := filepath.Rel(configDir, path)
+	if err != nil {
+		return "", false
+	}
+
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) == 1 {
+		return globalChange, parts[0] == "devenv.yaml"
+	}
+	if parts[0] == ".." {
+		return "", false
+	}
+	return parts[0], true
+}
+
+// regenerate renders the manifests affected by the changed targets and
+// prints one result line per developer.
+func (s *watchSession) regenerate(changed map[string]bool) {
+	stamp := time.Now().Format("15:04:05")
+
+	if changed[globalChange] {
+		globalConfig, err := config.LoadGlobalConfig(configDir)
+		if err != nil {
+			fmt.Printf("[%s] ❌ devenv.yaml: %v\n", stamp, err)
+			return
+		}
+		s.globalConfig = globalConfig
+		loadDataSources(configDir, globalConfig)
+		if err := generateSystemManifests(globalConfig, outputDir); err != nil {
+			fmt.Printf("[%s] ❌ system manifests: %v\n", stamp, err)
+		}
+	} else if s.globalConfig != nil {
+		// Data sources are looked up again on every regeneration
+		loadDataSources(configDir, s.globalConfig)
+	}
+	if s.globalConfig == nil {
+		fmt.Printf("[%s] ❌ devenv.yaml must be fixed first\n", stamp)
+		return
+	}
+
+	developers, err := s.affectedDevelopers(changed)
+	if err != nil {
+		fmt.Printf("[%s] ❌ %v\n", stamp, err)
+		return
+	}
+	for _, developer := range developers {
+		s.regenerateDeveloper(developer, stamp)
+	}
+	writeRunIndex()
+}
+
+// affectedDevelopers returns the developers to regenerate for the changed
+// targets, in sorted order.
+func (s *watchSession) affectedDevelopers(changed map[string]bool) ([]string, error) {
+	if s.developer != "" {
+		if changed[globalChange] || changed[s.developer] {
+			return []string{s.developer}, nil
+		}
+		return nil, nil
+	}
+
+	// Only directories with a developer config are developers
+	all, err := config.ListDeveloperDirs(configDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list developers: %w", err)
+	}
+	if changed[globalChange] {
+		return all, nil
+	}
+	var developers []string
+	for _, developer := range all {
+		if changed[developer] {
+			developers = append(developers, developer)
+		}
+	}
+	sort.Strings(developers)
+	return developers, nil
+}
+
+// regenerateDeveloper loads and renders one developer, or the selected
+// environment when --env is set.
+func (s *watchSession) regenerateDeveloper(developerName, stamp string) {
+	startTime := time.Now()
+	progress.started(developerName)
+
+	var cfg *config.DevEnvConfig
+	var err error
+	if envName != "" {
+		cfg, err = config.LoadDeveloperEnvironment(configDir, developerName, envName, s.globalConfig)
+	} else {
+		cfg, err = config.LoadDeveloperConfigWithBaseConfig(configDir, developerName, s.globalConfig)
+	}
+	if err == nil {
+		err = generateDeveloperManifests(cfg, developerOutputDir(cfg, developerName))
+	}
+	progress.finished(ProcessingResult{
+		Developer:   developerName,
+		Environment: envName,
+		Success:     err == nil,
+		Error:       err,
+		Duration:    time.Since(startTime),
+	})
+	if err != nil {
+		fmt.Printf("[%s] ❌ %s: %v\n", stamp, developerName, err)
+		return
+	}
+
+	fmt.Printf("[%s] ✅ %s (%.1fs)\n", stamp, developerName, time.Since(startTime).Seconds())
+	printValidationWarnings(developerName, cfg.Warnings)
+}
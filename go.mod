@@ -3,6 +3,7 @@ module github.com/nauticalab/devenv-engine
 go 1.24.2
 
 require (
+	github.com/fsnotify/fsnotify v1.8.0
 	github.com/go-git/go-git/v5 v5.16.2
 	github.com/go-playground/validator/v10 v10.27.0
 	github.com/spf13/cobra v1.10.1
@@ -0,0 +1,165 @@
+// Package archive streams rendered manifests into a gzip-compressed tar
+// archive, avoiding the creation of many small files on disk.
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Writer writes files into a .tar.gz stream. It is safe for concurrent use,
+// so batch workers can share a single archive.
+type Writer struct {
+	mu   sync.Mutex
+	file *os.File
+	gz   *gzip.Writer
+	tw   *tar.Writer
+	dirs map[string]bool
+
+	// Set by SetReproducible: entries are buffered in pending and written
+	// in name order on Close, all with modTime
+	reproducible bool
+	modTime      time.Time
+	pending      map[string][]byte
+}
+
+// NewWriter returns a Writer that streams a gzip-compressed tarball to w.
+func NewWriter(w io.Writer) *Writer {
+	gz := gzip.NewWriter(w)
+	return &Writer{
+		gz:   gz,
+		tw:   tar.NewWriter(gz),
+		dirs: make(map[string]bool),
+	}
+}
+
+// Create opens the file at archivePath for writing and returns a Writer
+// streaming into it. Close flushes the archive and closes the file.
+func Create(archivePath string) (*Writer, error) {
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive %s: %w", archivePath, err)
+	}
+	w := NewWriter(file)
+	w.file = file
+	return w, nil
+}
+
+// SetReproducible makes the archive byte-for-byte deterministic for the
+// same files regardless of write order or time: entries are buffered and
+// written sorted by name on Close, and all of them carry modTime. It must
+// be called before the first WriteFile.
+func (w *Writer) SetReproducible(modTime time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.reproducible = true
+	w.modTime = modTime
+	w.pending = make(map[string][]byte)
+}
+
+// WriteFile adds a regular file with the given slash-separated name and
+// content. Parent directory entries are added on first use.
+func (w *Writer) WriteFile(name string, content []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	name = path.Clean(name)
+	if w.reproducible {
+		w.pending[name] = append([]byte(nil), content...)
+		return nil
+	}
+	return w.writeEntry(name, content)
+}
+
+// entryTime returns the modification time recorded for entries.
+func (w *Writer) entryTime() time.Time {
+	if w.reproducible {
+		return w.modTime
+	}
+	return time.Now()
+}
+
+// writeEntry writes a file entry and its parent directories. The caller
+// must hold w.mu.
+func (w *Writer) writeEntry(name string, content []byte) error {
+	if err := w.ensureDir(path.Dir(name)); err != nil {
+		return err
+	}
+
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(content)),
+		ModTime:  w.entryTime(),
+		Format:   tar.FormatPAX,
+	}
+	if err := w.tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write archive header for %s: %w", name, err)
+	}
+	if _, err := w.tw.Write(content); err != nil {
+		return fmt.Errorf("failed to write archive entry %s: %w", name, err)
+	}
+	return nil
+}
+
+// ensureDir writes directory entries for dir and its parents. The caller
+// must hold w.mu.
+func (w *Writer) ensureDir(dir string) error {
+	if dir == "." || dir == "/" || w.dirs[dir] {
+		return nil
+	}
+	if err := w.ensureDir(path.Dir(dir)); err != nil {
+		return err
+	}
+	header := &tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     dir + "/",
+		Mode:     0755,
+		ModTime:  w.entryTime(),
+		Format:   tar.FormatPAX,
+	}
+	if err := w.tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write archive directory %s: %w", dir, err)
+	}
+	w.dirs[dir] = true
+	return nil
+}
+
+// Close flushes the tar and gzip streams and closes the underlying file
+// when the Writer was created with Create.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	names := make([]string, 0, len(w.pending))
+	for name := range w.pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := w.writeEntry(name, w.pending[name]); err != nil {
+			return err
+		}
+	}
+
+	if err := w.tw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize tar stream: %w", err)
+	}
+	if err := w.gz.Close(); err != nil {
+		return fmt.Errorf("failed to finalize gzip stream: %w", err)
+	}
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return fmt.Errorf("failed to close archive file: %w", err)
+		}
+	}
+	return nil
+}
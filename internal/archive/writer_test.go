@@ -0,0 +1,81 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	require.NoError(t, w.WriteFile("namespace.yaml", []byte("kind: Namespace\n")))
+	require.NoError(t, w.WriteFile("alice/statefulset.yaml", []byte("kind: StatefulSet\n")))
+	require.NoError(t, w.WriteFile("alice/service.yaml", []byte("kind: Service\n")))
+	require.NoError(t, w.Close())
+
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	var names []string
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, header.Name)
+		if header.Typeflag == tar.TypeReg {
+			content, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			files[header.Name] = string(content)
+		}
+	}
+
+	// The directory entry is written once, before its first file
+	assert.Equal(t, []string{"namespace.yaml", "alice/", "alice/statefulset.yaml", "alice/service.yaml"}, names)
+	assert.Equal(t, "kind: StatefulSet\n", files["alice/statefulset.yaml"])
+}
+
+func TestWriter_Reproducible(t *testing.T) {
+	modTime := time.Unix(1700000000, 0)
+	build := func(names ...string) []byte {
+		var buf bytes.Buffer
+		w := NewWriter(&buf)
+		w.SetReproducible(modTime)
+		for _, name := range names {
+			require.NoError(t, w.WriteFile(name, []byte("kind: "+name+"\n")))
+		}
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+
+	first := build("namespace.yaml", "bob/service.yaml", "alice/service.yaml")
+	time.Sleep(10 * time.Millisecond)
+	second := build("alice/service.yaml", "namespace.yaml", "bob/service.yaml")
+	assert.Equal(t, first, second, "archives must not depend on write order or time")
+
+	gz, err := gzip.NewReader(bytes.NewReader(first))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, header.Name)
+		assert.True(t, header.ModTime.Equal(modTime))
+	}
+	assert.Equal(t, []string{"alice/", "alice/service.yaml", "bob/", "bob/service.yaml", "namespace.yaml"}, names)
+}
@@ -0,0 +1,128 @@
+// Package bootstrap creates the initial layout of a config repository: a
+// developers/ directory with devenv.yaml, a CI workflow that runs devenv
+// test, and a .gitignore for generated manifests.
+package bootstrap
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed files
+var files embed.FS
+
+// repoFiles maps the embedded templates to their paths in the repository.
+// Templates are stored without leading dots so the embed is not affected
+// by hidden-file rules.
+var repoFiles = []struct {
+	template string
+	path     string
+}{
+	{"files/developers/devenv.yaml.tmpl", "developers/devenv.yaml"},
+	{"files/github/workflows/devenv.yml.tmpl", ".github/workflows/devenv.yml"},
+	{"files/gitignore.tmpl", ".gitignore"},
+}
+
+// Options are the answers used to fill in the generated files.
+type Options struct {
+	Namespace string // Kubernetes namespace for all environments
+	HostName  string // Domain for HTTP access; empty leaves it commented out
+	CPU       string // Default CPU per developer (e.g., "2" or "500m")
+	Memory    string // Default memory per developer (e.g., "8Gi")
+	Version   string // devenv version installed by the CI workflow
+}
+
+// DefaultOptions returns the suggested answers.
+func DefaultOptions() Options {
+	return Options{
+		Namespace: "devenv",
+		CPU:       "2",
+		Memory:    "8Gi",
+		Version:   "latest",
+	}
+}
+
+// Files renders the repository files for opts, keyed by path relative to
+// the repository root. The generated devenv.yaml is validated, so invalid
+// answers (e.g., a malformed memory quantity) are reported before anything
+// is written.
+func Files(opts Options) (map[string][]byte, error) {
+	rendered := make(map[string][]byte, len(repoFiles))
+	for _, file := range repoFiles {
+		content, err := files.ReadFile(file.template)
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := template.New(file.path).Funcs(template.FuncMap{"quote": quote}).Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template for %s: %w", file.path, err)
+		}
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, opts); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", file.path, err)
+		}
+		rendered[file.path] = out.Bytes()
+	}
+
+	globalConfig := config.NewBaseConfigWithDefaults()
+	if err := yaml.Unmarshal(rendered["developers/devenv.yaml"], &globalConfig); err != nil {
+		return nil, fmt.Errorf("generated devenv.yaml is not valid YAML: %w", err)
+	}
+	if err := config.ValidateBaseConfig(&globalConfig); err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
+
+// Write renders the repository files into dir. It refuses to overwrite
+// existing files, so it is safe to run in a repository that already has
+// some of them, and returns the paths it created.
+func Write(dir string, opts Options) ([]string, error) {
+	rendered, err := Files(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing []string
+	for _, file := range repoFiles {
+		_, err := os.Stat(filepath.Join(dir, file.path))
+		if err == nil {
+			existing = append(existing, file.path)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("refusing to overwrite existing files: %s", strings.Join(existing, ", "))
+	}
+
+	var created []string
+	for _, file := range repoFiles {
+		path := filepath.Join(dir, file.path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return created, err
+		}
+		if err := os.WriteFile(path, rendered[file.path], 0644); err != nil {
+			return created, err
+		}
+		created = append(created, file.path)
+	}
+	return created, nil
+}
+
+// quote renders s as a double-quoted YAML scalar.
+func quote(s string) (string, error) {
+	quoted, err := json.Marshal(s)
+	return string(quoted), err
+}
@@ -0,0 +1,58 @@
+package bootstrap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	opts := DefaultOptions()
+	opts.Namespace = "ml-team"
+	opts.HostName = "dev.example.com"
+	opts.Memory = "16Gi"
+	opts.Version = "v1.2.0"
+
+	created, err := Write(dir, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"developers/devenv.yaml", ".github/workflows/devenv.yml", ".gitignore"}, created)
+
+	// The generated global config loads with the answers applied
+	globalConfig, err := config.LoadGlobalConfig(filepath.Join(dir, "developers"))
+	require.NoError(t, err)
+	assert.Equal(t, "ml-team", globalConfig.Namespace)
+	assert.Equal(t, "dev.example.com", globalConfig.HostName)
+	assert.Equal(t, "16Gi", globalConfig.Resources.Memory)
+
+	workflow, err := os.ReadFile(filepath.Join(dir, ".github", "workflows", "devenv.yml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(workflow), "devenv-engine/cmd/devenv@v1.2.0")
+	assert.Contains(t, string(workflow), "devenv test --config-dir developers")
+
+	// Running again never overwrites
+	_, err = Write(dir, opts)
+	assert.ErrorContains(t, err, "refusing to overwrite existing files: developers/devenv.yaml, .github/workflows/devenv.yml, .gitignore")
+}
+
+func TestFiles_WithoutHostName(t *testing.T) {
+	files, err := Files(DefaultOptions())
+	require.NoError(t, err)
+	assert.Contains(t, string(files["developers/devenv.yaml"]), "# hostName:")
+}
+
+func TestFiles_InvalidAnswers(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Memory = "lots"
+	_, err := Files(opts)
+	assert.Error(t, err)
+
+	opts = DefaultOptions()
+	opts.Namespace = "Not_A_Namespace"
+	_, err = Files(opts)
+	assert.Error(t, err)
+}
@@ -0,0 +1,182 @@
+// Package bundle packs a developer's merged config and rendered manifests
+// into a portable .tar.gz, for moving an environment to another cluster or
+// namespace. Volume data is not copied; the bundle lists where it lives so
+// it can be snapshotted and restored alongside.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/nauticalab/devenv-engine/internal/archive"
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Layout of a bundle archive.
+const (
+	ManifestFile = "bundle.yaml"
+	ConfigFile   = config.DeveloperConfigFile
+	ManifestsDir = "manifests"
+)
+
+// Manifest describes the exported environment; it is stored as bundle.yaml.
+type Manifest struct {
+	Developer     string       `yaml:"developer"`
+	Namespace     string       `yaml:"namespace"`
+	DevenvVersion string       `yaml:"devenvVersion,omitempty"`
+	Data          []DataSource `yaml:"data,omitempty"`
+}
+
+// DataSource is where one volume of the environment keeps its data: a
+// PersistentVolumeClaim in the source namespace or a directory on the node.
+type DataSource struct {
+	Volume   string `yaml:"volume"`
+	Claim    string `yaml:"claim,omitempty"`
+	HostPath string `yaml:"hostPath,omitempty"`
+}
+
+// Bundle is the content of a bundle archive.
+type Bundle struct {
+	Manifest Manifest
+	// Config is the developer config merged with devenv.yaml, so it is
+	// complete without the source repository
+	Config []byte
+	// Manifests maps filenames to rendered manifests
+	Manifests map[string][]byte
+}
+
+// New builds a bundle for cfg from its rendered manifests.
+func New(cfg *config.DevEnvConfig, manifests map[string][]byte, devenvVersion string) (*Bundle, error) {
+	if cfg.Environment != "" {
+		return nil, fmt.Errorf("named environments cannot be exported; export developer %s instead", cfg.Name)
+	}
+
+	// The image variant is resolved, since the target devenv.yaml may not
+	// define imageVariants
+	resolved := *cfg
+	resolved.Image = cfg.ContainerImage()
+	resolved.ImageVariants = nil
+	merged, err := yaml.Marshal(&resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode merged config: %w", err)
+	}
+	header := fmt.Sprintf("# Exported from namespace %s; merged with the source devenv.yaml\n", cfg.Namespace)
+
+	return &Bundle{
+		Manifest: Manifest{
+			Developer:     cfg.Name,
+			Namespace:     cfg.Namespace,
+			DevenvVersion: devenvVersion,
+			Data:          dataSources(cfg),
+		},
+		Config:    append([]byte(header), merged...),
+		Manifests: manifests,
+	}, nil
+}
+
+// dataSources lists the volumes holding the environment's data, matching
+// the mounts in statefulset.tmpl.
+func dataSources(cfg *config.DevEnvConfig) []DataSource {
+	var sources []DataSource
+	if cfg.HomeVolumeClaim() {
+		claim, _ := config.ResourceName(config.ResourceHomeVolume, cfg.Name)
+		sources = append(sources, DataSource{Volume: "home", Claim: claim})
+	} else {
+		sources = append(sources,
+			DataSource{Volume: "home", HostPath: path.Join("/mnt/devenv", cfg.Name, "homedir")},
+			DataSource{Volume: "linuxbrew", HostPath: path.Join("/mnt/devenv", cfg.Name, "linuxbrew")},
+		)
+	}
+	for _, volume := range cfg.Volumes {
+		if volume.Size != "" {
+			sources = append(sources, DataSource{Volume: volume.Name, Claim: config.VolumeClaimName(volume.Name, cfg.Name)})
+		} else {
+			sources = append(sources, DataSource{Volume: volume.Name, HostPath: volume.LocalPath})
+		}
+	}
+	return sources
+}
+
+// Write stores the bundle as a .tar.gz stream.
+func (b *Bundle) Write(w io.Writer) error {
+	manifest, err := yaml.Marshal(b.Manifest)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", ManifestFile, err)
+	}
+
+	aw := archive.NewWriter(w)
+	if err := aw.WriteFile(ManifestFile, manifest); err != nil {
+		return err
+	}
+	if err := aw.WriteFile(ConfigFile, b.Config); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(b.Manifests))
+	for name := range b.Manifests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := aw.WriteFile(path.Join(ManifestsDir, name), b.Manifests[name]); err != nil {
+			return err
+		}
+	}
+	return aw.Close()
+}
+
+// Read parses a bundle written by Write.
+func Read(r io.Reader) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a bundle: %w", err)
+	}
+	defer gz.Close()
+
+	b := &Bundle{Manifests: make(map[string][]byte)}
+	var manifest []byte
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from bundle: %w", header.Name, err)
+		}
+
+		name := path.Clean(header.Name)
+		switch {
+		case name == ManifestFile:
+			manifest = content
+		case name == ConfigFile:
+			b.Config = content
+		case strings.HasPrefix(name, ManifestsDir+"/"):
+			b.Manifests[strings.TrimPrefix(name, ManifestsDir+"/")] = content
+		}
+	}
+
+	if manifest == nil || b.Config == nil {
+		return nil, fmt.Errorf("not a bundle: %s or %s is missing", ManifestFile, ConfigFile)
+	}
+	if err := yaml.Unmarshal(manifest, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ManifestFile, err)
+	}
+	if b.Manifest.Developer == "" {
+		return nil, fmt.Errorf("invalid %s: developer is not set", ManifestFile)
+	}
+	return b, nil
+}
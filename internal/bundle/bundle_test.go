@@ -0,0 +1,103 @@
+package bundle
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func testConfig() *config.DevEnvConfig {
+	return &config.DevEnvConfig{
+		Name: "alice",
+		BaseConfig: config.BaseConfig{
+			Namespace:    "devenv",
+			SSHPublicKey: []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAA alice@example.com"},
+			Resources:    config.ResourceConfig{CPU: 4, Memory: "16Gi", StorageClass: "fast-ssd"},
+			Volumes: []config.VolumeMount{
+				{Name: "data", ContainerPath: "/data", Size: "1Ti"},
+				{Name: "datasets", LocalPath: "/mnt/datasets", ContainerPath: "/datasets"},
+			},
+		},
+		SSHPort: 30001,
+	}
+}
+
+func TestBundle_RoundTrip(t *testing.T) {
+	manifests := map[string][]byte{
+		"statefulset.yaml": []byte("kind: StatefulSet\n"),
+		"service.yaml":     []byte("kind: Service\n"),
+	}
+	b, err := New(testConfig(), manifests, "v1.2.3")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, b.Write(&buf))
+
+	read, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, Manifest{
+		Developer:     "alice",
+		Namespace:     "devenv",
+		DevenvVersion: "v1.2.3",
+		Data: []DataSource{
+			{Volume: "home", Claim: "devenv-home-alice"},
+			{Volume: "data", Claim: "devenv-alice-data"},
+			{Volume: "datasets", HostPath: "/mnt/datasets"},
+		},
+	}, read.Manifest)
+	assert.Equal(t, manifests, read.Manifests)
+
+	// The merged config is a complete developer config
+	var cfg config.DevEnvConfig
+	require.NoError(t, yaml.Unmarshal(read.Config, &cfg))
+	assert.Equal(t, "alice", cfg.Name)
+	assert.Equal(t, "devenv", cfg.Namespace)
+	assert.Equal(t, 30001, cfg.SSHPort)
+	assert.Len(t, cfg.Volumes, 2)
+}
+
+func TestNew_HostPathHome(t *testing.T) {
+	cfg := testConfig()
+	cfg.Resources.StorageClass = ""
+	cfg.Volumes = nil
+
+	b, err := New(cfg, nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, []DataSource{
+		{Volume: "home", HostPath: "/mnt/devenv/alice/homedir"},
+		{Volume: "linuxbrew", HostPath: "/mnt/devenv/alice/linuxbrew"},
+	}, b.Manifest.Data)
+}
+
+func TestNew_RejectsEnvironment(t *testing.T) {
+	cfg := testConfig()
+	cfg.Environment = "gpu"
+	_, err := New(cfg, nil, "")
+	assert.ErrorContains(t, err, "named environments cannot be exported")
+}
+
+func TestRead_NotABundle(t *testing.T) {
+	_, err := Read(bytes.NewReader([]byte("plain text")))
+	assert.ErrorContains(t, err, "not a bundle")
+}
+
+func TestNew_ResolvesImageVariant(t *testing.T) {
+	cfg := testConfig()
+	cfg.Image = "ghcr.io/example/devenv:1.4"
+	cfg.Arch = "arm64"
+	cfg.ImageVariants = map[string]map[string]string{"ghcr.io/example/devenv:1.4": {"arm64": "ghcr.io/example/devenv:1.4-arm64"}}
+
+	b, err := New(cfg, nil, "")
+	require.NoError(t, err)
+
+	// The bundled config does not depend on the source imageVariants
+	var bundled config.DevEnvConfig
+	require.NoError(t, yaml.Unmarshal(b.Config, &bundled))
+	assert.Equal(t, "ghcr.io/example/devenv:1.4-arm64", bundled.Image)
+	assert.Empty(t, bundled.ImageVariants)
+	assert.Equal(t, "ghcr.io/example/devenv:1.4", cfg.Image, "the config is not modified")
+}
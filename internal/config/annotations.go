@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// AnnotationsConfig holds extra Kubernetes annotations added to generated
+// objects, e.g. nginx rate limits or auth snippets on the Ingress. Global
+// annotations apply to every developer; per-developer entries are added on
+// top and override global values with the same key.
+type AnnotationsConfig struct {
+	Service map[string]string `yaml:"service,omitempty"` // Added to every generated Service
+	Ingress map[string]string `yaml:"ingress,omitempty"` // Added to the Ingress
+}
+
+// managedIngressAnnotations are written by ingress.tmpl itself. Overriding
+// them through config would produce duplicate YAML keys, so they are rejected.
+var managedIngressAnnotations = []string{
+	"cert-manager.io/cluster-issuer",
+	"nginx.ingress.kubernetes.io/auth-response-headers",
+	"nginx.ingress.kubernetes.io/auth-signin",
+	"nginx.ingress.kubernetes.io/auth-url",
+	"nginx.ingress.kubernetes.io/force-ssl-redirect",
+}
+
+// annotationNameRe matches the name part of an annotation key.
+var annotationNameRe = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+
+// annotationPrefixRe matches the optional DNS subdomain prefix of a key.
+var annotationPrefixRe = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// addAnnotationIssues validates annotation keys and rejects keys that the
+// templates already manage.
+func addAnnotationIssues(report *ValidationReport, annotations AnnotationsConfig) {
+	for _, group := range []struct {
+		field  string
+		values map[string]string
+	}{
+		{"annotations.service", annotations.Service},
+		{"annotations.ingress", annotations.Ingress},
+	} {
+		keys := make([]string, 0, len(group.values))
+		for key := range group.values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if err := validateAnnotationKey(key); err != nil {
+				report.addError(ruleID(group.field, "key_format"), fmt.Errorf("'%s' key %q is invalid: %w", group.field, key, err))
+			}
+		}
+	}
+
+	for _, key := range managedIngressAnnotations {
+		if _, ok := annotations.Ingress[key]; ok {
+			report.addError(ruleAnnotationManaged, fmt.Errorf(
+				"'annotations.ingress' must not set %q; it is managed by devenv (see enableAuth, authURL and authSignIn)", key))
+		}
+	}
+}
+
+// validateAnnotationKey checks key against the Kubernetes annotation key
+// syntax: an optional DNS subdomain prefix and '/', followed by a name of at
+// most 63 characters.
+func validateAnnotationKey(key string) error {
+	prefix, name, hasPrefix := strings.Cut(key, "/")
+	if !hasPrefix {
+		name, prefix = prefix, ""
+	}
+	if hasPrefix && (len(prefix) == 0 || len(prefix) > 253 || !annotationPrefixRe.MatchString(prefix)) {
+		return fmt.Errorf("prefix must be a lowercase DNS subdomain")
+	}
+	if len(name) == 0 || len(name) > maxDNSLabelLength || !annotationNameRe.MatchString(name) {
+		return fmt.Errorf("name must be 1-63 alphanumerics, '-', '_' or '.', starting and ending with an alphanumeric")
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAnnotationKey(t *testing.T) {
+	valid := []string{
+		"owner",
+		"example.com/owner",
+		"nginx.ingress.kubernetes.io/limit-rps",
+		"team_name.v2",
+	}
+	for _, key := range valid {
+		assert.NoError(t, validateAnnotationKey(key), key)
+	}
+
+	invalid := []string{
+		"",
+		"/owner",
+		"Example.com/owner",
+		"example.com/",
+		"-owner",
+		"owner with spaces",
+		"a/b/c",
+	}
+	for _, key := range invalid {
+		assert.Error(t, validateAnnotationKey(key), key)
+	}
+}
+
+func TestCheckDevEnvConfig_Annotations(t *testing.T) {
+	newCfg := func(ingress map[string]string) *DevEnvConfig {
+		return &DevEnvConfig{
+			Name: "alice",
+			Git:  GitConfig{Name: "Alice", Email: "alice@example.com"},
+			BaseConfig: BaseConfig{
+				SSHPublicKey: "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host",
+				Annotations:  AnnotationsConfig{Ingress: ingress},
+			},
+		}
+	}
+
+	assert.Empty(t, CheckDevEnvConfig(newCfg(map[string]string{
+		"nginx.ingress.kubernetes.io/limit-rps": "10",
+	})).Issues)
+
+	report := CheckDevEnvConfig(newCfg(map[string]string{
+		"nginx.ingress.kubernetes.io/auth-url": "https://evil.example.com",
+		"bad key":                              "x",
+	}))
+	rules := make([]string, 0, len(report.Errors()))
+	for _, issue := range report.Errors() {
+		rules = append(rules, issue.Rule)
+	}
+	assert.ElementsMatch(t, []string{"annotations.ingress:key_format", "annotations.ingress:managed"}, rules)
+}
+
+func TestLoadDeveloperConfigWithBaseConfig_MergesAnnotations(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalConfigYAML := `annotations:
+  ingress:
+    nginx.ingress.kubernetes.io/limit-rps: "10"
+    nginx.ingress.kubernetes.io/proxy-body-size: "8m"
+  service:
+    example.com/owner: platform
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalConfigYAML), 0o644))
+
+	writeDeveloper := func(name, extra string) {
+		dir := filepath.Join(tempDir, name)
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		content := "name: " + name + "\nsshPublicKey: \"ssh-rsa AAAAB3NzaC1yc2E " + name + "@example.com\"\n" + extra
+		require.NoError(t, os.WriteFile(filepath.Join(dir, DeveloperConfigFile), []byte(content), 0o644))
+	}
+	writeDeveloper("alice", `annotations:
+  ingress:
+    nginx.ingress.kubernetes.io/limit-rps: "50"
+    nginx.ingress.kubernetes.io/enable-cors: "true"
+`)
+	writeDeveloper("bob", "")
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+
+	alice, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"nginx.ingress.kubernetes.io/limit-rps":       "50",
+		"nginx.ingress.kubernetes.io/proxy-body-size": "8m",
+		"nginx.ingress.kubernetes.io/enable-cors":     "true",
+	}, alice.Annotations.Ingress)
+	assert.Equal(t, map[string]string{"example.com/owner": "platform"}, alice.Annotations.Service)
+
+	// Alice's additions must not leak into the shared global config or bob
+	bob, err := LoadDeveloperConfigWithBaseConfig(tempDir, "bob", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "10", bob.Annotations.Ingress["nginx.ingress.kubernetes.io/limit-rps"])
+	assert.NotContains(t, bob.Annotations.Ingress, "nginx.ingress.kubernetes.io/enable-cors")
+	assert.Len(t, globalCfg.Annotations.Ingress, 2)
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// ArchLabel is the node label Kubernetes sets to a node's CPU architecture.
+const ArchLabel = "kubernetes.io/arch"
+
+// supportedArchs are the values allowed for 'arch' and as imageVariants keys.
+var supportedArchs = []string{"amd64", "arm64"}
+
+// PodNodeSelector returns the node selector of the environment's pod:
+// 'nodeSelector' plus the labels of preemptible nodes when 'preemptible'
+// is set, those of the GPU type when 'resources.gpuType' is set and
+// kubernetes.io/arch when 'arch' is set.
+func (c *BaseConfig) PodNodeSelector() map[string]string {
+	gpuType, hasGPUType := c.requestedGPUType()
+	if c.Arch == "" && !c.Preemptible && !hasGPUType {
+		return c.NodeSelector
+	}
+	selector := make(map[string]string, len(c.NodeSelector)+1)
+	if c.Preemptible {
+		preemptibleSelector, _ := c.preemptibleNodes()
+		for key, value := range preemptibleSelector {
+			selector[key] = value
+		}
+	}
+	for key, value := range gpuType.NodeSelector {
+		selector[key] = value
+	}
+	for key, value := range c.NodeSelector {
+		selector[key] = value
+	}
+	if c.Arch != "" {
+		selector[ArchLabel] = c.Arch
+	}
+	return selector
+}
+
+// ContainerImage returns the image the environment runs: the variant of
+// 'image' for 'arch' from imageVariants in devenv.yaml, or 'image' itself
+// when there is none (e.g., a multi-arch image).
+func (c *BaseConfig) ContainerImage() string {
+	if variant := c.ImageVariants[c.Image][c.Arch]; variant != "" {
+		return variant
+	}
+	return c.Image
+}
+
+// addArchIssues checks 'arch' against the node selector and the keys of
+// imageVariants, which the validator tags cannot express.
+func addArchIssues(report *ValidationReport, base *BaseConfig) {
+	if value, ok := base.NodeSelector[ArchLabel]; ok && base.Arch != "" && value != base.Arch {
+		report.addError(ruleArchNodeSelector, fmt.Errorf(
+			"'nodeSelector' %s %q conflicts with 'arch' %q; set only 'arch'", ArchLabel, value, base.Arch))
+	}
+
+	images := make([]string, 0, len(base.ImageVariants))
+	for image := range base.ImageVariants {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	for _, image := range images {
+		archs := make([]string, 0, len(base.ImageVariants[image]))
+		for arch := range base.ImageVariants[image] {
+			archs = append(archs, arch)
+		}
+		sort.Strings(archs)
+		for _, arch := range archs {
+			if !slices.Contains(supportedArchs, arch) {
+				report.addError(ruleImageVariantArch, fmt.Errorf(
+					"'imageVariants' for %q has unknown architecture %q; use %v", image, arch, supportedArchs))
+			}
+		}
+	}
+}
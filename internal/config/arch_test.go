@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseConfig_PodNodeSelector(t *testing.T) {
+	cfg := BaseConfig{NodeSelector: map[string]string{"pool": "general"}}
+	assert.Equal(t, map[string]string{"pool": "general"}, cfg.PodNodeSelector())
+
+	cfg.Arch = "arm64"
+	assert.Equal(t, map[string]string{"pool": "general", ArchLabel: "arm64"}, cfg.PodNodeSelector())
+	assert.Equal(t, map[string]string{"pool": "general"}, cfg.NodeSelector, "nodeSelector is not modified")
+}
+
+func TestBaseConfig_ContainerImage(t *testing.T) {
+	variants := map[string]map[string]string{
+		"ghcr.io/example/devenv:1.4": {"arm64": "ghcr.io/example/devenv:1.4-arm64"},
+	}
+
+	tests := map[string]struct {
+		image string
+		arch  string
+		want  string
+	}{
+		"variant":     {"ghcr.io/example/devenv:1.4", "arm64", "ghcr.io/example/devenv:1.4-arm64"},
+		"no variant":  {"ghcr.io/example/devenv:1.4", "amd64", "ghcr.io/example/devenv:1.4"},
+		"no arch":     {"ghcr.io/example/devenv:1.4", "", "ghcr.io/example/devenv:1.4"},
+		"other image": {"ubuntu:24.04", "arm64", "ubuntu:24.04"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := BaseConfig{Image: tt.image, Arch: tt.arch, ImageVariants: variants}
+			assert.Equal(t, tt.want, cfg.ContainerImage())
+		})
+	}
+}
+
+func TestCheckBaseConfig_Arch(t *testing.T) {
+	rules := func(report *ValidationReport) []string {
+		var ids []string
+		for _, issue := range report.Errors() {
+			ids = append(ids, issue.Rule)
+		}
+		return ids
+	}
+
+	assert.Empty(t, rules(CheckBaseConfig(&BaseConfig{
+		Arch:          "arm64",
+		NodeSelector:  map[string]string{ArchLabel: "arm64"},
+		ImageVariants: map[string]map[string]string{"devenv:1.4": {"amd64": "devenv:1.4-amd64", "arm64": "devenv:1.4-arm64"}},
+	})))
+	assert.Equal(t, []string{"arch:oneof"}, rules(CheckBaseConfig(&BaseConfig{Arch: "riscv64"})))
+	assert.Equal(t, []string{"arch:node_selector_conflict"}, rules(CheckBaseConfig(&BaseConfig{
+		Arch:         "arm64",
+		NodeSelector: map[string]string{ArchLabel: "amd64"},
+	})))
+	assert.Equal(t, []string{"imageVariants:arch"}, rules(CheckBaseConfig(&BaseConfig{
+		ImageVariants: map[string]map[string]string{"devenv:1.4": {"aarch64": "devenv:1.4-arm64"}},
+	})))
+}
+
+func TestLoadDeveloperEnvironment_Arch(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalConfigYAML := `image: ghcr.io/example/devenv:1.4
+imageVariants:
+  ghcr.io/example/devenv:1.4:
+    amd64: ghcr.io/example/devenv:1.4-amd64
+    arm64: ghcr.io/example/devenv:1.4-arm64
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalConfigYAML), 0o644))
+
+	developerDir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(filepath.Join(developerDir, EnvironmentsDir), 0o755))
+	// A developer cannot redefine the variants
+	developerYAML := `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+sshPort: 30001
+arch: amd64
+imageVariants:
+  ghcr.io/example/devenv:1.4:
+    amd64: evil.example.com/devenv
+`
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, DeveloperConfigFile), []byte(developerYAML), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, EnvironmentsDir, "arm.yaml"), []byte("sshPort: 30002\narch: arm64\n"), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+
+	alice, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "ghcr.io/example/devenv:1.4-amd64", alice.ContainerImage())
+	assert.Equal(t, map[string]string{ArchLabel: "amd64"}, alice.PodNodeSelector())
+	assert.Equal(t, "ghcr.io/example/devenv:1.4-amd64", globalCfg.ImageVariants["ghcr.io/example/devenv:1.4"]["amd64"])
+
+	// The variant follows the environment's arch
+	arm, err := LoadDeveloperEnvironment(tempDir, "alice", "arm", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "ghcr.io/example/devenv:1.4-arm64", arm.ContainerImage())
+	assert.Equal(t, map[string]string{ArchLabel: "arm64"}, arm.PodNodeSelector())
+}
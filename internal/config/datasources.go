@@ -0,0 +1,33 @@
+package config
+
+import "github.com/go-playground/validator/v10"
+
+// defaultDataSourceTimeout bounds a data source command, in seconds.
+const defaultDataSourceTimeout = 10
+
+// DataSourceConfig declares an external source of values that templates
+// read with lookup, e.g. a CMDB for cost centers or an IPAM for static IPs.
+// A source is either a command, run with sh -c and the key as "$1", whose
+// output is parsed as YAML or JSON, or a YAML or JSON file (relative to the
+// config directory) mapping keys to values.
+type DataSourceConfig struct {
+	Command        string `yaml:"command,omitempty"`
+	File           string `yaml:"file,omitempty"`
+	TimeoutSeconds int    `yaml:"timeoutSeconds,omitempty" validate:"omitempty,min=1"` // Per command run
+}
+
+// Timeout returns the number of seconds a command may run, defaulting to 10.
+func (d DataSourceConfig) Timeout() int {
+	return orDefault(d.TimeoutSeconds, defaultDataSourceTimeout)
+}
+
+// validateDataSource requires exactly one of command and file.
+func validateDataSource(sl validator.StructLevel) {
+	source := sl.Current().Interface().(DataSourceConfig)
+	switch {
+	case source.Command == "" && source.File == "":
+		sl.ReportError(source.Command, "Command", "Command", "required_without_file", "")
+	case source.Command != "" && source.File != "":
+		sl.ReportError(source.File, "File", "File", "excluded_with_command", "")
+	}
+}
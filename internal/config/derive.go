@@ -0,0 +1,79 @@
+package config
+
+import (
+	"strings"
+)
+
+// namePlaceholder is substituted with the developer name when expanding
+// derived-default patterns (e.g., "{name}@example.com").
+const namePlaceholder = "{name}"
+
+// DerivedDefaultsConfig holds patterns used to synthesize developer values
+// that are left unset in devenv-config.yaml. Each pattern may reference the
+// developer name via the "{name}" placeholder. Empty patterns disable the
+// corresponding derivation.
+type DerivedDefaultsConfig struct {
+	GitEmail string `yaml:"gitEmail,omitempty"` // e.g. "{name}@example.com"
+	GitName  string `yaml:"gitName,omitempty"`  // e.g. "{name}"
+	HostName string `yaml:"hostName,omitempty"` // e.g. "{name}.devenv.example.com"
+}
+
+// DerivedValue records a configuration value that was synthesized from a
+// derived-default pattern rather than set explicitly, so callers can report
+// its provenance to the user.
+type DerivedValue struct {
+	Field   string // YAML path of the field, e.g. "git.email"
+	Value   string // The synthesized value
+	Pattern string // The pattern the value was expanded from
+}
+
+// applyDerivedDefaults fills unset identity fields from the patterns in
+// DerivedDefaults and records each synthesized value in c.Derived.
+// Fields that are already set are never overwritten.
+func (c *DevEnvConfig) applyDerivedDefaults() {
+	patterns := c.DerivedDefaults
+
+	if c.Git.Email == "" && patterns.GitEmail != "" {
+		c.Git.Email = normalizeEmail(expandNamePattern(patterns.GitEmail, c.Name))
+		c.recordDerived("git.email", c.Git.Email, patterns.GitEmail)
+	}
+	if c.Git.Name == "" && patterns.GitName != "" {
+		c.Git.Name = expandNamePattern(patterns.GitName, c.Name)
+		c.recordDerived("git.name", c.Git.Name, patterns.GitName)
+	}
+	if c.HostName == "" && patterns.HostName != "" {
+		c.HostName = strings.ToLower(expandNamePattern(patterns.HostName, c.Name))
+		c.recordDerived("hostName", c.HostName, patterns.HostName)
+	}
+}
+
+// recordDerived appends a provenance entry for a synthesized field.
+func (c *DevEnvConfig) recordDerived(field, value, pattern string) {
+	c.Derived = append(c.Derived, DerivedValue{Field: field, Value: value, Pattern: pattern})
+}
+
+// normalizeIdentityFields trims identity-related strings and canonicalizes
+// the git email so equivalent inputs produce identical manifests.
+func (c *DevEnvConfig) normalizeIdentityFields() {
+	c.Name = trimHidden(c.Name)
+	c.normalizeStringFields()
+	c.Git.Name = strings.TrimSpace(c.Git.Name)
+	c.Git.Email = normalizeEmail(c.Git.Email)
+}
+
+// expandNamePattern replaces every "{name}" placeholder in pattern with name.
+func expandNamePattern(pattern, name string) string {
+	return strings.ReplaceAll(strings.TrimSpace(pattern), namePlaceholder, name)
+}
+
+// normalizeEmail trims surrounding whitespace and lowercases the domain part
+// of an email address. The local part is left untouched because it is
+// case-sensitive per RFC 5321. Strings without '@' are only trimmed.
+func normalizeEmail(email string) string {
+	email = strings.TrimSpace(email)
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	return email[:at+1] + strings.ToLower(email[at+1:])
+}
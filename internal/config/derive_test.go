@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyDerivedDefaults(t *testing.T) {
+	t.Run("unset fields are synthesized with provenance", func(t *testing.T) {
+		cfg := &DevEnvConfig{
+			Name: "alice",
+			BaseConfig: BaseConfig{
+				DerivedDefaults: DerivedDefaultsConfig{
+					GitEmail: "{name}@Example.COM",
+					GitName:  "{name}",
+					HostName: "{name}.devenv.example.com",
+				},
+			},
+		}
+
+		cfg.applyDerivedDefaults()
+
+		assert.Equal(t, "alice@example.com", cfg.Git.Email)
+		assert.Equal(t, "alice", cfg.Git.Name)
+		assert.Equal(t, "alice.devenv.example.com", cfg.HostName)
+		assert.Equal(t, []DerivedValue{
+			{Field: "git.email", Value: "alice@example.com", Pattern: "{name}@Example.COM"},
+			{Field: "git.name", Value: "alice", Pattern: "{name}"},
+			{Field: "hostName", Value: "alice.devenv.example.com", Pattern: "{name}.devenv.example.com"},
+		}, cfg.Derived)
+	})
+
+	t.Run("explicit values are never overwritten", func(t *testing.T) {
+		cfg := &DevEnvConfig{
+			Name: "alice",
+			Git:  GitConfig{Name: "Alice Smith", Email: "alice@corp.io"},
+			BaseConfig: BaseConfig{
+				HostName: "dev.example.com",
+				DerivedDefaults: DerivedDefaultsConfig{
+					GitEmail: "{name}@example.com",
+					GitName:  "{name}",
+					HostName: "{name}.example.com",
+				},
+			},
+		}
+
+		cfg.applyDerivedDefaults()
+
+		assert.Equal(t, "alice@corp.io", cfg.Git.Email)
+		assert.Equal(t, "Alice Smith", cfg.Git.Name)
+		assert.Equal(t, "dev.example.com", cfg.HostName)
+		assert.Empty(t, cfg.Derived)
+	})
+
+	t.Run("no patterns means no derivation", func(t *testing.T) {
+		cfg := &DevEnvConfig{Name: "alice"}
+		cfg.applyDerivedDefaults()
+
+		assert.Empty(t, cfg.Git.Email)
+		assert.Empty(t, cfg.HostName)
+		assert.Empty(t, cfg.Derived)
+	})
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"alice@example.com", "alice@example.com"},
+		{"  Alice@Example.COM ", "Alice@example.com"},
+		{"no-at-sign", "no-at-sign"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, normalizeEmail(tt.in), "input %q", tt.in)
+	}
+}
+
+func TestLoadDeveloperConfigWithBaseConfig_DerivedDefaults(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalConfigYAML := `derivedDefaults:
+  gitEmail: "{name}@example.com"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalConfigYAML), 0o644))
+
+	developerDir := filepath.Join(tempDir, "bob")
+	require.NoError(t, os.MkdirAll(developerDir, 0o755))
+	userConfigYAML := `name: bob
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E bob@example.com"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, "devenv-config.yaml"), []byte(userConfigYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+
+	cfg, err := LoadDeveloperConfigWithBaseConfig(tempDir, "bob", globalCfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, "bob@example.com", cfg.Git.Email)
+	require.Len(t, cfg.Derived, 1)
+	assert.Equal(t, "git.email", cfg.Derived[0].Field)
+}
@@ -64,6 +64,16 @@
 //
 // Invalid configurations return descriptive errors during loading.
 //
+// Embedders can add organization-specific checks with [NewValidator], which
+// accepts extra validator tags and per-field tag expressions:
+//
+//	v, err := config.NewValidator(config.ValidatorOptions{
+//	    FieldRules: map[string]string{"image": "startswith=registry.example.com/"},
+//	})
+//	report := v.CheckDevEnvConfig(cfg)
+//
+// Validators are immutable after construction and safe for concurrent use.
+//
 // # Template Integration
 //
 // DevEnvConfig provides template-friendly methods that handle errors internally
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// utf8BOM is the byte order mark some Windows editors prepend to UTF-8 files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// normalizeConfigBytes strips a leading UTF-8 BOM and converts CRLF and lone
+// CR line endings to LF so files saved on Windows parse exactly like files
+// saved elsewhere. Each normalization is returned as a warning so the file
+// can be fixed at the source.
+func normalizeConfigBytes(data []byte) ([]byte, []ValidationIssue) {
+	var warnings []ValidationIssue
+
+	if bytes.HasPrefix(data, utf8BOM) {
+		data = data[len(utf8BOM):]
+		warnings = append(warnings, ValidationIssue{
+			Severity: SeverityWarning,
+			Rule:     ruleFileBOM,
+			Message:  "file starts with a UTF-8 byte order mark (BOM); it was ignored, but consider saving the file as UTF-8 without BOM",
+		})
+	}
+
+	if bytes.IndexByte(data, '\r') >= 0 {
+		data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+		data = bytes.ReplaceAll(data, []byte("\r"), []byte("\n"))
+		warnings = append(warnings, ValidationIssue{
+			Severity: SeverityWarning,
+			Rule:     ruleFileLineEndings,
+			Message:  "file uses Windows (CRLF) line endings; they were converted to LF, but consider saving the file with LF line endings",
+		})
+	}
+
+	return data, warnings
+}
+
+// normalizeScriptFields removes carriage returns from fields that are
+// rendered into the startup scripts, where a stray "\r" turns "vim" into a
+// package name apt cannot find. These can only survive file normalization
+// through explicit "\r" escapes in quoted YAML strings.
+func (c *BaseConfig) normalizeScriptFields() []ValidationIssue {
+	var fixed []string
+	strip := func(field string, value *string) {
+		if stripCarriageReturns(value) {
+			fixed = append(fixed, field)
+		}
+	}
+
+	strip("pythonBinPath", &c.PythonBinPath)
+	for i := range c.Packages.Python {
+		strip("packages.python", &c.Packages.Python[i])
+	}
+	for i := range c.Packages.APT {
+		strip("packages.apt", &c.Packages.APT[i])
+	}
+	for i := range c.Packages.Brew {
+		strip("packages.brew", &c.Packages.Brew[i])
+	}
+	for i := range c.GitRepos {
+		repo := &c.GitRepos[i]
+		strip("gitRepos.url", &repo.URL)
+		strip("gitRepos.branch", &repo.Branch)
+		strip("gitRepos.tag", &repo.Tag)
+		strip("gitRepos.commitHash", &repo.CommitHash)
+		strip("gitRepos.directory", &repo.Directory)
+	}
+
+	return carriageReturnWarnings(fixed)
+}
+
+// normalizeScriptFields also covers the developer-only git identity, which
+// is exported into the environment by the startup script.
+func (c *DevEnvConfig) normalizeScriptFields() []ValidationIssue {
+	warnings := c.BaseConfig.normalizeScriptFields()
+
+	var fixed []string
+	if stripCarriageReturns(&c.Git.Name) {
+		fixed = append(fixed, "git.name")
+	}
+	if stripCarriageReturns(&c.Git.Email) {
+		fixed = append(fixed, "git.email")
+	}
+	return append(warnings, carriageReturnWarnings(fixed)...)
+}
+
+// stripCarriageReturns removes every "\r" from value and reports whether
+// anything was removed.
+func stripCarriageReturns(value *string) bool {
+	if !strings.ContainsRune(*value, '\r') {
+		return false
+	}
+	*value = strings.ReplaceAll(*value, "\r", "")
+	return true
+}
+
+// carriageReturnWarnings reports each field once, in first-seen order.
+func carriageReturnWarnings(fields []string) []ValidationIssue {
+	var warnings []ValidationIssue
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		if seen[field] {
+			continue
+		}
+		seen[field] = true
+		warnings = append(warnings, ValidationIssue{
+			Severity: SeverityWarning,
+			Rule:     ruleFileLineEndings,
+			Message:  fmt.Sprintf("'%s' contained carriage return characters (\\r); they were removed", field),
+		})
+	}
+	return warnings
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeConfigBytes(t *testing.T) {
+	data, warnings := normalizeConfigBytes([]byte("name: alice\nimage: ubuntu\n"))
+	assert.Equal(t, "name: alice\nimage: ubuntu\n", string(data))
+	assert.Empty(t, warnings)
+
+	data, warnings = normalizeConfigBytes([]byte("\xEF\xBB\xBFname: alice\r\nimage: ubuntu\rpackages: []\r\n"))
+	assert.Equal(t, "name: alice\nimage: ubuntu\npackages: []\n", string(data))
+	require.Len(t, warnings, 2)
+	assert.Equal(t, "file:bom", warnings[0].Rule)
+	assert.Equal(t, "file:line_endings", warnings[1].Rule)
+	assert.Equal(t, SeverityWarning, warnings[1].Severity)
+}
+
+func TestLoadDeveloperConfigWithBaseConfig_WindowsFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	globalYAML := "\xEF\xBB\xBFpackages:\r\n  apt:\r\n    - \"git\\r\"\r\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalYAML), 0o644))
+
+	dir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	developerYAML := "name: alice\r\n" +
+		"sshPublicKey: \"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAA alice@laptop\"\r\n" +
+		"git:\r\n  name: \"Alice\\r\"\r\n  email: alice@example.com\r\n" +
+		"packages:\r\n  apt: [\"vim\\r\"]\r\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DeveloperConfigFile), []byte(developerYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"git"}, globalCfg.Packages.APT)
+
+	var globalRules []string
+	for _, issue := range CheckBaseConfig(globalCfg).Warnings() {
+		globalRules = append(globalRules, issue.Rule)
+	}
+	assert.Equal(t, []string{"file:bom", "file:line_endings", "file:line_endings"}, globalRules)
+
+	cfg, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"git", "vim"}, cfg.Packages.APT)
+	assert.Equal(t, "Alice", cfg.Git.Name)
+
+	var messages []string
+	for _, warning := range cfg.Warnings {
+		messages = append(messages, warning.Message)
+	}
+	require.Len(t, messages, 2, "global fixes are not repeated for each developer")
+	assert.Contains(t, messages[0], "CRLF")
+	assert.Contains(t, messages[1], "'packages.apt' contained carriage return")
+}
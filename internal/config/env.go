@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// EnvVar is a single environment variable exposed to the container.
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+// envNameRe matches portable environment variable names: letters, digits
+// and underscores, not starting with a digit. Names outside this set are
+// silently skipped by envFrom or break shell scripts that read them.
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedEnvNames are set by the generated manifests themselves (env-vars
+// ConfigMap and the container's env list) and cannot be overridden.
+var reservedEnvNames = []string{
+	"GIT_EMAIL",
+	"GIT_NAME",
+	"GITHUB_TOKEN",
+	"IS_ADMIN",
+	"POD_FQDN",
+	"SERVICE_FQDN",
+	"UID",
+	"USER",
+}
+
+// EnvVars returns the configured environment variables sorted by name so
+// generated manifests are stable.
+func (c *BaseConfig) EnvVars() []EnvVar {
+	vars := make([]EnvVar, 0, len(c.Env))
+	for name, value := range c.Env {
+		vars = append(vars, EnvVar{Name: name, Value: value})
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+// addEnvIssues validates environment variable names and rejects names the
+// templates already set.
+func addEnvIssues(report *ValidationReport, env map[string]string) {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !envNameRe.MatchString(name) {
+			report.addError(ruleEnvNameFormat, fmt.Errorf(
+				"'env' name %q is invalid; use letters, digits and '_', not starting with a digit", name))
+		}
+	}
+
+	for _, name := range reservedEnvNames {
+		if _, ok := env[name]; ok {
+			report.addError(ruleEnvNameReserved, fmt.Errorf(
+				"'env' must not set %q; it is managed by devenv", name))
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseConfig_EnvVars(t *testing.T) {
+	cfg := &BaseConfig{Env: map[string]string{"ZETA": "1", "ALPHA": "2", "_internal": ""}}
+	assert.Equal(t, []EnvVar{
+		{Name: "ALPHA", Value: "2"},
+		{Name: "ZETA", Value: "1"},
+		{Name: "_internal", Value: ""},
+	}, cfg.EnvVars())
+
+	assert.Empty(t, (&BaseConfig{}).EnvVars())
+}
+
+func TestCheckDevEnvConfig_Env(t *testing.T) {
+	newCfg := func(env map[string]string) *DevEnvConfig {
+		return &DevEnvConfig{
+			Name: "alice",
+			Git:  GitConfig{Name: "Alice", Email: "alice@example.com"},
+			BaseConfig: BaseConfig{
+				SSHPublicKey: "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host",
+				Env:          env,
+			},
+		}
+	}
+
+	assert.Empty(t, CheckDevEnvConfig(newCfg(map[string]string{"LOG_LEVEL": "debug", "_x1": ""})).Issues)
+
+	report := CheckDevEnvConfig(newCfg(map[string]string{
+		"1BAD":      "x",
+		"with-dash": "x",
+		"USER":      "root",
+	}))
+	rules := make([]string, 0, len(report.Errors()))
+	for _, issue := range report.Errors() {
+		rules = append(rules, issue.Rule)
+	}
+	assert.Equal(t, []string{"env:name_format", "env:name_format", "env:reserved"}, rules)
+	assert.Contains(t, report.Err().Error(), `'env' must not set "USER"`)
+}
+
+func TestLoadDeveloperConfigWithBaseConfig_Env(t *testing.T) {
+	tempDir := t.TempDir()
+	globalYAML := "env:\n  LOG_LEVEL: info\n  REGION: us-east-1\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalYAML), 0o644))
+
+	dir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	developerYAML := "name: alice\nsshPublicKey: \"ssh-rsa AAAAB3NzaC1yc2E alice@example.com\"\n" +
+		"env:\n  LOG_LEVEL: debug\n  EDITOR: vim\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DeveloperConfigFile), []byte(developerYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+
+	cfg, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"EDITOR":    "vim",
+		"LOG_LEVEL": "debug",
+		"REGION":    "us-east-1",
+	}, cfg.Env)
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "info", "REGION": "us-east-1"}, globalCfg.Env,
+		"global config must not be modified")
+}
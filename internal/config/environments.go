@@ -0,0 +1,238 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvironmentsDir is the directory inside a developer's config directory
+// that holds named environments, one YAML file per environment
+// (e.g., alice/environments/gpu.yaml).
+const EnvironmentsDir = "environments"
+
+// environmentNameRe restricts environment names to lowercase DNS label
+// characters since they become part of generated resource names.
+var environmentNameRe = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// EnvironmentConfigPath returns the path of a developer's environment file.
+func EnvironmentConfigPath(configDir, developerName, environment string) string {
+	return filepath.Join(configDir, developerName, EnvironmentsDir, environment+".yaml")
+}
+
+// ListEnvironments returns the sorted names of a developer's environments.
+// A developer without an environments directory has none.
+func ListEnvironments(configDir, developerName string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(configDir, developerName, EnvironmentsDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var environments []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".yaml")
+		if ok && !entry.IsDir() {
+			environments = append(environments, name)
+		}
+	}
+	sort.Strings(environments)
+	return environments, nil
+}
+
+// Instance is a developer's default environment or one of its named
+// environments, with the name its resources are derived from.
+type Instance struct {
+	Developer   string // Directory name of the developer
+	Environment string // Empty for the default environment
+	Name        string // InstanceName, e.g. "alice" or "alice-gpu"
+}
+
+// String names the instance, e.g. "alice" or "alice (environment gpu)".
+func (i Instance) String() string {
+	if i.Environment == "" {
+		return i.Developer
+	}
+	return i.Developer + " (environment " + i.Environment + ")"
+}
+
+// ListInstances returns the default and named environments of every
+// developer in configDir, named after the indexed developer names.
+// Developers whose config header cannot be decoded are left out.
+func ListInstances(configDir string) ([]Instance, error) {
+	index, err := IndexDevelopers(configDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []Instance
+	for _, entry := range index {
+		if entry.Err != nil {
+			continue
+		}
+		instances = append(instances, Instance{Developer: entry.Developer, Name: entry.Name})
+
+		environments, err := ListEnvironments(configDir, entry.Developer)
+		if err != nil {
+			return nil, err
+		}
+		for _, environment := range environments {
+			instances = append(instances, Instance{
+				Developer:   entry.Developer,
+				Environment: environment,
+				Name:        entry.Name + "-" + environment,
+			})
+		}
+	}
+	return instances, nil
+}
+
+// LoadDeveloperEnvironment loads a developer config merged with the global
+// config and then applies the named environment on top of it. It fails on
+// validation errors, like LoadDeveloperConfigWithBaseConfig.
+func LoadDeveloperEnvironment(configDir, developerName, environment string, baseConfig *BaseConfig) (*DevEnvConfig, error) {
+	envConfig, report, err := CheckDeveloperEnvironment(configDir, developerName, environment, baseConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := report.Err(); err != nil {
+		return nil, fmt.Errorf("invalid configuration in %s: %w",
+			EnvironmentConfigPath(configDir, developerName, environment), err)
+	}
+	envConfig.Warnings = report.Warnings()
+
+	return envConfig, nil
+}
+
+// CheckDeveloperEnvironment adds a fourth layer to the merge hierarchy:
+// system defaults → devenv.yaml → devenv-config.yaml → environments/<name>.yaml.
+// The environment file overrides scalar fields and extends list and map
+// fields exactly like devenv-config.yaml extends devenv.yaml; a 'profile' in
+// it replaces the developer's profile. Resources are
+// named after InstanceName ("<developer>-<environment>"), so an environment
+// must use its own sshPort, and the instance name must not be taken by
+// another developer or another developer's environment.
+//
+// Like CheckDeveloperConfig, the returned error is only set when a file
+// cannot be read or parsed; validation problems are in the report.
+func CheckDeveloperEnvironment(configDir, developerName, environment string, baseConfig *BaseConfig) (*DevEnvConfig, *ValidationReport, error) {
+	if !environmentNameRe.MatchString(environment) {
+		return nil, nil, fmt.Errorf("invalid environment name %q: use lowercase letters, digits and '-'", environment)
+	}
+
+	configPath := EnvironmentConfigPath(configDir, developerName, environment)
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		available, _ := ListEnvironments(configDir, developerName)
+		if len(available) == 0 {
+			return nil, nil, fmt.Errorf("environment %q not found: %s does not exist", environment, configPath)
+		}
+		return nil, nil, fmt.Errorf("environment %q not found: %s does not exist (available: %s)",
+			environment, configPath, strings.Join(available, ", "))
+	}
+
+	data, err := readConfigFile(configPath, orDefault(baseConfig.Validation.Limits.MaxFileSize, defaultMaxFileSize))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	}
+	data, warnings := normalizeConfigBytes(data)
+
+	// A profile selected by the environment replaces the developer's profile
+	profileName, err := selectedProfile(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse YAML in %s: %w", configPath, err)
+	}
+	developerConfig, _, err := checkDeveloperConfig(configDir, developerName, baseConfig, profileName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Start from the merged developer config; maps and warnings are reset
+	// for the same reasons as in CheckDeveloperConfig
+	envConfig := *developerConfig
+	envConfig.Annotations = AnnotationsConfig{}
+	envConfig.Env = nil
+	envConfig.NodeSelector = nil
+	envConfig.Affinity = nil
+	envConfig.ExtraValues = nil
+	envConfig.Observability.Labels = nil
+	envConfig.Logging.Labels = nil
+	envConfig.Probes = ProbesConfig{}
+	envConfig.ImageVariants = nil
+	envConfig.PreemptibleNodes = PreemptibleNodesConfig{}
+	envConfig.GPUTypes = nil
+	envConfig.loadWarnings = nil
+
+	if err := yaml.Unmarshal(data, &envConfig); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse YAML in %s: %w", configPath, err)
+	}
+
+	envConfig.mergeListFields(&developerConfig.BaseConfig)
+	envConfig.Validation = baseConfig.Validation
+	envConfig.Profiles = nil
+	envConfig.Hooks = HooksConfig{}
+	envConfig.SSHPortRange = PortRangeConfig{}
+	envConfig.DataSources = nil
+	envConfig.ImageVariants = baseConfig.ImageVariants
+	envConfig.PreemptibleNodes = baseConfig.PreemptibleNodes
+	envConfig.GPUTypes = baseConfig.GPUTypes
+	envConfig.NamespaceStrategy = baseConfig.NamespaceStrategy
+	envConfig.Environment = environment
+	envConfig.normalizeIdentityFields()
+	envConfig.applyNamespaceStrategy()
+	envConfig.loadWarnings = append(developerConfig.loadWarnings,
+		append(warnings, envConfig.normalizeScriptFields()...)...)
+
+	report := envConfig.Check()
+	if _, err := baseConfig.withProfile(envConfig.Profile); err != nil {
+		report.addError(ruleProfileUnknown, err)
+	}
+	if envConfig.Name != developerConfig.Name {
+		report.addError(ruleEnvironmentName, fmt.Errorf(
+			"environment %q must not change 'name' (%q in %s)", environment, envConfig.Name, DeveloperConfigFile))
+	}
+	if envConfig.SSHPort != 0 && envConfig.SSHPort == developerConfig.SSHPort {
+		report.addError(ruleEnvironmentSSHPort, fmt.Errorf(
+			"environment %q must set its own 'sshPort'; %d is already used by the developer's default environment", environment, envConfig.SSHPort))
+	}
+	instances, err := ListInstances(configDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan developer directories in %s: %w", configDir, err)
+	}
+	var owners []string
+	for _, instance := range instances {
+		if instance.Developer != developerName &&
+			CanonicalDeveloperName(instance.Name) == CanonicalDeveloperName(envConfig.InstanceName()) {
+			owners = append(owners, instance.String())
+		}
+	}
+	if len(owners) > 0 {
+		report.addError(ruleEnvironmentInstanceName, fmt.Errorf(
+			"environment %q is named %q, which is also the name of %s; their resources and output directories would overwrite each other, so rename the environment",
+			environment, envConfig.InstanceName(), strings.Join(owners, ", ")))
+	}
+	// The claims are named after the developer, so the default and named
+	// environments mount the same ones, possibly from different nodes
+	if envConfig.HasVolumeClaims() {
+		mode := envConfig.VolumeAccessMode()
+		if developerConfig.HasVolumeClaims() && developerConfig.VolumeAccessMode() != AccessModeReadWriteMany {
+			mode = developerConfig.VolumeAccessMode()
+		}
+		if mode != AccessModeReadWriteMany {
+			report.addError(ruleEnvironmentVolumeClaims, fmt.Errorf(
+				"environment %q shares the developer's PersistentVolumeClaims, which are %s and cannot be mounted from two nodes at once; set 'resources.storageAccessMode: ReadWriteMany' in %s, with a StorageClass that supports it",
+				environment, mode, DeveloperConfigFile))
+		}
+	}
+
+	return &envConfig, report, nil
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeEnvironmentFixture creates a config dir with developer alice and the
+// given environment files.
+func writeEnvironmentFixture(t *testing.T, environments map[string]string) (string, *BaseConfig) {
+	t.Helper()
+	tempDir := t.TempDir()
+
+	dir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, EnvironmentsDir), 0o755))
+	developerYAML := "name: alice\nsshPort: 30010\nsshPublicKey: \"ssh-rsa AAAAB3NzaC1yc2E alice@example.com\"\n" +
+		"packages:\n  apt: [vim]\nenv:\n  LOG_LEVEL: info\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DeveloperConfigFile), []byte(developerYAML), 0o644))
+
+	for name, content := range environments {
+		require.NoError(t, os.WriteFile(EnvironmentConfigPath(tempDir, "alice", name), []byte(content), 0o644))
+	}
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+	return tempDir, globalCfg
+}
+
+func TestLoadDeveloperEnvironment(t *testing.T) {
+	configDir, globalCfg := writeEnvironmentFixture(t, map[string]string{
+		"gpu": "sshPort: 30011\nimage: nvidia/cuda:12.4.0-base-ubuntu22.04\n" +
+			"packages:\n  apt: [nvtop]\nenv:\n  LOG_LEVEL: debug\n",
+	})
+
+	cfg, err := LoadDeveloperEnvironment(configDir, "alice", "gpu", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", cfg.Name)
+	assert.Equal(t, "gpu", cfg.Environment)
+	assert.Equal(t, "alice-gpu", cfg.InstanceName())
+	assert.Equal(t, 30011, cfg.SSHPort)
+	assert.Equal(t, "nvidia/cuda:12.4.0-base-ubuntu22.04", cfg.Image)
+	assert.Equal(t, []string{"vim", "nvtop"}, cfg.Packages.APT, "lists are extended, not replaced")
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "debug"}, cfg.Env)
+
+	cfg.Namespace = "devenv"
+	assert.Equal(t, "devenv-alice-gpu-0", cfg.PodHostname())
+	assert.Equal(t, "devenv-alice-gpu.devenv.svc.cluster.local", cfg.ServiceFQDN())
+
+	// The developer's default environment is unaffected
+	devCfg, err := LoadDeveloperConfigWithBaseConfig(configDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", devCfg.InstanceName())
+	assert.Equal(t, []string{"vim"}, devCfg.Packages.APT)
+}
+
+func TestCheckDeveloperEnvironment_Errors(t *testing.T) {
+	configDir, globalCfg := writeEnvironmentFixture(t, map[string]string{
+		"samePort": "image: ubuntu:24.04\n",
+		"renamed":  "name: bob\nsshPort: 30012\n",
+	})
+
+	_, report, err := CheckDeveloperEnvironment(configDir, "alice", "renamed", globalCfg)
+	require.NoError(t, err)
+	require.Len(t, report.Errors(), 1)
+	assert.Equal(t, "name:environment_unchanged", report.Errors()[0].Rule)
+
+	_, err = LoadDeveloperEnvironment(configDir, "alice", "samePort", globalCfg)
+	assert.ErrorContains(t, err, "invalid environment name")
+
+	require.NoError(t, os.Rename(EnvironmentConfigPath(configDir, "alice", "samePort"),
+		EnvironmentConfigPath(configDir, "alice", "same-port")))
+	_, report, err = CheckDeveloperEnvironment(configDir, "alice", "same-port", globalCfg)
+	require.NoError(t, err)
+	require.Len(t, report.Errors(), 1)
+	assert.Equal(t, "sshPort:environment_unique", report.Errors()[0].Rule)
+
+	_, err = LoadDeveloperEnvironment(configDir, "alice", "staging", globalCfg)
+	assert.ErrorContains(t, err, `environment "staging" not found`)
+	assert.ErrorContains(t, err, "(available: renamed, same-port)")
+}
+
+func TestCheckDeveloperEnvironment_VolumeClaims(t *testing.T) {
+	configDir, globalCfg := writeEnvironmentFixture(t, map[string]string{
+		"gpu":    "sshPort: 30011\nresources:\n  storageClass: fast-ssd\n",
+		"shared": "sshPort: 30012\nresources:\n  storageClass: fast-ssd\n  storageAccessMode: ReadWriteMany\n",
+	})
+
+	// A ReadWriteOnce claim of the developer cannot follow the environment to another node
+	_, report, err := CheckDeveloperEnvironment(configDir, "alice", "gpu", globalCfg)
+	require.NoError(t, err)
+	require.Len(t, report.Errors(), 1)
+	assert.Equal(t, "resources.storageAccessMode:environment_shared", report.Errors()[0].Rule)
+	assert.Contains(t, report.Errors()[0].Message, "ReadWriteOnce")
+
+	envCfg, report, err := CheckDeveloperEnvironment(configDir, "alice", "shared", globalCfg)
+	require.NoError(t, err)
+	assert.Empty(t, report.Errors())
+	assert.Equal(t, AccessModeReadWriteMany, envCfg.VolumeAccessMode())
+}
+
+func TestCheckDeveloperEnvironment_InstanceNameCollision(t *testing.T) {
+	configDir, globalCfg := writeEnvironmentFixture(t, map[string]string{
+		"gpu":   "sshPort: 30011\n",
+		"gpu-x": "sshPort: 30012\n",
+		"cpu":   "sshPort: 30013\n",
+	})
+	// Names are compared case-insensitively
+	dir := filepath.Join(configDir, "alice-gpu")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, EnvironmentsDir), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DeveloperConfigFile), []byte("name: Alice-GPU\nsshPort: 30020\n"), 0o644))
+	require.NoError(t, os.WriteFile(EnvironmentConfigPath(configDir, "alice-gpu", "x"), []byte("sshPort: 30021\n"), 0o644))
+
+	instances, err := ListInstances(configDir)
+	require.NoError(t, err)
+	assert.Equal(t, []Instance{
+		{Developer: "alice", Name: "alice"},
+		{Developer: "alice", Environment: "cpu", Name: "alice-cpu"},
+		{Developer: "alice", Environment: "gpu", Name: "alice-gpu"},
+		{Developer: "alice", Environment: "gpu-x", Name: "alice-gpu-x"},
+		{Developer: "alice-gpu", Name: "Alice-GPU"},
+		{Developer: "alice-gpu", Environment: "x", Name: "Alice-GPU-x"},
+	}, instances)
+
+	// alice-gpu is another developer
+	_, report, err := CheckDeveloperEnvironment(configDir, "alice", "gpu", globalCfg)
+	require.NoError(t, err)
+	require.Len(t, report.Errors(), 1)
+	assert.Equal(t, "name:environment_collision", report.Errors()[0].Rule)
+	assert.Contains(t, report.Errors()[0].Message, "also the name of alice-gpu;")
+
+	// alice-gpu-x is another developer's environment
+	_, report, err = CheckDeveloperEnvironment(configDir, "alice", "gpu-x", globalCfg)
+	require.NoError(t, err)
+	require.Len(t, report.Errors(), 1)
+	assert.Contains(t, report.Errors()[0].Message, "also the name of alice-gpu (environment x);")
+
+	_, err = LoadDeveloperEnvironment(configDir, "alice", "gpu", globalCfg)
+	assert.ErrorContains(t, err, "also the name of alice-gpu;")
+
+	_, report, err = CheckDeveloperEnvironment(configDir, "alice", "cpu", globalCfg)
+	require.NoError(t, err)
+	assert.Empty(t, report.Errors())
+}
+
+func TestListEnvironments(t *testing.T) {
+	configDir, _ := writeEnvironmentFixture(t, map[string]string{
+		"staging": "sshPort: 30012\n",
+		"gpu":     "sshPort: 30011\n",
+	})
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "alice", EnvironmentsDir, "README.md"), nil, 0o644))
+
+	environments, err := ListEnvironments(configDir, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gpu", "staging"}, environments)
+
+	environments, err = ListEnvironments(configDir, "bob")
+	require.NoError(t, err)
+	assert.Empty(t, environments)
+}
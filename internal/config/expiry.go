@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// expiresLayout is the date format of 'expires'.
+const expiresLayout = "2006-01-02"
+
+// expiryWarningDays is how long before 'expires' validation starts warning.
+const expiryWarningDays = 14
+
+// timeNow returns the current time; tests replace it.
+var timeNow = time.Now
+
+// ExpiresAt returns the moment the environment expires: the end of the
+// 'expires' day in UTC. ok is false when 'expires' is unset or invalid.
+func (c *DevEnvConfig) ExpiresAt() (expiresAt time.Time, ok bool) {
+	if c.Expires == "" {
+		return time.Time{}, false
+	}
+	day, err := time.Parse(expiresLayout, c.Expires)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return day.AddDate(0, 0, 1), true
+}
+
+// Expired reports whether the environment is past its 'expires' date.
+func (c *DevEnvConfig) Expired() bool {
+	expiresAt, ok := c.ExpiresAt()
+	return ok && !timeNow().Before(expiresAt)
+}
+
+// addExpiryIssues warns about environments that are past their 'expires'
+// date or reach it within expiryWarningDays.
+func addExpiryIssues(report *ValidationReport, config *DevEnvConfig) {
+	expiresAt, ok := config.ExpiresAt()
+	if !ok {
+		return
+	}
+	remaining := expiresAt.Sub(timeNow())
+	switch {
+	case remaining <= 0:
+		report.addWarning(ruleExpiresPast, fmt.Sprintf(
+			"environment expired on %s ('expires'); remove it or extend 'expires'", config.Expires))
+	case remaining <= expiryWarningDays*24*time.Hour:
+		days := int((remaining + 24*time.Hour - 1) / (24 * time.Hour))
+		report.addWarning(ruleExpiresSoon, fmt.Sprintf(
+			"environment expires on %s ('expires'), in %d day(s)", config.Expires, days))
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDevEnvConfig_ExpiresAt(t *testing.T) {
+	cfg := &DevEnvConfig{}
+	_, ok := cfg.ExpiresAt()
+	assert.False(t, ok)
+
+	cfg.Expires = "2025-12-31"
+	expiresAt, ok := cfg.ExpiresAt()
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), expiresAt, "the expiry day is included")
+}
+
+func TestCheck_Expires(t *testing.T) {
+	defer func(original func() time.Time) { timeNow = original }(timeNow)
+	timeNow = func() time.Time { return time.Date(2025, 12, 20, 12, 0, 0, 0, time.UTC) }
+
+	issues := func(expires string) []ValidationIssue {
+		var found []ValidationIssue
+		report := (&DevEnvConfig{Name: "intern", Expires: expires}).Check()
+		for _, issue := range append(report.Errors(), report.Warnings()...) {
+			if issue.Rule == ruleExpiresPast || issue.Rule == ruleExpiresSoon || issue.Rule == "expires:datetime" {
+				found = append(found, issue)
+			}
+		}
+		return found
+	}
+
+	assert.Empty(t, issues(""))
+	assert.Empty(t, issues("2026-06-30"))
+
+	soon := issues("2025-12-31")
+	if assert.Len(t, soon, 1) {
+		assert.Equal(t, ruleExpiresSoon, soon[0].Rule)
+		assert.Contains(t, soon[0].Message, "in 12 day(s)")
+	}
+
+	past := issues("2025-12-19")
+	if assert.Len(t, past, 1) {
+		assert.Equal(t, ruleExpiresPast, past[0].Rule)
+		assert.Equal(t, SeverityWarning, past[0].Severity)
+	}
+	assert.True(t, (&DevEnvConfig{Expires: "2025-12-19"}).Expired())
+	assert.False(t, (&DevEnvConfig{Expires: "2025-12-20"}).Expired())
+
+	invalid := issues("31.12.2025")
+	if assert.Len(t, invalid, 1) {
+		assert.Equal(t, SeverityError, invalid[0].Severity)
+		assert.Contains(t, invalid[0].Message, "YYYY-MM-DD")
+	}
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// DefaultGPUResource is the extended resource GPUs are requested as when
+// 'resources.gpuType' is unset.
+const DefaultGPUResource = "nvidia.com/gpu"
+
+// migProfileRe matches NVIDIA MIG profile names, e.g. "1g.10gb".
+var migProfileRe = regexp.MustCompile(`^[1-9][0-9]*g\.[1-9][0-9]*gb$`)
+
+// GPUTypeConfig describes a GPU type developers may request with
+// 'resources.gpuType': the extended resource its device plugin advertises,
+// how nodes with it are labeled and tainted, and the MIG profiles it can
+// be partitioned into.
+type GPUTypeConfig struct {
+	Resource     string            `yaml:"resource,omitempty"` // Defaults to nvidia.com/gpu
+	NodeSelector map[string]string `yaml:"nodeSelector,omitempty"`
+	Tolerations  []Toleration      `yaml:"tolerations,omitempty" validate:"dive"`
+	MIGProfiles  []string          `yaml:"migProfiles,omitempty"` // e.g. "1g.10gb", requested with 'resources.migProfile'
+}
+
+// requestedGPUType returns the entry of gpuTypes the environment requests
+// GPUs of, if it requests any.
+func (c *BaseConfig) requestedGPUType() (GPUTypeConfig, bool) {
+	if c.Resources.GPU <= 0 || c.Resources.GPUType == "" {
+		return GPUTypeConfig{}, false
+	}
+	gpuType, ok := c.GPUTypes[c.Resources.GPUType]
+	return gpuType, ok
+}
+
+// GPUResource returns the extended resource the environment requests its
+// GPUs as: the resource of 'resources.gpuType', or nvidia.com/gpu. With
+// 'resources.migProfile' it is the MIG slice in the same domain, as the
+// NVIDIA device plugin's mixed strategy advertises it (e.g.,
+// nvidia.com/mig-1g.10gb).
+func (c *BaseConfig) GPUResource() string {
+	resource := DefaultGPUResource
+	if gpuType, ok := c.requestedGPUType(); ok && gpuType.Resource != "" {
+		resource = gpuType.Resource
+	}
+	if c.Resources.MIGProfile != "" {
+		domain, _, _ := strings.Cut(resource, "/")
+		return domain + "/mig-" + c.Resources.MIGProfile
+	}
+	return resource
+}
+
+// addGPUTypeIssues checks 'resources.gpuType' and 'resources.migProfile'
+// against the gpuTypes allowed in devenv.yaml.
+func addGPUTypeIssues(report *ValidationReport, config *DevEnvConfig) {
+	resources := config.Resources
+	if _, ok := config.GPUTypes[resources.GPUType]; resources.GPUType != "" && !ok {
+		if len(config.GPUTypes) == 0 {
+			report.addError(ruleGPUTypeAllowed, fmt.Errorf(
+				"'resources.gpuType' %q is not allowed; no 'gpuTypes' are defined in devenv.yaml", resources.GPUType))
+		} else {
+			report.addError(ruleGPUTypeAllowed, fmt.Errorf(
+				"'resources.gpuType' %q is not allowed; use one of the 'gpuTypes' in devenv.yaml: %v", resources.GPUType, sortedKeys(config.GPUTypes)))
+		}
+	}
+
+	if resources.MIGProfile == "" {
+		return
+	}
+	if resources.GPUType == "" {
+		report.addError(ruleMIGProfileAllowed, fmt.Errorf(
+			"'resources.migProfile' %q requires a 'resources.gpuType' that allows it", resources.MIGProfile))
+		return
+	}
+	if gpuType, ok := config.GPUTypes[resources.GPUType]; ok && !slices.Contains(gpuType.MIGProfiles, resources.MIGProfile) {
+		report.addError(ruleMIGProfileAllowed, fmt.Errorf(
+			"'resources.migProfile' %q is not allowed for GPU type %q; use one of %v", resources.MIGProfile, resources.GPUType, gpuType.MIGProfiles))
+	}
+}
+
+// addGPUTypesIssues checks the gpuTypes of devenv.yaml: resources must be
+// qualified extended resource names and MIG profiles must look like
+// "1g.10gb".
+func addGPUTypesIssues(report *ValidationReport, base *BaseConfig) {
+	for _, name := range sortedKeys(base.GPUTypes) {
+		gpuType := base.GPUTypes[name]
+		if resource := gpuType.Resource; resource != "" {
+			if err := validateAnnotationKey(resource); err != nil || !strings.Contains(resource, "/") {
+				report.addError(ruleGPUTypesFormat, fmt.Errorf(
+					"'gpuTypes' %q has invalid resource %q; use a qualified extended resource name such as %s", name, resource, DefaultGPUResource))
+			}
+		}
+		for _, key := range sortedKeys(gpuType.NodeSelector) {
+			value := gpuType.NodeSelector[key]
+			if err := validateAnnotationKey(key); err != nil {
+				report.addError(ruleGPUTypesFormat, fmt.Errorf("'gpuTypes' %q nodeSelector key %q is invalid: %w", name, key, err))
+			} else if len(value) > maxDNSLabelLength || (value != "" && !annotationNameRe.MatchString(value)) {
+				report.addError(ruleGPUTypesFormat, fmt.Errorf("'gpuTypes' %q nodeSelector value %q for %q is invalid", name, value, key))
+			}
+		}
+		for _, profile := range gpuType.MIGProfiles {
+			if !migProfileRe.MatchString(profile) {
+				report.addError(ruleGPUTypesFormat, fmt.Errorf(
+					"'gpuTypes' %q has invalid MIG profile %q; use the form <compute>g.<memory>gb, e.g. 1g.10gb", name, profile))
+			}
+		}
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGPUTypes is a gpuTypes allowlist with an NVIDIA type that can be
+// partitioned and an AMD type.
+var testGPUTypes = map[string]GPUTypeConfig{
+	"a100": {
+		NodeSelector: map[string]string{"nvidia.com/gpu.product": "NVIDIA-A100-SXM4-80GB"},
+		Tolerations:  []Toleration{{Key: "nvidia.com/gpu", Operator: "Exists", Effect: "NoSchedule"}},
+		MIGProfiles:  []string{"1g.10gb", "3g.40gb"},
+	},
+	"mi250": {Resource: "amd.com/gpu", NodeSelector: map[string]string{"amd.com/gpu.product": "MI250"}},
+}
+
+func TestBaseConfig_GPUResource(t *testing.T) {
+	tests := map[string]struct {
+		resources ResourceConfig
+		want      string
+	}{
+		"default":          {ResourceConfig{GPU: 1}, "nvidia.com/gpu"},
+		"type":             {ResourceConfig{GPU: 1, GPUType: "a100"}, "nvidia.com/gpu"},
+		"other vendor":     {ResourceConfig{GPU: 2, GPUType: "mi250"}, "amd.com/gpu"},
+		"mig profile":      {ResourceConfig{GPU: 1, GPUType: "a100", MIGProfile: "1g.10gb"}, "nvidia.com/mig-1g.10gb"},
+		"unknown type":     {ResourceConfig{GPU: 1, GPUType: "h100"}, "nvidia.com/gpu"},
+		"type without gpu": {ResourceConfig{GPUType: "mi250"}, "nvidia.com/gpu"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := BaseConfig{Resources: tt.resources, GPUTypes: testGPUTypes}
+			assert.Equal(t, tt.want, cfg.GPUResource())
+		})
+	}
+}
+
+func TestBaseConfig_GPUTypeScheduling(t *testing.T) {
+	toleration := Toleration{Key: "dedicated", Operator: "Equal", Value: "ml", Effect: "NoSchedule"}
+	cfg := BaseConfig{
+		NodeSelector: map[string]string{"pool": "ml"},
+		Tolerations:  []Toleration{toleration},
+		Resources:    ResourceConfig{GPUType: "a100"},
+		GPUTypes:     testGPUTypes,
+	}
+	assert.Equal(t, map[string]string{"pool": "ml"}, cfg.PodNodeSelector(), "no GPUs are requested")
+	assert.Equal(t, []Toleration{toleration}, cfg.PodTolerations())
+
+	cfg.Resources.GPU = 1
+	assert.Equal(t, map[string]string{"pool": "ml", "nvidia.com/gpu.product": "NVIDIA-A100-SXM4-80GB"}, cfg.PodNodeSelector())
+	assert.Equal(t, append([]Toleration{toleration}, testGPUTypes["a100"].Tolerations...), cfg.PodTolerations())
+	assert.Equal(t, map[string]string{"pool": "ml"}, cfg.NodeSelector, "nodeSelector is not modified")
+}
+
+func TestCheck_GPUType(t *testing.T) {
+	rules := func(resources ResourceConfig, gpuTypes map[string]GPUTypeConfig) []string {
+		cfg := &DevEnvConfig{Name: "alice", BaseConfig: BaseConfig{Resources: resources, GPUTypes: gpuTypes}}
+		var ids []string
+		for _, issue := range cfg.Check().Errors() {
+			if issue.Rule == ruleGPUTypeAllowed || issue.Rule == ruleMIGProfileAllowed {
+				ids = append(ids, issue.Rule)
+			}
+		}
+		return ids
+	}
+
+	assert.Empty(t, rules(ResourceConfig{GPU: 1, GPUType: "a100", MIGProfile: "3g.40gb"}, testGPUTypes))
+	assert.Equal(t, []string{"resources.gpuType:allowed"}, rules(ResourceConfig{GPU: 1, GPUType: "h100"}, testGPUTypes))
+	assert.Equal(t, []string{"resources.gpuType:allowed"}, rules(ResourceConfig{GPU: 1, GPUType: "a100"}, nil))
+	assert.Equal(t, []string{"resources.migProfile:allowed"}, rules(ResourceConfig{GPU: 1, GPUType: "a100", MIGProfile: "7g.80gb"}, testGPUTypes))
+	assert.Equal(t, []string{"resources.migProfile:allowed"}, rules(ResourceConfig{GPU: 1, GPUType: "mi250", MIGProfile: "1g.10gb"}, testGPUTypes))
+	assert.Equal(t, []string{"resources.migProfile:allowed"}, rules(ResourceConfig{GPU: 1, MIGProfile: "1g.10gb"}, testGPUTypes))
+}
+
+func TestCheckBaseConfig_GPUTypes(t *testing.T) {
+	rules := func(gpuTypes map[string]GPUTypeConfig) []string {
+		var ids []string
+		for _, issue := range CheckBaseConfig(&BaseConfig{GPUTypes: gpuTypes}).Errors() {
+			ids = append(ids, issue.Rule)
+		}
+		return ids
+	}
+
+	assert.Empty(t, rules(testGPUTypes))
+	assert.Equal(t, []string{"gpuTypes:format"}, rules(map[string]GPUTypeConfig{"a100": {Resource: "gpu"}}))
+	assert.Equal(t, []string{"gpuTypes:format"}, rules(map[string]GPUTypeConfig{"a100": {MIGProfiles: []string{"1g-10gb"}}}))
+	assert.Equal(t, []string{"gpuTypes:format"}, rules(map[string]GPUTypeConfig{"a100": {NodeSelector: map[string]string{"gpu": "A100 80GB"}}}))
+}
+
+func TestLoadDeveloperConfig_GPUTypesAreGlobalOnly(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalConfigYAML := `gpuTypes:
+  mi250:
+    resource: amd.com/gpu
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalConfigYAML), 0o644))
+
+	developerDir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(developerDir, 0o755))
+	developerYAML := `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+sshPort: 30001
+resources:
+  gpu: 1
+  gpuType: mi250
+gpuTypes:
+  mi250:
+    resource: example.com/anything
+`
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, DeveloperConfigFile), []byte(developerYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+	alice, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, "amd.com/gpu", alice.GPUResource())
+	assert.Equal(t, "amd.com/gpu", globalCfg.GPUTypes["mi250"].Resource)
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DeveloperConfigFile is the name of the per-developer configuration file.
+const DeveloperConfigFile = "devenv-config.yaml"
+
+// DeveloperIndexEntry is a lightweight summary of a developer config used
+// for discovery and cross-developer checks (name and port conflicts) without
+// a full load, merge, and validation of every config in the repository.
+type DeveloperIndexEntry struct {
+	Developer  string // Directory name of the developer
+	ConfigPath string // Path to the developer's devenv-config.yaml
+	Name       string // Declared name, or the directory name when unset
+	SSHPort    int
+	HTTPPort   int
+	Err        error // Set when the config header could not be decoded
+}
+
+// configHeader holds the handful of fields decoded during indexing. Decoding
+// into this small struct skips normalization, merging, and validation.
+type configHeader struct {
+	Name     string `yaml:"name"`
+	SSHPort  int    `yaml:"sshPort"`
+	HTTPPort int    `yaml:"httpPort"`
+}
+
+// ListDeveloperDirs returns the names of subdirectories of configDir that
+// contain a devenv-config.yaml file. It only stats files and never parses them.
+func ListDeveloperDirs(configDir string) ([]string, error) {
+	var developers []string
+
+	entries, err := os.ReadDir(configDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config directory %s: %w", configDir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			// Check to make sure devenv-config.yaml exists in this directory
+			configPath := filepath.Join(configDir, entry.Name(), DeveloperConfigFile)
+			if _, err := os.Stat(configPath); err == nil {
+				developers = append(developers, entry.Name())
+			}
+		}
+	}
+
+	return developers, nil
+}
+
+// IndexDevelopers builds a lightweight index of every developer config in
+// configDir by decoding only the name and port fields of each file. Entries
+// whose header cannot be decoded are returned with Err set rather than
+// failing the whole scan.
+func IndexDevelopers(configDir string) ([]DeveloperIndexEntry, error) {
+	developers, err := ListDeveloperDirs(configDir)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make([]DeveloperIndexEntry, 0, len(developers))
+	for _, developer := range developers {
+		configPath := filepath.Join(configDir, developer, DeveloperConfigFile)
+		entry := DeveloperIndexEntry{Developer: developer, ConfigPath: configPath, Name: developer}
+
+		header, err := readConfigHeader(configPath)
+		if err != nil {
+			entry.Err = err
+		} else {
+			if name := strings.TrimSpace(header.Name); name != "" {
+				entry.Name = name
+			}
+			entry.SSHPort = header.SSHPort
+			entry.HTTPPort = header.HTTPPort
+		}
+		index = append(index, entry)
+	}
+
+	return index, nil
+}
+
+// readConfigHeader decodes the indexed fields of a developer config.
+func readConfigHeader(configPath string) (configHeader, error) {
+	var header configHeader
+
+	data, err := readConfigFile(configPath, defaultMaxFileSize)
+	if err != nil {
+		return header, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	}
+
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return header, fmt.Errorf("failed to parse YAML in %s: %w", configPath, err)
+	}
+	return header, nil
+}
+
+// IndexEnvironmentPorts returns the SSH ports declared by a developer's
+// named environments, keyed by environment name. Environments without an
+// sshPort, or whose header cannot be decoded, are left out.
+func IndexEnvironmentPorts(configDir, developerName string) (map[string]int, error) {
+	environments, err := ListEnvironments(configDir, developerName)
+	if err != nil {
+		return nil, err
+	}
+
+	ports := make(map[string]int)
+	for _, environment := range environments {
+		header, err := readConfigHeader(EnvironmentConfigPath(configDir, developerName, environment))
+		if err != nil || header.SSHPort == 0 {
+			continue
+		}
+		ports[environment] = header.SSHPort
+	}
+	return ports, nil
+}
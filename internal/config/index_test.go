@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexDevelopers(t *testing.T) {
+	tempDir := t.TempDir()
+	write := func(dir, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(tempDir, dir), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, dir, DeveloperConfigFile), []byte(content), 0o644))
+	}
+
+	write("alice", "name: alice\nsshPort: 30100\nhttpPort: 8080\nresources:\n  cpu: 4\n")
+	write("bob", "sshPort: 30101\n")         // name falls back to directory
+	write("broken", "name: [unterminated\n") // header cannot be decoded
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "no-config"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte("image: x\n"), 0o644))
+
+	dirs, err := ListDeveloperDirs(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob", "broken"}, dirs)
+
+	index, err := IndexDevelopers(tempDir)
+	require.NoError(t, err)
+	require.Len(t, index, 3)
+
+	assert.Equal(t, "alice", index[0].Name)
+	assert.Equal(t, 30100, index[0].SSHPort)
+	assert.Equal(t, 8080, index[0].HTTPPort)
+	assert.NoError(t, index[0].Err)
+
+	assert.Equal(t, "bob", index[1].Name)
+	assert.Equal(t, 30101, index[1].SSHPort)
+
+	assert.Equal(t, "broken", index[2].Developer)
+	assert.Error(t, index[2].Err)
+}
+
+func TestListDeveloperDirs_MissingDir(t *testing.T) {
+	_, err := ListDeveloperDirs(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestIndexEnvironmentPorts(t *testing.T) {
+	tempDir := t.TempDir()
+	environmentsDir := filepath.Join(tempDir, "alice", EnvironmentsDir)
+	require.NoError(t, os.MkdirAll(environmentsDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(environmentsDir, "gpu.yaml"), []byte("sshPort: 30101\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(environmentsDir, "noport.yaml"), []byte("image: x\n"), 0o644))
+
+	ports, err := IndexEnvironmentPorts(tempDir, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"gpu": 30101}, ports)
+
+	ports, err = IndexEnvironmentPorts(tempDir, "bob")
+	require.NoError(t, err)
+	assert.Empty(t, ports, "developers without environments have no ports")
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultIngressClassName is the IngressClass of the ingress-nginx controller
+// the generated Ingress has always targeted.
+const defaultIngressClassName = "nginx"
+
+// IngressConfig adapts the generated Ingress to the cluster's ingress
+// controller. Extra annotations for the Ingress are set with
+// annotations.ingress.
+type IngressConfig struct {
+	ClassName string `yaml:"className,omitempty" validate:"omitempty,max=253,hostname"`
+	// Additional hosts routed to the environment; "{name}" expands to the
+	// instance name (e.g., "{name}.lab.example.com")
+	ExtraHosts []string `yaml:"extraHosts,omitempty" validate:"dive,min=1"`
+	// Existing TLS secret to serve instead of the per-environment one
+	TLSSecret string `yaml:"tlsSecret,omitempty" validate:"omitempty,max=253,hostname"`
+}
+
+// IngressClassName returns the IngressClass of the generated Ingress,
+// defaulting to nginx.
+func (c *DevEnvConfig) IngressClassName() string {
+	if c.Ingress.ClassName != "" {
+		return c.Ingress.ClassName
+	}
+	return defaultIngressClassName
+}
+
+// IngressExtraHosts returns ingress.extraHosts with "{name}" expanded to the
+// instance name and lowercased, in configured order.
+func (c *DevEnvConfig) IngressExtraHosts() []string {
+	var hosts []string
+	for _, pattern := range c.Ingress.ExtraHosts {
+		hosts = append(hosts, strings.ToLower(expandNamePattern(pattern, c.InstanceName())))
+	}
+	return hosts
+}
+
+// IngressTLSSecret returns the TLS secret of the generated Ingress: the
+// configured ingress.tlsSecret, or the per-environment secret cert-manager
+// issues.
+func (c *DevEnvConfig) IngressTLSSecret() string {
+	if c.Ingress.TLSSecret != "" {
+		return c.Ingress.TLSSecret
+	}
+	name, _ := ResourceName(ResourceTLSSecret, c.InstanceName())
+	return name
+}
+
+// addIngressIssues checks that the expanded extra hosts are DNS names.
+func addIngressIssues(report *ValidationReport, config *DevEnvConfig) {
+	for i, host := range config.IngressExtraHosts() {
+		if len(host) > 253 || !annotationPrefixRe.MatchString(host) {
+			report.addError(ruleIngressExtraHost, fmt.Errorf(
+				"'ingress.extraHosts[%d]' must expand to a DNS name, got %q", i, host))
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngressConfig_Defaults(t *testing.T) {
+	cfg := &DevEnvConfig{Name: "alice"}
+	assert.Equal(t, "nginx", cfg.IngressClassName())
+	assert.Empty(t, cfg.IngressExtraHosts())
+	assert.Equal(t, "http-alice-tls", cfg.IngressTLSSecret())
+
+	cfg.Environment = "gpu"
+	cfg.Ingress = IngressConfig{
+		ClassName:  "traefik",
+		ExtraHosts: []string{"{name}.Lab.example.com", "shared.example.com"},
+		TLSSecret:  "wildcard-tls",
+	}
+	assert.Equal(t, "traefik", cfg.IngressClassName())
+	assert.Equal(t, []string{"alice-gpu.lab.example.com", "shared.example.com"}, cfg.IngressExtraHosts())
+	assert.Equal(t, "wildcard-tls", cfg.IngressTLSSecret())
+}
+
+func TestCheckDevEnvConfig_IngressExtraHosts(t *testing.T) {
+	cfg := &DevEnvConfig{
+		Name: "alice",
+		Git:  GitConfig{Name: "Alice", Email: "alice@example.com"},
+		BaseConfig: BaseConfig{
+			SSHPublicKey: "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host",
+			Ingress:      IngressConfig{ExtraHosts: []string{"{name}.lab.example.com"}},
+		},
+	}
+	assert.Empty(t, CheckDevEnvConfig(cfg).Issues)
+
+	cfg.Ingress.ExtraHosts = []string{"{name}_lab.example.com"}
+	report := CheckDevEnvConfig(cfg)
+	require.Len(t, report.Errors(), 1)
+	assert.Equal(t, "ingress.extraHosts:dns_name", report.Errors()[0].Rule)
+	assert.Contains(t, report.Errors()[0].Message, "alice_lab.example.com")
+}
+
+func TestLoadDeveloperConfigWithBaseConfig_MergesIngress(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalConfigYAML := `ingress:
+  className: traefik
+  extraHosts:
+    - "{name}.lab.example.com"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalConfigYAML), 0o644))
+
+	dir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	content := `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+ingress:
+  extraHosts:
+    - alice.example.org
+  tlsSecret: alice-example-org-tls
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DeveloperConfigFile), []byte(content), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+
+	alice, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "traefik", alice.IngressClassName())
+	assert.Equal(t, []string{"alice.lab.example.com", "alice.example.org"}, alice.IngressExtraHosts())
+	assert.Equal(t, "alice-example-org-tls", alice.IngressTLSSecret())
+	assert.Equal(t, []string{"{name}.lab.example.com"}, globalCfg.Ingress.ExtraHosts)
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// preemptiblePreStopCommand runs the pre-stop script of preemptible
+// environments, which warns the developer and runs their checkpoint hook.
+var preemptiblePreStopCommand = []string{"/bin/bash", "/scripts/pre-stop.sh"}
+
+// LifecycleConfig holds commands run in the environment's container right
+// after it starts and before it is stopped, e.g. to warm caches or flush
+// state. A developer command replaces the global command of the same hook.
+type LifecycleConfig struct {
+	PostStart []string `yaml:"postStart,omitempty"` // e.g. ["/bin/sh", "-c", "make warm-cache"]
+	PreStop   []string `yaml:"preStop,omitempty"`
+}
+
+// PreStopCommand returns the preStop command of the environment's
+// container. Preemptible environments run the pre-stop script, which runs
+// 'lifecycle.preStop' first when it is set.
+func (c *BaseConfig) PreStopCommand() []string {
+	if !c.Preemptible {
+		return c.Lifecycle.PreStop
+	}
+	command := append([]string{}, preemptiblePreStopCommand...)
+	return append(command, c.Lifecycle.PreStop...)
+}
+
+// addLifecycleIssues checks that the hook commands that are set are
+// non-empty and have no empty arguments, which the validator tags cannot
+// tell apart from unset ones.
+func addLifecycleIssues(report *ValidationReport, lifecycle LifecycleConfig) {
+	for _, hook := range []struct {
+		field   string
+		command []string
+	}{
+		{"lifecycle.postStart", lifecycle.PostStart},
+		{"lifecycle.preStop", lifecycle.PreStop},
+	} {
+		if hook.command == nil {
+			continue
+		}
+		if len(hook.command) == 0 {
+			report.addError(ruleLifecycleCommand, fmt.Errorf(
+				"'%s' must be a non-empty list of strings, e.g. [\"/bin/sh\", \"-c\", \"...\"]", hook.field))
+			continue
+		}
+		for i, arg := range hook.command {
+			if strings.TrimSpace(arg) == "" {
+				report.addError(ruleLifecycleCommand, fmt.Errorf("'%s[%d]' must not be empty", hook.field, i))
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreStopCommand(t *testing.T) {
+	cfg := &BaseConfig{}
+	assert.Nil(t, cfg.PreStopCommand())
+
+	cfg.Lifecycle.PreStop = []string{"/bin/sh", "-c", "sync"}
+	assert.Equal(t, []string{"/bin/sh", "-c", "sync"}, cfg.PreStopCommand())
+
+	// Preemptible environments pass the command to the pre-stop script
+	cfg.Preemptible = true
+	assert.Equal(t, []string{"/bin/bash", "/scripts/pre-stop.sh", "/bin/sh", "-c", "sync"}, cfg.PreStopCommand())
+	cfg.Lifecycle.PreStop = nil
+	assert.Equal(t, []string{"/bin/bash", "/scripts/pre-stop.sh"}, cfg.PreStopCommand())
+	assert.Equal(t, []string{"/bin/bash", "/scripts/pre-stop.sh"}, preemptiblePreStopCommand, "the script command is not modified")
+}
+
+func TestCheck_Lifecycle(t *testing.T) {
+	errors := func(lifecycle LifecycleConfig) []string {
+		cfg := &DevEnvConfig{Name: "alice", BaseConfig: BaseConfig{Lifecycle: lifecycle}}
+		var rules []string
+		for _, issue := range cfg.Check().Errors() {
+			if issue.Rule == ruleLifecycleCommand {
+				rules = append(rules, issue.Rule)
+			}
+		}
+		return rules
+	}
+
+	assert.Empty(t, errors(LifecycleConfig{}))
+	assert.Empty(t, errors(LifecycleConfig{PostStart: []string{"/bin/sh", "-c", "make warm-cache"}, PreStop: []string{"sync"}}))
+	assert.Equal(t, []string{"lifecycle:command"}, errors(LifecycleConfig{PostStart: []string{}}))
+	assert.Equal(t, []string{"lifecycle:command", "lifecycle:command"}, errors(LifecycleConfig{PreStop: []string{"", " "}}))
+}
+
+func TestLoadDeveloperConfig_Lifecycle(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalConfigYAML := `lifecycle:
+  postStart: ["/bin/sh", "-c", "warm-cache"]
+  preStop: ["sync"]
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalConfigYAML), 0o644))
+
+	developerDir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(developerDir, 0o755))
+	developerYAML := `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+sshPort: 30001
+lifecycle:
+  preStop: ["/bin/sh", "-c", "flush-state"]
+`
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, DeveloperConfigFile), []byte(developerYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+	alice, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+
+	// A developer command replaces the global command of the same hook
+	assert.Equal(t, LifecycleConfig{
+		PostStart: []string{"/bin/sh", "-c", "warm-cache"},
+		PreStop:   []string{"/bin/sh", "-c", "flush-state"},
+	}, alice.Lifecycle)
+	assert.Equal(t, []string{"sync"}, globalCfg.Lifecycle.PreStop, "global commands are not modified")
+}
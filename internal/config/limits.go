@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Default input limits. They are far above what a real developer config
+// needs and only exist to reject oversized or abusive payloads early.
+const (
+	defaultMaxFileSize = 256 * 1024 // bytes
+	defaultMaxSSHKeys  = 32
+	defaultMaxPackages = 500 // per package manager
+	defaultMaxVolumes  = 32
+)
+
+// InputLimits caps the size of config inputs. Zero values use the defaults.
+// Limits are read from validation.limits in devenv.yaml; devenv.yaml itself
+// is always read with the default file size limit.
+type InputLimits struct {
+	MaxFileSize int `yaml:"maxFileSize,omitempty" validate:"omitempty,min=1"` // Bytes per config file
+	MaxSSHKeys  int `yaml:"maxSSHKeys,omitempty" validate:"omitempty,min=1"`
+	MaxPackages int `yaml:"maxPackages,omitempty" validate:"omitempty,min=1"` // Per package manager
+	MaxVolumes  int `yaml:"maxVolumes,omitempty" validate:"omitempty,min=1"`
+}
+
+// orDefault returns limit, or fallback when limit is not set.
+func orDefault(limit, fallback int) int {
+	if limit > 0 {
+		return limit
+	}
+	return fallback
+}
+
+// readConfigFile reads path, failing without reading the rest of the file
+// once it exceeds maxSize bytes.
+func readConfigFile(path string, maxSize int) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSize {
+		return nil, fmt.Errorf("file is larger than %d bytes (see validation.limits.maxFileSize)", maxSize)
+	}
+	return data, nil
+}
+
+// addInputLimitIssues reports list fields of the merged config that exceed
+// the configured counts.
+func addInputLimitIssues(report *ValidationReport, config *DevEnvConfig) {
+	limits := config.Validation.Limits
+
+	if keys, err := config.GetSSHKeys(); err == nil {
+		addCountIssue(report, "sshPublicKey", len(keys), orDefault(limits.MaxSSHKeys, defaultMaxSSHKeys), "maxSSHKeys")
+	}
+
+	maxPackages := orDefault(limits.MaxPackages, defaultMaxPackages)
+	addCountIssue(report, "packages.python", len(config.Packages.Python), maxPackages, "maxPackages")
+	addCountIssue(report, "packages.apt", len(config.Packages.APT), maxPackages, "maxPackages")
+	addCountIssue(report, "packages.brew", len(config.Packages.Brew), maxPackages, "maxPackages")
+
+	addCountIssue(report, "volumes", len(config.Volumes), orDefault(limits.MaxVolumes, defaultMaxVolumes), "maxVolumes")
+}
+
+func addCountIssue(report *ValidationReport, field string, count, limit int, setting string) {
+	if count > limit {
+		report.addError(ruleID(field, "max_count"), fmt.Errorf(
+			"'%s' has %d entries, more than the limit of %d (see validation.limits.%s)", field, count, limit, setting))
+	}
+}
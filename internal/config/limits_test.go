@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "devenv.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("name: alice\n"), 0o644))
+
+	data, err := readConfigFile(path, 12)
+	require.NoError(t, err)
+	assert.Equal(t, "name: alice\n", string(data))
+
+	_, err = readConfigFile(path, 11)
+	assert.ErrorContains(t, err, "larger than 11 bytes")
+}
+
+func TestCheckDevEnvConfig_InputLimits(t *testing.T) {
+	cfg := &DevEnvConfig{
+		Name: "alice",
+		Git:  GitConfig{Name: "Alice", Email: "alice@example.com"},
+		BaseConfig: BaseConfig{
+			SSHPublicKey: []string{
+				"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAA alice@laptop",
+				"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAA alice@desktop",
+			},
+			Packages: PackageConfig{APT: []string{"vim", "git", "curl"}},
+			Volumes: []VolumeMount{
+				{Name: "data", LocalPath: "/mnt/data", ContainerPath: "/data"},
+			},
+		},
+	}
+	assert.Empty(t, CheckDevEnvConfig(cfg).Issues, "defaults are far above normal configs")
+
+	cfg.Validation.Limits = InputLimits{MaxSSHKeys: 1, MaxPackages: 2, MaxVolumes: 1}
+	report := CheckDevEnvConfig(cfg)
+	var rules []string
+	for _, issue := range report.Errors() {
+		rules = append(rules, issue.Rule)
+	}
+	assert.Equal(t, []string{"sshPublicKey:max_count", "packages.apt:max_count"}, rules)
+	assert.Contains(t, report.Err().Error(), "'packages.apt' has 3 entries, more than the limit of 2")
+}
+
+func TestLoadDeveloperConfigWithBaseConfig_MaxFileSize(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"),
+		[]byte("validation:\n  limits:\n    maxFileSize: 200\n"), 0o644))
+
+	dir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	developerYAML := "name: alice\nsshPublicKey: \"ssh-rsa AAAAB3NzaC1yc2E alice@example.com\"\n" +
+		"# " + strings.Repeat("x", 200) + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DeveloperConfigFile), []byte(developerYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+
+	_, err = LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	assert.ErrorContains(t, err, "larger than 200 bytes")
+
+	globalCfg.Validation.Limits.MaxFileSize = 0
+	_, err = LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	assert.NoError(t, err)
+}
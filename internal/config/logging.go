@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/nauticalab/devenv-engine/internal/labels"
+)
+
+// Log stores a LoggingSinkConfig can ship to, named after fluent-bit's
+// output plugins.
+const (
+	LogSinkLoki          = "loki"
+	LogSinkElasticsearch = "elasticsearch"
+	LogSinkForward       = "forward" // Fluentd or fluent-bit aggregator
+)
+
+// defaultLogSinkPorts are the ports the log stores listen on by default.
+var defaultLogSinkPorts = map[string]int{
+	LogSinkLoki:          3100,
+	LogSinkElasticsearch: 9200,
+	LogSinkForward:       24224,
+}
+
+// logLabelKeyRe matches label keys that are valid as pod labels, fluent-bit
+// record keys and Loki label names alike.
+var logLabelKeyRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// defaultLogPath is where the sidecar looks for log files, relative to the
+// home directory.
+const defaultLogPath = "logs/*.log"
+
+// defaultLogIndex is the Elasticsearch index logs are written to.
+const defaultLogIndex = "devenv-logs"
+
+// defaultLogShipperImage is the fluent-bit image of the sidecar.
+const defaultLogShipperImage = "fluent/fluent-bit:3.1"
+
+// LoggingConfig tags the environment's logs with the developer and Labels.
+// Without a sink, the labels are added to the pod, where a cluster-wide log
+// agent such as a fluent-bit DaemonSet picks them up with the container's
+// output. With a sink, a fluent-bit sidecar also ships the log files under
+// Paths to it.
+type LoggingConfig struct {
+	Enabled bool              `yaml:"enabled,omitempty"`
+	Sink    LoggingSinkConfig `yaml:"sink,omitempty"`
+	Labels  map[string]string `yaml:"labels,omitempty"` // e.g. team: ml
+	Paths   []string          `yaml:"paths,omitempty"`  // Globs relative to the home directory; defaults to logs/*.log
+	Image   string            `yaml:"image,omitempty" validate:"omitempty,min=1"`
+}
+
+// LoggingSinkConfig is the central log store the sidecar ships to.
+type LoggingSinkConfig struct {
+	Type  string `yaml:"type,omitempty" validate:"omitempty,oneof=loki elasticsearch forward"`
+	Host  string `yaml:"host,omitempty" validate:"omitempty,hostname_rfc1123"`
+	Port  int    `yaml:"port,omitempty" validate:"omitempty,min=1,max=65535"` // Defaults to the store's usual port
+	TLS   bool   `yaml:"tls,omitempty"`
+	Index string `yaml:"index,omitempty"` // Elasticsearch only; defaults to devenv-logs
+}
+
+// Sidecar reports whether a fluent-bit sidecar ships the log files.
+func (l LoggingConfig) Sidecar() bool {
+	return l.Enabled && l.Sink.Type != ""
+}
+
+// ShipperImage returns the image of the fluent-bit sidecar.
+func (l LoggingConfig) ShipperImage() string {
+	if l.Image != "" {
+		return l.Image
+	}
+	return defaultLogShipperImage
+}
+
+// LogPaths returns the globs of the log files the sidecar tails, relative
+// to the home directory.
+func (l LoggingConfig) LogPaths() []string {
+	if len(l.Paths) > 0 {
+		return l.Paths
+	}
+	return []string{defaultLogPath}
+}
+
+// SinkPort returns the port of the log store.
+func (s LoggingSinkConfig) SinkPort() int {
+	if s.Port != 0 {
+		return s.Port
+	}
+	return defaultLogSinkPorts[s.Type]
+}
+
+// SinkIndex returns the Elasticsearch index logs are written to.
+func (s LoggingSinkConfig) SinkIndex() string {
+	if s.Index != "" {
+		return s.Index
+	}
+	return defaultLogIndex
+}
+
+// addLoggingIssues checks that a sink has a host, that the labels are valid
+// and leave the labels devenv sets alone, and that the log paths stay in the
+// home directory, the only volume the sidecar mounts.
+func addLoggingIssues(report *ValidationReport, logging LoggingConfig) {
+	if logging.Sink.Type != "" && logging.Sink.Host == "" {
+		report.addError(ruleLoggingSinkHost, fmt.Errorf("'logging.sink.host' is required with 'logging.sink.type' %q", logging.Sink.Type))
+	}
+
+	for _, key := range sortedKeys(logging.Labels) {
+		value := logging.Labels[key]
+		if key == labels.App || key == labels.Developer || key == labels.Component {
+			report.addError(ruleLoggingLabels, fmt.Errorf("'logging.labels' must not set %q; it is managed by devenv", key))
+		} else if len(key) > maxDNSLabelLength || !logLabelKeyRe.MatchString(key) {
+			report.addError(ruleLoggingLabels, fmt.Errorf(
+				"'logging.labels' key %q is invalid; use letters, digits and '_', starting with a letter or '_'", key))
+		} else if len(value) > maxDNSLabelLength || !annotationNameRe.MatchString(value) {
+			report.addError(ruleLoggingLabels, fmt.Errorf("'logging.labels' value %q for %q is invalid", value, key))
+		}
+	}
+
+	for _, logPath := range logging.Paths {
+		clean := path.Clean(logPath)
+		if logPath == "" || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") || strings.ContainsAny(logPath, ", ") {
+			report.addError(ruleLoggingPaths, fmt.Errorf(
+				"'logging.paths' entry %q must be a glob relative to the home directory, without commas or spaces, e.g. %s", logPath, defaultLogPath))
+		}
+	}
+}
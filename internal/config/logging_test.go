@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingConfig_Defaults(t *testing.T) {
+	logging := LoggingConfig{Enabled: true}
+	assert.False(t, logging.Sidecar())
+	assert.Equal(t, "fluent/fluent-bit:3.1", logging.ShipperImage())
+	assert.Equal(t, []string{"logs/*.log"}, logging.LogPaths())
+
+	logging.Sink = LoggingSinkConfig{Type: LogSinkForward, Host: "fluentd.logging"}
+	assert.True(t, logging.Sidecar())
+	assert.Equal(t, 24224, logging.Sink.SinkPort())
+	assert.Equal(t, "devenv-logs", logging.Sink.SinkIndex())
+
+	logging.Enabled = false
+	assert.False(t, logging.Sidecar(), "a sink does not enable logging")
+}
+
+func TestCheck_Logging(t *testing.T) {
+	errors := func(logging LoggingConfig) []string {
+		cfg := &DevEnvConfig{Name: "alice", BaseConfig: BaseConfig{Logging: logging}}
+		var rules []string
+		for _, issue := range cfg.Check().Errors() {
+			switch issue.Rule {
+			case ruleLoggingSinkHost, ruleLoggingLabels, ruleLoggingPaths, "logging.sink.type:oneof":
+				rules = append(rules, issue.Rule)
+			}
+		}
+		return rules
+	}
+
+	assert.Empty(t, errors(LoggingConfig{
+		Enabled: true,
+		Sink:    LoggingSinkConfig{Type: LogSinkLoki, Host: "loki.logging.svc"},
+		Labels:  map[string]string{"team": "ml", "cost_center": "42"},
+		Paths:   []string{"logs/*.log", "runs/*/train.log"},
+	}))
+
+	assert.Equal(t, []string{"logging.sink.host:required"}, errors(LoggingConfig{Enabled: true, Sink: LoggingSinkConfig{Type: LogSinkLoki}}))
+	assert.Equal(t, []string{"logging.sink.type:oneof"}, errors(LoggingConfig{Sink: LoggingSinkConfig{Type: "splunk", Host: "splunk"}}))
+	assert.Equal(t, []string{"logging.labels:format"}, errors(LoggingConfig{Labels: map[string]string{"developer": "bob"}}))
+	assert.Equal(t, []string{"logging.labels:format"}, errors(LoggingConfig{Labels: map[string]string{"example.com/team": "ml"}}))
+	assert.Equal(t, []string{"logging.labels:format"}, errors(LoggingConfig{Labels: map[string]string{"team": ""}}))
+	assert.Equal(t, []string{"logging.paths:format", "logging.paths:format", "logging.paths:format"},
+		errors(LoggingConfig{Paths: []string{"/var/log/*.log", "../bob/logs/*.log", "logs/a.log,logs/b.log"}}))
+}
+
+func TestLoadDeveloperConfig_Logging(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalConfigYAML := `logging:
+  sink:
+    type: loki
+    host: loki.logging.svc
+  labels:
+    team: platform
+    site: lab
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalConfigYAML), 0o644))
+
+	developerDir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(developerDir, 0o755))
+	developerYAML := `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+sshPort: 30001
+logging:
+  enabled: true
+  labels:
+    team: ml
+`
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, DeveloperConfigFile), []byte(developerYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+	alice, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+
+	assert.True(t, alice.Logging.Sidecar())
+	assert.Equal(t, globalCfg.Logging.Sink, alice.Logging.Sink)
+	assert.Equal(t, map[string]string{"team": "ml", "site": "lab"}, alice.Logging.Labels)
+	assert.Equal(t, map[string]string{"team": "platform", "site": "lab"}, globalCfg.Logging.Labels, "global labels are not modified")
+}
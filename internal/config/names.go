@@ -0,0 +1,205 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// dnsLabelRe matches an RFC 1035 label as required for Kubernetes Service
+// names: lowercase alphanumerics and '-', starting with a letter and ending
+// with an alphanumeric character.
+var dnsLabelRe = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+
+// reservedDeveloperNames collide with system resources generated by devenv
+// or with names commonly used for cluster-wide components. "home" would
+// make the claim of its volume "alice" ("devenv-home-alice") the home claim
+// of developer "alice".
+var reservedDeveloperNames = []string{
+	"all",
+	"default",
+	"devenv",
+	"home",
+	"manager",
+	"namespace",
+	"system",
+}
+
+// reservedDeveloperNamePrefixes make derived resource names ambiguous or fall
+// under Kubernetes-reserved prefixes. For example, a developer named
+// "ssh-bob" would get a governing Service "devenv-ssh-bob", which is the
+// SSH Service generated for developer "bob", and the claim of volume "ice"
+// of a developer named "home-al" would be the home claim of "al-ice".
+var reservedDeveloperNamePrefixes = []string{
+	"home-",
+	"http-",
+	"kube-",
+	"ssh-",
+	"system-",
+}
+
+// addDeveloperNameIssues enforces the naming policy for developer names on
+// top of the basic hostname tag: DNS label syntax, reserved words, and
+// reserved prefixes.
+func addDeveloperNameIssues(report *ValidationReport, name string) {
+	if name == "" {
+		return // reported by the required tag
+	}
+
+	if !dnsLabelRe.MatchString(name) {
+		report.addError(ruleNameDNSLabel, fmt.Errorf(
+			"'name' must be a lowercase DNS label (letters, digits and '-', starting with a letter), got %q; try %q",
+			name, suggestDNSLabel(name)))
+	}
+
+	lower := strings.ToLower(name)
+	for _, reserved := range reservedDeveloperNames {
+		if lower == reserved {
+			report.addError(ruleNameReserved, fmt.Errorf(
+				"'name' %q is reserved for system resources; choose a different developer name", name))
+		}
+	}
+	for _, prefix := range reservedDeveloperNamePrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			report.addError(ruleNameReservedPrefix, fmt.Errorf(
+				"'name' %q must not start with reserved prefix %q; generated resource names would collide with other developers or system components",
+				name, prefix))
+		}
+	}
+}
+
+// suggestDNSLabel returns a best-effort DNS label derived from name, used
+// to make naming errors actionable.
+func suggestDNSLabel(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// CanonicalDeveloperName returns the key used to detect developer names that
+// would map to the same Kubernetes resources (e.g., "Alice" and "alice").
+func CanonicalDeveloperName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Resource identifiers accepted by ResourceName. Each maps to the naming
+// pattern used by the generated manifests.
+const (
+	ResourceDevEnv         = "devenv"          // StatefulSet, governing Service and app label
+	ResourceSSHService     = "ssh-service"     // NodePort Service for SSH
+	ResourceHTTPService    = "http-service"    // ClusterIP Service for HTTP
+	ResourceIngress        = "ingress"         // Ingress for HTTP access
+	ResourceEnvVars        = "env-vars"        // ConfigMap with environment variables
+	ResourceStartupScripts = "startup-scripts" // ConfigMap with startup scripts
+	ResourceTLSSecret      = "tls-secret"      // TLS Secret referenced by the Ingress
+	ResourceNetworkPolicy  = "network-policy"  // NetworkPolicy isolating the pod
+	ResourceHomeVolume     = "home-volume"     // PersistentVolumeClaim for the home directory
+	ResourceSupportAccess  = "support-access"  // ServiceAccount, Role and RoleBinding for read-only support access
+	ResourceNamespace      = "namespace"       // Namespace of the developer under the per-developer strategy
+	ResourceLogging        = "logging"         // ConfigMap with the fluent-bit config of the log shipping sidecar
+)
+
+// maxDNSLabelLength is the Kubernetes limit for DNS-1123/1035 label names.
+const maxDNSLabelLength = 63
+
+// maxStatefulSetNameLength leaves room for the "-<ordinal>" pod suffix and
+// the "controller-revision-hash" label ("<name>-<10 char hash>") that the
+// StatefulSet controller derives from the StatefulSet name.
+const maxStatefulSetNameLength = 52
+
+// nameHashLength is the number of hex characters appended to truncated names.
+const nameHashLength = 8
+
+type resourceNamePattern struct {
+	format    string // fmt pattern with a single %s for the developer name
+	maxLength int
+}
+
+var resourceNamePatterns = map[string]resourceNamePattern{
+	ResourceDevEnv:         {format: "devenv-%s", maxLength: maxStatefulSetNameLength},
+	ResourceSSHService:     {format: "devenv-ssh-%s", maxLength: maxDNSLabelLength},
+	ResourceHTTPService:    {format: "devenv-http-%s", maxLength: maxDNSLabelLength},
+	ResourceIngress:        {format: "devenv-ingress-%s", maxLength: maxDNSLabelLength},
+	ResourceEnvVars:        {format: "env-vars-%s", maxLength: maxDNSLabelLength},
+	ResourceStartupScripts: {format: "startup-scripts-%s", maxLength: maxDNSLabelLength},
+	ResourceTLSSecret:      {format: "http-%s-tls", maxLength: maxDNSLabelLength},
+	ResourceNetworkPolicy:  {format: "devenv-netpol-%s", maxLength: maxDNSLabelLength},
+	ResourceHomeVolume:     {format: "devenv-home-%s", maxLength: maxDNSLabelLength},
+	ResourceSupportAccess:  {format: "devenv-support-%s", maxLength: maxDNSLabelLength},
+	ResourceNamespace:      {format: "devenv-%s", maxLength: maxDNSLabelLength},
+	ResourceLogging:        {format: "devenv-logging-%s", maxLength: maxDNSLabelLength},
+}
+
+// ResourceName returns the Kubernetes name of a generated resource for a
+// developer. The result is always a valid DNS label: invalid characters are
+// replaced with '-', and names exceeding the resource's length limit are
+// truncated and suffixed with a short hash of the full name so distinct
+// developers never collide. The mapping is deterministic.
+func ResourceName(resource, developer string) (string, error) {
+	pattern, ok := resourceNamePatterns[resource]
+	if !ok {
+		return "", fmt.Errorf("unknown resource %q for name derivation", resource)
+	}
+	full := fmt.Sprintf(pattern.format, suggestDNSLabel(developer))
+	return truncateWithHash(full, pattern.maxLength), nil
+}
+
+// VolumeClaimName returns the name of the PersistentVolumeClaim generated
+// for a developer's volume with a size, e.g. "devenv-alice-datasets".
+// Volume names have no '-', so the name is unique per developer and volume
+// as long as no developer is named "home" or "home-*" (reserved above).
+func VolumeClaimName(volume, developer string) string {
+	full := fmt.Sprintf("devenv-%s-%s", suggestDNSLabel(developer), suggestDNSLabel(volume))
+	return truncateWithHash(full, maxDNSLabelLength)
+}
+
+// truncateWithHash shortens name to at most maxLength characters by keeping
+// a prefix and appending "-<hash>", where hash is derived from the full name.
+func truncateWithHash(name string, maxLength int) string {
+	if len(name) <= maxLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(sum[:])[:nameHashLength]
+	prefix := strings.TrimRight(name[:maxLength-nameHashLength-1], "-")
+	return prefix + "-" + suffix
+}
+
+// addResourceNameIssues warns when the developer name is long enough that
+// generated resource names must be shortened, so the hashed names are not a
+// surprise when inspecting the cluster.
+func addResourceNameIssues(report *ValidationReport, name string) {
+	if name == "" {
+		return
+	}
+	resources := make([]string, 0, len(resourceNamePatterns))
+	for resource := range resourceNamePatterns {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	var truncated []string
+	for _, resource := range resources {
+		pattern := resourceNamePatterns[resource]
+		full := fmt.Sprintf(pattern.format, suggestDNSLabel(name))
+		if len(full) > pattern.maxLength {
+			short, _ := ResourceName(resource, name)
+			truncated = append(truncated, fmt.Sprintf("%s → %s", full, short))
+		}
+	}
+	if len(truncated) > 0 {
+		report.addWarning(ruleNameTruncated, fmt.Sprintf(
+			"'name' %q is too long for some Kubernetes resource names, which will be shortened with a hash suffix: %s",
+			name, strings.Join(truncated, ", ")))
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceName(t *testing.T) {
+	t.Run("short names follow the manifest patterns", func(t *testing.T) {
+		cases := map[string]string{
+			ResourceDevEnv:         "devenv-alice",
+			ResourceSSHService:     "devenv-ssh-alice",
+			ResourceHTTPService:    "devenv-http-alice",
+			ResourceIngress:        "devenv-ingress-alice",
+			ResourceEnvVars:        "env-vars-alice",
+			ResourceStartupScripts: "startup-scripts-alice",
+			ResourceTLSSecret:      "http-alice-tls",
+			ResourceNetworkPolicy:  "devenv-netpol-alice",
+			ResourceHomeVolume:     "devenv-home-alice",
+			ResourceSupportAccess:  "devenv-support-alice",
+			ResourceNamespace:      "devenv-alice",
+			ResourceLogging:        "devenv-logging-alice",
+		}
+		for resource, want := range cases {
+			got, err := ResourceName(resource, "alice")
+			require.NoError(t, err)
+			assert.Equal(t, want, got, resource)
+		}
+	})
+
+	t.Run("long names are truncated deterministically with a hash", func(t *testing.T) {
+		long := strings.Repeat("a", 60)
+
+		first, err := ResourceName(ResourceStartupScripts, long)
+		require.NoError(t, err)
+		second, err := ResourceName(ResourceStartupScripts, long)
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.Len(t, first, maxDNSLabelLength)
+		assert.True(t, strings.HasPrefix(first, "startup-scripts-aaaa"))
+		assert.Regexp(t, `-[0-9a-f]{8}$`, first)
+
+		statefulSet, err := ResourceName(ResourceDevEnv, long)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(statefulSet), maxStatefulSetNameLength)
+	})
+
+	t.Run("distinct long names do not collide", func(t *testing.T) {
+		a, _ := ResourceName(ResourceStartupScripts, strings.Repeat("a", 58)+"x")
+		b, _ := ResourceName(ResourceStartupScripts, strings.Repeat("a", 58)+"y")
+		assert.NotEqual(t, a, b)
+	})
+
+	t.Run("invalid characters are sanitized", func(t *testing.T) {
+		got, err := ResourceName(ResourceDevEnv, "Alice.Smith")
+		require.NoError(t, err)
+		assert.Equal(t, "devenv-alice-smith", got)
+	})
+
+	t.Run("unknown resource", func(t *testing.T) {
+		_, err := ResourceName("cronjob", "alice")
+		assert.Error(t, err)
+	})
+}
+
+func TestVolumeClaimName(t *testing.T) {
+	assert.Equal(t, "devenv-alice-datasets", VolumeClaimName("datasets", "alice"))
+
+	// Only developers named "home" or "home-*" could take the home claim of
+	// another developer, and those names are reserved
+	cases := []struct{ developer, volume, owner string }{
+		{"home", "alice", "alice"},
+		{"home-al", "ice", "al-ice"},
+	}
+	for _, tc := range cases {
+		home, _ := ResourceName(ResourceHomeVolume, tc.owner)
+		assert.Equal(t, home, VolumeClaimName(tc.volume, tc.developer))
+
+		report := &ValidationReport{}
+		addDeveloperNameIssues(report, tc.developer)
+		assert.True(t, report.HasErrors(), tc.developer)
+	}
+}
+
+func TestCheckDevEnvConfig_WarnsOnTruncatedNames(t *testing.T) {
+	cfg := &DevEnvConfig{
+		Name: "a" + strings.Repeat("b", 50),
+		Git:  GitConfig{Name: "Dev", Email: "dev@example.com"},
+		BaseConfig: BaseConfig{
+			SSHPublicKey: "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host",
+		},
+	}
+
+	report := CheckDevEnvConfig(cfg)
+	require.NoError(t, report.Err())
+	require.Len(t, report.Warnings(), 1)
+	assert.Equal(t, "name:truncated", report.Warnings()[0].Rule)
+}
@@ -0,0 +1,22 @@
+package config
+
+// Values of 'namespaceStrategy'.
+const (
+	NamespaceShared       = "shared"        // All environments in 'namespace'
+	NamespacePerDeveloper = "per-developer" // Each developer's environments in devenv-<name>
+)
+
+// PerDeveloperNamespaces reports whether every developer gets a namespace
+// of their own instead of sharing 'namespace'.
+func (c *BaseConfig) PerDeveloperNamespaces() bool {
+	return c.NamespaceStrategy == NamespacePerDeveloper
+}
+
+// applyNamespaceStrategy moves the environment into the developer's own
+// namespace under the per-developer strategy. Named environments share
+// the developer's namespace.
+func (c *DevEnvConfig) applyNamespaceStrategy() {
+	if c.PerDeveloperNamespaces() && c.Name != "" {
+		c.Namespace, _ = ResourceName(ResourceNamespace, c.Name)
+	}
+}
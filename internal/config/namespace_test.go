@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDeveloperConfig_NamespaceStrategy(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalConfigYAML := "namespace: devenv\nnamespaceStrategy: per-developer\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalConfigYAML), 0o644))
+
+	developerDir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(filepath.Join(developerDir, EnvironmentsDir), 0o755))
+	// A developer can neither pick another namespace nor opt out of the strategy
+	developerYAML := `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+sshPort: 30001
+namespace: elsewhere
+namespaceStrategy: shared
+`
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, DeveloperConfigFile), []byte(developerYAML), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, EnvironmentsDir, "gpu.yaml"), []byte("sshPort: 30002\nnamespace: gpu\n"), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, "devenv", globalCfg.Namespace)
+
+	alice, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.True(t, alice.PerDeveloperNamespaces())
+	assert.Equal(t, "devenv-alice", alice.Namespace)
+	assert.Equal(t, "devenv-alice-0.devenv-alice.devenv-alice.svc.cluster.local", alice.PodFQDN())
+
+	// Environments share the developer's namespace
+	gpu, err := LoadDeveloperEnvironment(tempDir, "alice", "gpu", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "devenv-alice", gpu.Namespace)
+}
+
+func TestCheckBaseConfig_NamespaceStrategy(t *testing.T) {
+	assert.NoError(t, CheckBaseConfig(&BaseConfig{NamespaceStrategy: NamespacePerDeveloper}).Err())
+	assert.NoError(t, CheckBaseConfig(&BaseConfig{NamespaceStrategy: NamespaceShared}).Err())
+	assert.Error(t, CheckBaseConfig(&BaseConfig{NamespaceStrategy: "per-team"}).Err())
+}
@@ -0,0 +1,37 @@
+package config
+
+// Network isolation modes for network.isolation.
+const (
+	NetworkIsolationOpen   = "open"   // No NetworkPolicy; all traffic is allowed (default)
+	NetworkIsolationStrict = "strict" // Only SSH, HTTP from the ingress controller and the allowed sources
+)
+
+// defaultIngressNamespace is where the nginx ingress controller used by the
+// generated Ingress usually runs.
+const defaultIngressNamespace = "ingress-nginx"
+
+// NetworkConfig controls the NetworkPolicy generated for each environment.
+// With strict isolation, ingress to the pod is limited to SSH (port 22,
+// reached through the NodePort Service), the HTTP port from the ingress
+// controller's namespace, any port from AllowedNamespaces, and AllowedPorts
+// from anywhere.
+type NetworkConfig struct {
+	Isolation         string   `yaml:"isolation,omitempty" validate:"omitempty,oneof=strict open"`
+	IngressNamespace  string   `yaml:"ingressNamespace,omitempty" validate:"omitempty,max=63,hostname"`
+	AllowedNamespaces []string `yaml:"allowedNamespaces,omitempty" validate:"dive,min=1,max=63,hostname"`
+	AllowedPorts      []int    `yaml:"allowedPorts,omitempty" validate:"dive,min=1,max=65535"`
+}
+
+// Strict reports whether a NetworkPolicy restricting ingress is generated.
+func (n NetworkConfig) Strict() bool {
+	return n.Isolation == NetworkIsolationStrict
+}
+
+// IngressControllerNamespace returns the namespace HTTP traffic is allowed
+// from, defaulting to ingress-nginx.
+func (n NetworkConfig) IngressControllerNamespace() string {
+	if n.IngressNamespace != "" {
+		return n.IngressNamespace
+	}
+	return defaultIngressNamespace
+}
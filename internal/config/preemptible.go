@@ -0,0 +1,49 @@
+package config
+
+// PreemptibleLabel is the node label and taint key that mark preemptible
+// (spot) nodes unless preemptibleNodes in devenv.yaml names others.
+const PreemptibleLabel = "devenv.io/preemptible"
+
+// PreemptibleNodesConfig describes how the cluster marks preemptible nodes,
+// e.g. cloud.google.com/gke-spot on GKE. Environments with 'preemptible'
+// set get this node selector and these tolerations.
+type PreemptibleNodesConfig struct {
+	NodeSelector map[string]string `yaml:"nodeSelector,omitempty"`
+	Tolerations  []Toleration      `yaml:"tolerations,omitempty" validate:"dive"`
+}
+
+// preemptibleNodes returns the node selector and tolerations of
+// preemptible nodes: those from devenv.yaml, or the PreemptibleLabel
+// label and taint when none are configured.
+func (c *BaseConfig) preemptibleNodes() (map[string]string, []Toleration) {
+	nodes := c.PreemptibleNodes
+	if len(nodes.NodeSelector) == 0 && len(nodes.Tolerations) == 0 {
+		return map[string]string{PreemptibleLabel: "true"},
+			[]Toleration{{Key: PreemptibleLabel, Operator: "Exists", Effect: "NoSchedule"}}
+	}
+	return nodes.NodeSelector, nodes.Tolerations
+}
+
+// PodTolerations returns the tolerations of the environment's pod:
+// 'tolerations' plus those of preemptible nodes when 'preemptible' is set
+// and those of the GPU type when 'resources.gpuType' is set.
+func (c *BaseConfig) PodTolerations() []Toleration {
+	tolerations := c.Tolerations
+	if c.Preemptible {
+		_, preemptible := c.preemptibleNodes()
+		tolerations = mergeTolerations(tolerations, preemptible)
+	}
+	if gpuType, ok := c.requestedGPUType(); ok {
+		tolerations = mergeTolerations(tolerations, gpuType.Tolerations)
+	}
+	return tolerations
+}
+
+// addPreemptibleIssues warns about preemptible GPU environments whose home
+// directory lives on the node, where it is lost when the node is reclaimed.
+func addPreemptibleIssues(report *ValidationReport, config *DevEnvConfig) {
+	if config.Preemptible && config.GPU() > 0 && !config.HomeVolumeClaim() {
+		report.addWarning(rulePreemptibleHomeVolume,
+			"'preemptible' GPU environment keeps the home directory on a host path of the node, so work is lost when the node is reclaimed; set 'resources.storageClass' to store it on a PersistentVolumeClaim")
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseConfig_PreemptibleScheduling(t *testing.T) {
+	toleration := Toleration{Key: "dedicated", Operator: "Equal", Value: "gpu", Effect: "NoSchedule"}
+	cfg := BaseConfig{
+		NodeSelector: map[string]string{"pool": "general"},
+		Tolerations:  []Toleration{toleration},
+	}
+	assert.Equal(t, map[string]string{"pool": "general"}, cfg.PodNodeSelector())
+	assert.Equal(t, []Toleration{toleration}, cfg.PodTolerations())
+
+	// Default label and taint
+	cfg.Preemptible = true
+	assert.Equal(t, map[string]string{"pool": "general", PreemptibleLabel: "true"}, cfg.PodNodeSelector())
+	assert.Equal(t, []Toleration{toleration, {Key: PreemptibleLabel, Operator: "Exists", Effect: "NoSchedule"}}, cfg.PodTolerations())
+
+	// Configured preemptible nodes, with 'nodeSelector' taking precedence
+	spot := Toleration{Key: "cloud.google.com/gke-spot", Operator: "Equal", Value: "true", Effect: "NoSchedule"}
+	cfg.PreemptibleNodes = PreemptibleNodesConfig{
+		NodeSelector: map[string]string{"cloud.google.com/gke-spot": "true", "pool": "spot"},
+		Tolerations:  []Toleration{spot},
+	}
+	assert.Equal(t, map[string]string{"pool": "general", "cloud.google.com/gke-spot": "true"}, cfg.PodNodeSelector())
+	assert.Equal(t, []Toleration{toleration, spot}, cfg.PodTolerations())
+	assert.Equal(t, map[string]string{"pool": "general"}, cfg.NodeSelector, "nodeSelector is not modified")
+}
+
+func TestCheck_PreemptibleHomeVolume(t *testing.T) {
+	warnings := func(cfg *DevEnvConfig) []string {
+		var ids []string
+		for _, issue := range cfg.Check().Warnings() {
+			if issue.Rule == rulePreemptibleHomeVolume {
+				ids = append(ids, issue.Rule)
+			}
+		}
+		return ids
+	}
+	cfg := &DevEnvConfig{
+		Name:       "alice",
+		BaseConfig: BaseConfig{Preemptible: true, Resources: ResourceConfig{GPU: 1}},
+	}
+	assert.Equal(t, []string{"preemptible:home_volume"}, warnings(cfg))
+
+	cfg.Resources.StorageClass = "fast-ssd"
+	assert.Empty(t, warnings(cfg))
+
+	cfg.Resources = ResourceConfig{}
+	assert.Empty(t, warnings(cfg), "CPU-only environments are not warned about")
+}
+
+func TestLoadDeveloperConfig_PreemptibleNodesAreGlobalOnly(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalConfigYAML := `preemptibleNodes:
+  nodeSelector:
+    cloud.google.com/gke-spot: "true"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalConfigYAML), 0o644))
+
+	developerDir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(developerDir, 0o755))
+	developerYAML := `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+sshPort: 30001
+preemptible: true
+preemptibleNodes:
+  nodeSelector:
+    pool: anything
+`
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, DeveloperConfigFile), []byte(developerYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+	alice, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"cloud.google.com/gke-spot": "true"}, alice.PodNodeSelector())
+	assert.Equal(t, map[string]string{"cloud.google.com/gke-spot": "true"}, globalCfg.PreemptibleNodes.NodeSelector)
+}
@@ -0,0 +1,112 @@
+package config
+
+import "fmt"
+
+// ProbesConfig configures the probes of the environment's container. A
+// developer probe replaces the global probe of the same kind.
+type ProbesConfig struct {
+	Liveness  *Probe `yaml:"liveness,omitempty"`
+	Readiness *Probe `yaml:"readiness,omitempty"` // Defaults to a TCP probe of SSH
+	Startup   *Probe `yaml:"startup,omitempty"`
+}
+
+// Probe is a container probe, written as in a Kubernetes container spec.
+// Exactly one of Exec, TCPSocket and HTTPGet must be set.
+type Probe struct {
+	Exec                *ExecAction      `yaml:"exec,omitempty"`
+	TCPSocket           *TCPSocketAction `yaml:"tcpSocket,omitempty"`
+	HTTPGet             *HTTPGetAction   `yaml:"httpGet,omitempty"`
+	InitialDelaySeconds int              `yaml:"initialDelaySeconds,omitempty" validate:"omitempty,min=0"`
+	PeriodSeconds       int              `yaml:"periodSeconds,omitempty" validate:"omitempty,min=1"`
+	TimeoutSeconds      int              `yaml:"timeoutSeconds,omitempty" validate:"omitempty,min=1"`
+	SuccessThreshold    int              `yaml:"successThreshold,omitempty" validate:"omitempty,min=1"`
+	FailureThreshold    int              `yaml:"failureThreshold,omitempty" validate:"omitempty,min=1"`
+}
+
+// ExecAction runs a command in the container; exit status 0 is success.
+type ExecAction struct {
+	Command []string `yaml:"command" validate:"required,min=1"`
+}
+
+// TCPSocketAction succeeds when a TCP connection to the port can be opened.
+type TCPSocketAction struct {
+	Port int `yaml:"port" validate:"required,min=1,max=65535"`
+}
+
+// HTTPGetAction succeeds when a GET request returns a status from 200 to 399.
+type HTTPGetAction struct {
+	Path   string `yaml:"path,omitempty" validate:"omitempty,startswith=/"`
+	Port   int    `yaml:"port" validate:"required,min=1,max=65535"`
+	Scheme string `yaml:"scheme,omitempty" validate:"omitempty,oneof=HTTP HTTPS"`
+}
+
+// defaultReadinessProbe marks the environment ready once sshd accepts
+// connections.
+var defaultReadinessProbe = Probe{
+	TCPSocket:           &TCPSocketAction{Port: 22},
+	InitialDelaySeconds: 5,
+	PeriodSeconds:       10,
+	SuccessThreshold:    1,
+	FailureThreshold:    6,
+}
+
+// ReadinessProbe returns the readiness probe of the environment's
+// container: 'probes.readiness', or a TCP probe of SSH.
+func (c *BaseConfig) ReadinessProbe() *Probe {
+	if c.Probes.Readiness != nil {
+		return c.Probes.Readiness
+	}
+	probe := defaultReadinessProbe
+	return &probe
+}
+
+// mergeProbes returns the global probes with those set by the user
+// replacing the global probe of the same kind.
+func mergeProbes(global, user ProbesConfig) ProbesConfig {
+	if user.Liveness == nil {
+		user.Liveness = global.Liveness
+	}
+	if user.Readiness == nil {
+		user.Readiness = global.Readiness
+	}
+	if user.Startup == nil {
+		user.Startup = global.Startup
+	}
+	return user
+}
+
+// addProbeIssues checks the probes against the Kubernetes rules the
+// validator tags cannot express, and warns about a liveness probe that
+// may restart the environment while it is still starting.
+func addProbeIssues(report *ValidationReport, probes ProbesConfig) {
+	for _, probe := range []struct {
+		field string
+		probe *Probe
+	}{
+		{"probes.liveness", probes.Liveness},
+		{"probes.readiness", probes.Readiness},
+		{"probes.startup", probes.Startup},
+	} {
+		if probe.probe == nil {
+			continue
+		}
+		handlers := 0
+		for _, set := range []bool{probe.probe.Exec != nil, probe.probe.TCPSocket != nil, probe.probe.HTTPGet != nil} {
+			if set {
+				handlers++
+			}
+		}
+		if handlers != 1 {
+			report.addError(ruleProbeHandler, fmt.Errorf("'%s' must set exactly one of 'exec', 'tcpSocket' or 'httpGet'", probe.field))
+		}
+		if probe.field != "probes.readiness" && probe.probe.SuccessThreshold > 1 {
+			report.addError(ruleProbeSuccessThreshold, fmt.Errorf(
+				"'%s.successThreshold' must be 1, got %d", probe.field, probe.probe.SuccessThreshold))
+		}
+	}
+
+	if probes.Liveness != nil && probes.Startup == nil {
+		report.addWarning(ruleProbeLivenessStartup,
+			"'probes.liveness' is set without 'probes.startup'; sshd only starts after packages are installed, so the liveness probe may restart the environment before it is up; add a startup probe that allows for the first start")
+	}
+}
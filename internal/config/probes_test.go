@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseConfig_ReadinessProbe(t *testing.T) {
+	cfg := BaseConfig{}
+	assert.Equal(t, &Probe{
+		TCPSocket:           &TCPSocketAction{Port: 22},
+		InitialDelaySeconds: 5,
+		PeriodSeconds:       10,
+		SuccessThreshold:    1,
+		FailureThreshold:    6,
+	}, cfg.ReadinessProbe())
+
+	probe := &Probe{HTTPGet: &HTTPGetAction{Path: "/healthz", Port: 8080}}
+	cfg.Probes.Readiness = probe
+	assert.Same(t, probe, cfg.ReadinessProbe())
+}
+
+func TestCheck_Probes(t *testing.T) {
+	issues := func(probes ProbesConfig) (errors, warnings []string) {
+		cfg := &DevEnvConfig{Name: "alice", BaseConfig: BaseConfig{Probes: probes}}
+		report := cfg.Check()
+		for _, issue := range report.Errors() {
+			if issue.Rule == ruleProbeHandler || issue.Rule == ruleProbeSuccessThreshold || issue.Rule == "probes.liveness.tcpSocket.port:max" {
+				errors = append(errors, issue.Rule)
+			}
+		}
+		for _, issue := range report.Warnings() {
+			if issue.Rule == ruleProbeLivenessStartup {
+				warnings = append(warnings, issue.Rule)
+			}
+		}
+		return errors, warnings
+	}
+
+	tcp := &Probe{TCPSocket: &TCPSocketAction{Port: 22}}
+	errors, warnings := issues(ProbesConfig{Liveness: tcp, Startup: &Probe{TCPSocket: &TCPSocketAction{Port: 22}, FailureThreshold: 60}})
+	assert.Empty(t, errors)
+	assert.Empty(t, warnings)
+
+	errors, warnings = issues(ProbesConfig{Liveness: tcp})
+	assert.Empty(t, errors)
+	assert.Equal(t, []string{"probes.liveness:without_startup"}, warnings)
+
+	errors, _ = issues(ProbesConfig{Readiness: &Probe{PeriodSeconds: 5}})
+	assert.Equal(t, []string{"probes:handler"}, errors)
+
+	errors, _ = issues(ProbesConfig{Readiness: &Probe{Exec: &ExecAction{Command: []string{"true"}}, TCPSocket: &TCPSocketAction{Port: 22}}})
+	assert.Equal(t, []string{"probes:handler"}, errors)
+
+	errors, _ = issues(ProbesConfig{Liveness: &Probe{TCPSocket: &TCPSocketAction{Port: 70000}, SuccessThreshold: 2}, Startup: tcp})
+	assert.ElementsMatch(t, []string{"probes.liveness.tcpSocket.port:max", "probes:success_threshold"}, errors)
+
+	errors, _ = issues(ProbesConfig{Readiness: &Probe{TCPSocket: &TCPSocketAction{Port: 22}, SuccessThreshold: 2}})
+	assert.Empty(t, errors, "readiness probes may require several successes")
+}
+
+func TestLoadDeveloperConfig_Probes(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalConfigYAML := `probes:
+  readiness:
+    tcpSocket:
+      port: 22
+    periodSeconds: 30
+  startup:
+    tcpSocket:
+      port: 22
+    failureThreshold: 60
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalConfigYAML), 0o644))
+
+	developerDir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(developerDir, 0o755))
+	// The developer's readiness probe replaces the global one as a whole
+	developerYAML := `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+sshPort: 30001
+probes:
+  readiness:
+    httpGet:
+      path: /healthz
+      port: 8080
+`
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, DeveloperConfigFile), []byte(developerYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+	alice, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, &Probe{HTTPGet: &HTTPGetAction{Path: "/healthz", Port: 8080}}, alice.ReadinessProbe())
+	assert.Equal(t, globalCfg.Probes.Startup, alice.Probes.Startup)
+	assert.Nil(t, alice.Probes.Liveness)
+	assert.Equal(t, &Probe{TCPSocket: &TCPSocketAction{Port: 22}, PeriodSeconds: 30}, globalCfg.Probes.Readiness, "global probes are not modified")
+}
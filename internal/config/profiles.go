@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ProfileConfig is a named bundle of settings defined under 'profiles' in
+// devenv.yaml (e.g., "ml-gpu"). A developer selects one with 'profile' and
+// it is applied between the global config and the developer config:
+// resources override the global values field by field, while packages,
+// volumes and env are merged additively like developer values.
+type ProfileConfig struct {
+	Resources ResourceConfig    `yaml:"resources,omitempty"`
+	Packages  PackageConfig     `yaml:"packages,omitempty"`
+	Volumes   []VolumeMount     `yaml:"volumes,omitempty" validate:"dive"`
+	Env       map[string]string `yaml:"env,omitempty"`
+}
+
+// ProfileNames returns the names of the defined profiles in sorted order.
+func (c *BaseConfig) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// withProfile returns a copy of the config with the named profile applied.
+// An empty name returns the config itself. The receiver is never modified.
+func (c *BaseConfig) withProfile(name string) (*BaseConfig, error) {
+	if name == "" {
+		return c, nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		if len(c.Profiles) == 0 {
+			return nil, fmt.Errorf("'profile' %q is not defined; devenv.yaml has no profiles", name)
+		}
+		return nil, fmt.Errorf("'profile' %q is not defined in devenv.yaml (available: %s)",
+			name, strings.Join(c.ProfileNames(), ", "))
+	}
+
+	layered := *c
+	layered.Resources = overlayResources(c.Resources, profile.Resources)
+	layered.Packages = PackageConfig{
+		Python: mergeStringSlices(c.Packages.Python, profile.Packages.Python),
+		APT:    mergeStringSlices(c.Packages.APT, profile.Packages.APT),
+		Brew:   mergeStringSlices(c.Packages.Brew, profile.Packages.Brew),
+	}
+	layered.Volumes = mergeVolumes(c.Volumes, profile.Volumes)
+	layered.Env = mergeMaps(c.Env, profile.Env)
+	return &layered, nil
+}
+
+// overlayResources returns base with every field that is set in override
+// replaced.
+func overlayResources(base, override ResourceConfig) ResourceConfig {
+	if override.CPU != nil {
+		base.CPU = override.CPU
+	}
+	if override.Memory != nil {
+		base.Memory = override.Memory
+	}
+	if override.Storage != "" {
+		base.Storage = override.Storage
+	}
+	if override.StorageClass != "" {
+		base.StorageClass = override.StorageClass
+	}
+	if override.GPU != 0 {
+		base.GPU = override.GPU
+	}
+	if override.Limits.CPU != nil {
+		base.Limits.CPU = override.Limits.CPU
+	}
+	if override.Limits.Memory != nil {
+		base.Limits.Memory = override.Limits.Memory
+	}
+	if override.GPUType != "" {
+		base.GPUType = override.GPUType
+	}
+	if override.MIGProfile != "" {
+		base.MIGProfile = override.MIGProfile
+	}
+	return base
+}
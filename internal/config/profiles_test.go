@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profilesGlobalYAML = `resources:
+  cpu: 2
+  memory: 8Gi
+packages:
+  apt: [vim]
+env:
+  LOG_LEVEL: info
+profiles:
+  ml-gpu:
+    resources:
+      memory: 64Gi
+      gpu: 1
+    packages:
+      python: [torch]
+    volumes:
+      - name: datasets
+        localPath: /mnt/datasets
+        containerPath: /datasets
+    env:
+      CUDA_VISIBLE_DEVICES: "0"
+  small:
+    resources:
+      cpu: 1
+`
+
+// writeProfileFixture creates a config dir with the profiles above and
+// developer alice using developerYAML.
+func writeProfileFixture(t *testing.T, developerYAML string) (string, *BaseConfig) {
+	t.Helper()
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(profilesGlobalYAML), 0o644))
+
+	dir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	developerYAML = "name: alice\nsshPort: 30010\nsshPublicKey: \"ssh-rsa AAAAB3NzaC1yc2E alice@example.com\"\n" + developerYAML
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DeveloperConfigFile), []byte(developerYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+	return tempDir, globalCfg
+}
+
+func TestLoadDeveloperConfigWithBaseConfig_Profile(t *testing.T) {
+	configDir, globalCfg := writeProfileFixture(t, "profile: ml-gpu\nresources:\n  gpu: 2\n"+
+		"packages:\n  python: [numpy]\nenv:\n  LOG_LEVEL: debug\n")
+
+	cfg, err := LoadDeveloperConfigWithBaseConfig(configDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "ml-gpu", cfg.Profile)
+	assert.Equal(t, 2, cfg.Resources.CPU, "global value kept when the profile does not set it")
+	assert.Equal(t, "64Gi", cfg.Resources.Memory, "profile overrides global")
+	assert.Equal(t, 2, cfg.Resources.GPU, "developer overrides profile")
+	assert.Equal(t, []string{"vim"}, cfg.Packages.APT)
+	assert.Equal(t, []string{"torch", "numpy"}, cfg.Packages.Python)
+	require.Len(t, cfg.Volumes, 1)
+	assert.Equal(t, "datasets", cfg.Volumes[0].Name)
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "debug", "CUDA_VISIBLE_DEVICES": "0"}, cfg.Env)
+	assert.Nil(t, cfg.Profiles, "profiles are consumed while loading")
+
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "info"}, globalCfg.Env, "global config must not be modified")
+	assert.Equal(t, "8Gi", globalCfg.Resources.Memory)
+	assert.Empty(t, globalCfg.Packages.Python)
+}
+
+func TestCheckDeveloperConfig_UnknownProfile(t *testing.T) {
+	configDir, globalCfg := writeProfileFixture(t, "profile: huge\n")
+
+	cfg, report, err := CheckDeveloperConfig(configDir, "alice", globalCfg)
+	require.NoError(t, err)
+	require.Len(t, report.Errors(), 1)
+	assert.Equal(t, "profile:unknown", report.Errors()[0].Rule)
+	assert.Equal(t, `'profile' "huge" is not defined in devenv.yaml (available: ml-gpu, small)`, report.Errors()[0].Message)
+	assert.Equal(t, "8Gi", cfg.Resources.Memory, "the global config is used without the profile")
+}
+
+func TestCheckDeveloperEnvironment_Profile(t *testing.T) {
+	configDir, globalCfg := writeProfileFixture(t, "profile: small\n")
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "alice", EnvironmentsDir), 0o755))
+	require.NoError(t, os.WriteFile(EnvironmentConfigPath(configDir, "alice", "gpu"),
+		[]byte("profile: ml-gpu\nsshPort: 30011\nimage: nvidia/cuda:12.4.0-base-ubuntu22.04\n"), 0o644))
+
+	cfg, report, err := CheckDeveloperEnvironment(configDir, "alice", "gpu", globalCfg)
+	require.NoError(t, err)
+	assert.Empty(t, report.Errors())
+	assert.Equal(t, "ml-gpu", cfg.Profile)
+	assert.Equal(t, 2, cfg.Resources.CPU, "the developer's profile is replaced, not stacked")
+	assert.Equal(t, 1, cfg.Resources.GPU)
+}
+
+func TestCheckBaseConfig_Profiles(t *testing.T) {
+	cfg := NewBaseConfigWithDefaults()
+	cfg.Profiles = map[string]ProfileConfig{
+		"bad": {Resources: ResourceConfig{CPU: "lots"}},
+	}
+
+	report := CheckBaseConfig(&cfg)
+	require.Len(t, report.Errors(), 1)
+	assert.Equal(t, "profiles.resources.cpu:k8s_cpu", report.Errors()[0].Rule)
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Severity classifies a validation issue. Errors block generation while
+// warnings are reported but do not fail loading.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// ValidationIssue is a single finding produced while checking a config.
+type ValidationIssue struct {
+	Severity Severity `json:"severity"`
+	Rule     string   `json:"rule,omitempty"` // "<field>:<rule>" ID, usable in validation.disabledRules / warnRules
+	Message  string   `json:"message"`
+}
+
+// ValidationReport collects every issue found while checking a config,
+// rather than stopping at the first failure.
+type ValidationReport struct {
+	Issues []ValidationIssue
+
+	settings ValidationConfig
+}
+
+// newValidationReport creates an empty report that honors the rule toggles
+// and severity overrides in settings.
+func newValidationReport(settings ValidationConfig) *ValidationReport {
+	return &ValidationReport{settings: settings}
+}
+
+// add records an issue unless its rule is disabled. Rules listed in
+// validation.warnRules are downgraded from errors to warnings.
+func (r *ValidationReport) add(severity Severity, rule, message string) {
+	if rule != "" && r.settings.IsDisabled(rule) {
+		return
+	}
+	if severity == SeverityError && rule != "" && r.settings.isWarnOnly(rule) {
+		severity = SeverityWarning
+	}
+	r.Issues = append(r.Issues, ValidationIssue{Severity: severity, Rule: rule, Message: message})
+}
+
+// addError records err as an error issue; nil errors are ignored.
+func (r *ValidationReport) addError(rule string, err error) {
+	if err != nil {
+		r.add(SeverityError, rule, err.Error())
+	}
+}
+
+// addWarning records a warning issue.
+func (r *ValidationReport) addWarning(rule, message string) {
+	r.add(SeverityWarning, rule, message)
+}
+
+// addLoadWarnings records the fixes applied while reading a config file.
+func (r *ValidationReport) addLoadWarnings(warnings []ValidationIssue) {
+	for _, warning := range warnings {
+		r.addWarning(warning.Rule, warning.Message)
+	}
+}
+
+// addFieldErrors converts go-playground/validator failures into issues,
+// deriving each rule ID from the field's YAML path and the failing tag.
+func (r *ValidationReport) addFieldErrors(err error, root reflect.Type) {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		r.addError("", err)
+		return
+	}
+	for _, fieldError := range validationErrors {
+		field := yamlPathForNamespace(root, fieldError.StructNamespace())
+		r.add(SeverityError, ruleID(field, fieldError.Tag()), formatFieldError(fieldError))
+	}
+}
+
+// Errors returns the issues with error severity.
+func (r *ValidationReport) Errors() []ValidationIssue {
+	return r.bySeverity(SeverityError)
+}
+
+// Warnings returns the issues with warning severity.
+func (r *ValidationReport) Warnings() []ValidationIssue {
+	return r.bySeverity(SeverityWarning)
+}
+
+// HasErrors reports whether any error-severity issue was recorded.
+func (r *ValidationReport) HasErrors() bool {
+	return len(r.Errors()) > 0
+}
+
+// Err returns the error-severity issues as a single error, or nil when the
+// config is valid. Warnings never contribute to the returned error.
+func (r *ValidationReport) Err() error {
+	issues := r.Errors()
+	if len(issues) == 0 {
+		return nil
+	}
+	errorMessages := make([]string, len(issues))
+	for i, issue := range issues {
+		errorMessages[i] = issue.Message
+	}
+	return fmt.Errorf("configuration validation failed:\n  - %s",
+		strings.Join(errorMessages, "\n  - "))
+}
+
+func (r *ValidationReport) bySeverity(severity Severity) []ValidationIssue {
+	var out []ValidationIssue
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDevEnvConfig_Severities(t *testing.T) {
+	t.Run("missing git identity is a warning, not an error", func(t *testing.T) {
+		cfg := &DevEnvConfig{
+			Name: "alice",
+			BaseConfig: BaseConfig{
+				SSHPublicKey: "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host",
+			},
+		}
+
+		report := CheckDevEnvConfig(cfg)
+		require.NoError(t, report.Err())
+		assert.False(t, report.HasErrors())
+
+		warnings := report.Warnings()
+		require.Len(t, warnings, 2)
+		assert.Equal(t, "git.email:recommended", warnings[0].Rule)
+		assert.Equal(t, "git.name:recommended", warnings[1].Rule)
+		assert.Equal(t, SeverityWarning, warnings[0].Severity)
+	})
+
+	t.Run("all errors are collected", func(t *testing.T) {
+		cfg := &DevEnvConfig{
+			BaseConfig: BaseConfig{
+				UID:           10,
+				PythonBinPath: "venv/bin",
+			},
+		}
+
+		report := CheckDevEnvConfig(cfg)
+		rules := make([]string, 0, len(report.Errors()))
+		for _, issue := range report.Errors() {
+			rules = append(rules, issue.Rule)
+		}
+		assert.Contains(t, rules, "name:required")
+		assert.Contains(t, rules, "uid:min")
+		assert.Contains(t, rules, "pythonBinPath:absolute")
+		assert.Contains(t, rules, "sshPublicKey:required")
+
+		err := report.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "'Name' is required")
+		assert.Contains(t, err.Error(), "absolute path")
+	})
+
+	t.Run("warnRules downgrade errors", func(t *testing.T) {
+		cfg := &DevEnvConfig{
+			Name: "legacy",
+			Git:  GitConfig{Name: "Legacy User", Email: "legacy@example.com"},
+			BaseConfig: BaseConfig{
+				UID:          500,
+				SSHPublicKey: "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host",
+				Validation:   ValidationConfig{WarnRules: []string{"uid:min"}},
+			},
+		}
+
+		report := CheckDevEnvConfig(cfg)
+		require.NoError(t, report.Err())
+		require.Len(t, report.Warnings(), 1)
+		assert.Equal(t, "uid:min", report.Warnings()[0].Rule)
+		assert.Contains(t, report.Warnings()[0].Message, "UID")
+	})
+
+	t.Run("disabled warnings are dropped", func(t *testing.T) {
+		cfg := &DevEnvConfig{
+			Name: "alice",
+			BaseConfig: BaseConfig{
+				SSHPublicKey: "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host",
+				Validation: ValidationConfig{
+					DisabledRules: []string{"git.email:recommended", "git.name:recommended"},
+				},
+			},
+		}
+
+		assert.Empty(t, CheckDevEnvConfig(cfg).Issues)
+	})
+}
+
+func TestLoadDeveloperConfig_RecordsWarnings(t *testing.T) {
+	cfg, err := LoadDeveloperConfig("testdata", "minimal_user")
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Warnings, 2)
+	for _, warning := range cfg.Warnings {
+		assert.Equal(t, SeverityWarning, warning.Severity)
+	}
+}
+
+func TestCheckDeveloperConfig_ReturnsReportInsteadOfFailing(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte("hostName: dev.example.com\n"), 0o644))
+
+	developerDir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(developerDir, 0o755))
+	userConfigYAML := `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+httpPort: 8080
+uid: 500
+`
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, DeveloperConfigFile), []byte(userConfigYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+
+	cfg, report, err := CheckDeveloperConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "dev.example.com", cfg.HostName, "global hostName satisfies the httpPort rule")
+
+	require.Len(t, report.Errors(), 1)
+	assert.Equal(t, "uid:min", report.Errors()[0].Rule)
+	assert.Len(t, report.Warnings(), 2)
+
+	// Parse failures are returned as errors, not report issues
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, DeveloperConfigFile), []byte("name: [alice\n"), 0o644))
+	_, _, err = CheckDeveloperConfig(tempDir, "alice", globalCfg)
+	require.Error(t, err)
+}
@@ -100,13 +100,32 @@ func cpuTextToMillicores(s string) (int64, error) {
 // getCanonicalCPU parses ResourceConfig.CPU on demand and returns millicores.
 // This is the single entry-point your higher-level code should call.
 func (r *ResourceConfig) getCanonicalCPU() (int64, error) {
-	text, err := normalizeToCPUText(r.CPU)
+	return canonicalCPU(r.CPU)
+}
+
+// getCanonicalCPU parses ResourceLimits.CPU on demand and returns millicores.
+func (l *ResourceLimits) getCanonicalCPU() (int64, error) {
+	return canonicalCPU(l.CPU)
+}
+
+// canonicalCPU runs a raw CPU value through the normalization pipeline.
+func canonicalCPU(raw any) (int64, error) {
+	text, err := normalizeToCPUText(raw)
 	if err != nil {
 		return 0, err
 	}
 	return cpuTextToMillicores(text)
 }
 
+// formatMillicores renders millicores as a Kubernetes CPU quantity
+// ("2500m"), or "0" for non-positive values.
+func formatMillicores(millicores int64) string {
+	if millicores <= 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%dm", millicores)
+}
+
 // ============================================================================
 // --- memory normalization pipeline ------------------------------------------
 // ============================================================================
@@ -238,13 +257,35 @@ func memoryTextToMi(s string) (int64, error) {
 
 // getCanonicalMemory parses ResourceConfig.Memory on demand and returns MiB.
 func (r *ResourceConfig) getCanonicalMemory() (int64, error) {
-	text, err := normalizeToMemoryText(r.Memory)
+	return canonicalMemory(r.Memory)
+}
+
+// getCanonicalMemory parses ResourceLimits.Memory on demand and returns MiB.
+func (l *ResourceLimits) getCanonicalMemory() (int64, error) {
+	return canonicalMemory(l.Memory)
+}
+
+// canonicalMemory runs a raw memory value through the normalization pipeline.
+func canonicalMemory(raw any) (int64, error) {
+	text, err := normalizeToMemoryText(raw)
 	if err != nil {
 		return 0, err
 	}
 	return memoryTextToMi(text)
 }
 
+// formatMebibytes renders MiB as a Kubernetes memory quantity, choosing "Gi"
+// for exact Gi multiples and "Mi" otherwise; non-positive values yield "".
+func formatMebibytes(mebibytes int64) string {
+	if mebibytes <= 0 {
+		return ""
+	}
+	if mebibytes%1024 == 0 {
+		return fmt.Sprintf("%dGi", mebibytes/1024)
+	}
+	return fmt.Sprintf("%dMi", mebibytes)
+}
+
 // ---------------- helpers ----------------
 // bytesToMi converts a size in decimal bytes to mebibytes (MiB) and rounds to
 // the nearest int64 MiB. It rejects negative, NaN, or infinite inputs and
@@ -0,0 +1,270 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Rule IDs for semantic checks that are implemented in code rather than
+// through validator tags. They can be disabled like any tag-based rule.
+const (
+	rulePythonBinPathAbsolute    = "pythonBinPath:absolute"
+	ruleSSHKeysFormat            = "sshPublicKey:format"
+	ruleSSHKeysRequired          = "sshPublicKey:required"
+	ruleCPUQuantity              = "resources.cpu:quantity"
+	ruleMemoryQuantity           = "resources.memory:quantity"
+	ruleGPUNonNegative           = "resources.gpu:nonnegative"
+	ruleCPULimitQuantity         = "resources.limits.cpu:quantity"
+	ruleMemoryLimitQuantity      = "resources.limits.memory:quantity"
+	ruleCPULimitBelowRequest     = "resources.limits.cpu:gte_request"
+	ruleMemLimitBelowRequest     = "resources.limits.memory:gte_request"
+	ruleGitEmailRecommended      = "git.email:recommended"
+	ruleGitNameRecommended       = "git.name:recommended"
+	ruleGPUImage                 = "image:gpu_capable"
+	ruleAuthURLRequired          = "authURL:required_with_auth"
+	ruleAuthSignInRequired       = "authSignIn:required_with_auth"
+	ruleHostNameRequired         = "hostName:required_with_http"
+	ruleRefreshSchedule          = "refresh.schedule:required_with_enabled"
+	ruleNameDNSLabel             = "name:dns_label"
+	ruleNameReserved             = "name:reserved"
+	ruleNameReservedPrefix       = "name:reserved_prefix"
+	ruleNameTruncated            = "name:truncated"
+	ruleAnnotationManaged        = "annotations.ingress:managed"
+	ruleIngressExtraHost         = "ingress.extraHosts:dns_name"
+	ruleNodeSelectorKey          = "nodeSelector:key_format"
+	ruleNodeSelectorValue        = "nodeSelector:value_format"
+	ruleTolerationFormat         = "tolerations:format"
+	ruleAffinityKind             = "affinity:kind"
+	ruleAffinityTargetNodes      = "affinity:target_nodes_conflict"
+	ruleArchNodeSelector         = "arch:node_selector_conflict"
+	ruleImageVariantArch         = "imageVariants:arch"
+	rulePreemptibleHomeVolume    = "preemptible:home_volume"
+	ruleGPUTypeAllowed           = "resources.gpuType:allowed"
+	ruleMIGProfileAllowed        = "resources.migProfile:allowed"
+	ruleGPUTypesFormat           = "gpuTypes:format"
+	ruleProbeHandler             = "probes:handler"
+	ruleProbeSuccessThreshold    = "probes:success_threshold"
+	ruleProbeLivenessStartup     = "probes.liveness:without_startup"
+	ruleLifecycleCommand         = "lifecycle:command"
+	ruleObservabilityInterval    = "observability.interval:duration"
+	ruleObservabilityLabels      = "observability.labels:format"
+	ruleObservabilityMetricsPort = "observability.metricsPort:conflict"
+	ruleLoggingSinkHost          = "logging.sink.host:required"
+	ruleLoggingLabels            = "logging.labels:format"
+	ruleLoggingPaths             = "logging.paths:format"
+	ruleExpiresPast              = "expires:past"
+	ruleExpiresSoon              = "expires:soon"
+	ruleEnvNameFormat            = "env:name_format"
+	ruleEnvNameReserved          = "env:reserved"
+	ruleEnvironmentName          = "name:environment_unchanged"
+	ruleEnvironmentSSHPort       = "sshPort:environment_unique"
+	ruleEnvironmentVolumeClaims  = "resources.storageAccessMode:environment_shared"
+	ruleEnvironmentInstanceName  = "name:environment_collision"
+	ruleProfileUnknown           = "profile:unknown"
+	ruleNameHiddenRunes          = "name:hidden_unicode"
+	ruleImageHiddenRunes         = "image:hidden_unicode"
+	ruleHostNameHiddenRunes      = "hostName:hidden_unicode"
+	ruleNamespaceHiddenRunes     = "namespace:hidden_unicode"
+	ruleSSHKeysHiddenRunes       = "sshPublicKey:hidden_unicode"
+	ruleFileBOM                  = "file:bom"
+	ruleFileLineEndings          = "file:line_endings"
+)
+
+// ruleID builds the "<field>:<rule>" identifier used by DisabledRules.
+func ruleID(field, rule string) string {
+	return field + ":" + rule
+}
+
+// IsDisabled reports whether the rule with the given ID has been switched off.
+// Matching is case-insensitive so "UID:min" and "uid:min" are equivalent.
+func (v ValidationConfig) IsDisabled(id string) bool {
+	for _, disabled := range v.DisabledRules {
+		if strings.EqualFold(strings.TrimSpace(disabled), id) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWarnOnly reports whether failures of the rule should be reported as
+// warnings instead of errors.
+func (v ValidationConfig) isWarnOnly(id string) bool {
+	for _, rule := range v.WarnRules {
+		if strings.EqualFold(strings.TrimSpace(rule), id) {
+			return true
+		}
+	}
+	return false
+}
+
+// addCustomRuleIssues evaluates every custom regex rule against cfg and
+// records failures in the report. Broken rules (invalid pattern or unknown
+// field) are reported as errors so misconfiguration is never silent.
+func (r *ValidationReport) addCustomRuleIssues(cfg any) {
+	for _, rule := range r.settings.CustomRules {
+		id := ruleID("customRules", rule.Name)
+
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			r.addError(id, fmt.Errorf("custom validation rule %q has invalid pattern %q: %w", rule.Name, rule.Pattern, err))
+			continue
+		}
+
+		values, err := lookupYAMLField(reflect.ValueOf(cfg), strings.Split(rule.Field, "."))
+		if err != nil {
+			r.addError(id, fmt.Errorf("custom validation rule %q: %w", rule.Name, err))
+			continue
+		}
+
+		for _, value := range values {
+			if value == "" || re.MatchString(value) {
+				continue
+			}
+			message := fmt.Sprintf("'%s' does not satisfy custom rule '%s', got '%s'", rule.Field, rule.Name, value)
+			if rule.Message != "" {
+				message += ": " + rule.Message
+			}
+			r.add(SeverityError, id, message)
+		}
+	}
+}
+
+// yamlPathForNamespace converts a validator struct namespace such as
+// "DevEnvConfig.BaseConfig.GitRepos[0].URL" into the YAML path users write
+// in their config files ("gitRepos.url"). Inline (embedded) structs do not
+// contribute a path segment. Unknown segments fall back to the Go name.
+func yamlPathForNamespace(root reflect.Type, namespace string) string {
+	segments := strings.Split(namespace, ".")
+	if len(segments) > 0 {
+		segments = segments[1:] // drop the root type name
+	}
+
+	t := root
+	var path []string
+	for _, segment := range segments {
+		if i := strings.IndexByte(segment, '['); i >= 0 {
+			segment = segment[:i]
+		}
+		for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Map) {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			path = append(path, segment)
+			t = nil
+			continue
+		}
+		field, ok := t.FieldByName(segment)
+		if !ok {
+			path = append(path, segment)
+			t = nil
+			continue
+		}
+		name, inline := yamlFieldName(field)
+		if !inline {
+			path = append(path, name)
+		}
+		t = field.Type
+	}
+	return strings.Join(path, ".")
+}
+
+// yamlFieldName returns the YAML key for a struct field and whether the
+// field is inlined into its parent.
+func yamlFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("yaml")
+	name, opts, _ := strings.Cut(tag, ",")
+	if strings.Contains(opts, "inline") {
+		return "", true
+	}
+	if name == "" {
+		return strings.ToLower(field.Name), false
+	}
+	return name, false
+}
+
+// lookupYAMLField resolves a YAML path against v and returns the string
+// values found there. Slices of structs are traversed element-wise, and
+// string lists or flexible string-or-list fields yield every element.
+func lookupYAMLField(v reflect.Value, path []string) ([]string, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	if len(path) == 0 {
+		return stringValues(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		field, ok := findYAMLField(v, path[0])
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q", path[0])
+		}
+		return lookupYAMLField(field, path[1:])
+
+	case reflect.Slice:
+		var out []string
+		for i := 0; i < v.Len(); i++ {
+			values, err := lookupYAMLField(v.Index(i), path)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, values...)
+		}
+		return out, nil
+
+	default:
+		return nil, fmt.Errorf("field %q cannot be traversed", path[0])
+	}
+}
+
+// findYAMLField finds the struct field whose YAML key is name, descending
+// into inline structs.
+func findYAMLField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key, inline := yamlFieldName(field)
+		if inline {
+			if found, ok := findYAMLField(v.Field(i), name); ok {
+				return found, true
+			}
+			continue
+		}
+		if key == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// stringValues flattens a leaf value into strings. Non-string leaves
+// produce an error so misconfigured rules are reported rather than ignored.
+func stringValues(v reflect.Value) ([]string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return []string{v.String()}, nil
+	case reflect.Slice:
+		var out []string
+		for i := 0; i < v.Len(); i++ {
+			values, err := lookupYAMLField(v.Index(i), nil)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, values...)
+		}
+		return out, nil
+	case reflect.Invalid:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("custom rules only apply to string fields, got %s", v.Kind())
+	}
+}
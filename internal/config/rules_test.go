@@ -0,0 +1,142 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYAMLPathForNamespace(t *testing.T) {
+	root := reflect.TypeOf(DevEnvConfig{})
+	cases := map[string]string{
+		"DevEnvConfig.Name":                         "name",
+		"DevEnvConfig.BaseConfig.UID":               "uid",
+		"DevEnvConfig.BaseConfig.Resources.CPU":     "resources.cpu",
+		"DevEnvConfig.BaseConfig.GitRepos[0].URL":   "gitRepos.url",
+		"DevEnvConfig.Git.Email":                    "git.email",
+		"DevEnvConfig.BaseConfig.Volumes[2].Name":   "volumes.name",
+		"DevEnvConfig.BaseConfig.Packages.APT[1]":   "packages.apt",
+		"DevEnvConfig.BaseConfig.DoesNotExist.Leaf": "DoesNotExist.Leaf",
+	}
+	for namespace, want := range cases {
+		assert.Equal(t, want, yamlPathForNamespace(root, namespace), namespace)
+	}
+}
+
+func TestValidateDevEnvConfig_DisabledRules(t *testing.T) {
+	newCfg := func(disabled ...string) *DevEnvConfig {
+		return &DevEnvConfig{
+			Name: "legacy",
+			BaseConfig: BaseConfig{
+				UID:          500, // below the min=1000 rule
+				SSHPublicKey: "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host",
+				Validation:   ValidationConfig{DisabledRules: disabled},
+			},
+		}
+	}
+
+	err := ValidateDevEnvConfig(newCfg())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "UID")
+
+	require.NoError(t, ValidateDevEnvConfig(newCfg("uid:min")))
+	require.NoError(t, ValidateDevEnvConfig(newCfg("UID:MIN")), "rule IDs are case-insensitive")
+
+	// Disabling an unrelated rule keeps the failure
+	require.Error(t, ValidateDevEnvConfig(newCfg("uid:max")))
+}
+
+func TestValidateDevEnvConfig_DisabledSemanticRule(t *testing.T) {
+	cfg := &DevEnvConfig{
+		Name: "alice",
+		BaseConfig: BaseConfig{
+			PythonBinPath: "venv/bin",
+			SSHPublicKey:  "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host",
+		},
+	}
+	require.Error(t, ValidateDevEnvConfig(cfg))
+
+	cfg.Validation.DisabledRules = []string{"pythonBinPath:absolute"}
+	require.NoError(t, ValidateDevEnvConfig(cfg))
+}
+
+func TestValidateDevEnvConfig_CustomRules(t *testing.T) {
+	newCfg := func(image string, apt ...string) *DevEnvConfig {
+		return &DevEnvConfig{
+			Name: "alice",
+			BaseConfig: BaseConfig{
+				Image:        image,
+				Packages:     PackageConfig{APT: apt},
+				SSHPublicKey: "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host",
+				Validation: ValidationConfig{
+					CustomRules: []CustomRule{
+						{Name: "internal-registry", Field: "image", Pattern: `^registry\.example\.com/`, Message: "images must come from the internal registry"},
+						{Name: "no-telnet", Field: "packages.apt", Pattern: `^(?:[^t]|t[^e]).*$`},
+					},
+				},
+			},
+		}
+	}
+
+	require.NoError(t, ValidateDevEnvConfig(newCfg("registry.example.com/ubuntu:22.04", "vim")))
+
+	err := ValidateDevEnvConfig(newCfg("docker.io/ubuntu:22.04", "vim"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "internal-registry")
+	assert.Contains(t, err.Error(), "internal registry")
+
+	err = ValidateDevEnvConfig(newCfg("registry.example.com/ubuntu:22.04", "vim", "telnet"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no-telnet")
+	assert.Contains(t, err.Error(), "telnet")
+}
+
+func TestValidateDevEnvConfig_CustomRuleErrors(t *testing.T) {
+	cfg := &DevEnvConfig{
+		Name: "alice",
+		BaseConfig: BaseConfig{
+			SSHPublicKey: "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host",
+		},
+	}
+
+	cfg.Validation.CustomRules = []CustomRule{{Name: "bad-regex", Field: "image", Pattern: "("}}
+	err := ValidateDevEnvConfig(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid pattern")
+
+	cfg.Validation.CustomRules = []CustomRule{{Name: "typo", Field: "imagee", Pattern: ".*"}}
+	err = ValidateDevEnvConfig(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown field")
+}
+
+func TestLoadDeveloperConfigWithBaseConfig_ValidationIsGlobalOnly(t *testing.T) {
+	tempDir := t.TempDir()
+
+	developerDir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(developerDir, 0o755))
+	// The developer tries to relax the UID rule for themselves
+	userConfigYAML := `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+uid: 500
+validation:
+  disabledRules: ["uid:min"]
+`
+	require.NoError(t, os.WriteFile(filepath.Join(developerDir, "devenv-config.yaml"), []byte(userConfigYAML), 0o644))
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+
+	_, err = LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.Error(t, err)
+
+	// The same toggle from the global config is honored
+	globalCfg.Validation.DisabledRules = []string{"uid:min"}
+	cfg, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, 500, cfg.UID)
+}
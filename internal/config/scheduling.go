@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// affinityKinds are the keys allowed at the top level of 'affinity', as in
+// a Kubernetes PodSpec.
+var affinityKinds = []string{"nodeAffinity", "podAffinity", "podAntiAffinity"}
+
+// Toleration lets the environment's pod schedule onto nodes with a matching
+// taint, such as dedicated GPU nodes.
+type Toleration struct {
+	Key               string `yaml:"key,omitempty"`
+	Operator          string `yaml:"operator,omitempty" validate:"omitempty,oneof=Exists Equal"`
+	Value             string `yaml:"value,omitempty"`
+	Effect            string `yaml:"effect,omitempty" validate:"omitempty,oneof=NoSchedule PreferNoSchedule NoExecute"`
+	TolerationSeconds *int64 `yaml:"tolerationSeconds,omitempty"`
+}
+
+// equal reports whether two tolerations are identical.
+func (t Toleration) equal(other Toleration) bool {
+	if (t.TolerationSeconds == nil) != (other.TolerationSeconds == nil) {
+		return false
+	}
+	if t.TolerationSeconds != nil && *t.TolerationSeconds != *other.TolerationSeconds {
+		return false
+	}
+	return t.Key == other.Key && t.Operator == other.Operator && t.Value == other.Value && t.Effect == other.Effect
+}
+
+// mergeTolerations combines global and user tolerations, global first,
+// dropping exact duplicates.
+func mergeTolerations(global, user []Toleration) []Toleration {
+	if len(global) == 0 {
+		return user
+	}
+	if len(user) == 0 {
+		return global
+	}
+
+	result := append([]Toleration{}, global...)
+	for _, toleration := range user {
+		duplicate := false
+		for _, existing := range result {
+			if existing.equal(toleration) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			result = append(result, toleration)
+		}
+	}
+	return result
+}
+
+// addSchedulingIssues checks the node selector, tolerations and affinity
+// against the Kubernetes rules the validator tags cannot express.
+func addSchedulingIssues(report *ValidationReport, base *BaseConfig) {
+	keys := make([]string, 0, len(base.NodeSelector))
+	for key := range base.NodeSelector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := validateAnnotationKey(key); err != nil {
+			report.addError(ruleNodeSelectorKey, fmt.Errorf("'nodeSelector' key %q is invalid: %w", key, err))
+		}
+		if value := base.NodeSelector[key]; len(value) > maxDNSLabelLength || (value != "" && !annotationNameRe.MatchString(value)) {
+			report.addError(ruleNodeSelectorValue, fmt.Errorf(
+				"'nodeSelector' value %q for %q must be at most 63 alphanumerics, '-', '_' or '.', starting and ending with an alphanumeric", value, key))
+		}
+	}
+
+	for i, toleration := range base.Tolerations {
+		field := fmt.Sprintf("'tolerations[%d]'", i)
+		if toleration.Key != "" {
+			if err := validateAnnotationKey(toleration.Key); err != nil {
+				report.addError(ruleTolerationFormat, fmt.Errorf("%s key %q is invalid: %w", field, toleration.Key, err))
+			}
+		}
+		if toleration.Operator == "Exists" && toleration.Value != "" {
+			report.addError(ruleTolerationFormat, fmt.Errorf("%s must not set 'value' with operator Exists", field))
+		}
+		if toleration.Key == "" && toleration.Operator != "Exists" {
+			report.addError(ruleTolerationFormat, fmt.Errorf("%s without 'key' must use operator Exists", field))
+		}
+		if toleration.TolerationSeconds != nil && toleration.Effect != "NoExecute" {
+			report.addError(ruleTolerationFormat, fmt.Errorf("%s may only set 'tolerationSeconds' with effect NoExecute", field))
+		}
+	}
+
+	kinds := make([]string, 0, len(base.Affinity))
+	for kind := range base.Affinity {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		if !slices.Contains(affinityKinds, kind) {
+			report.addError(ruleAffinityKind, fmt.Errorf("'affinity' key %q is invalid; use %v", kind, affinityKinds))
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDevEnvConfig_Scheduling(t *testing.T) {
+	newCfg := func(base BaseConfig) *DevEnvConfig {
+		base.SSHPublicKey = "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host"
+		return &DevEnvConfig{
+			Name:       "alice",
+			Git:        GitConfig{Name: "Alice", Email: "alice@example.com"},
+			BaseConfig: base,
+		}
+	}
+	rules := func(report *ValidationReport) []string {
+		var ids []string
+		for _, issue := range report.Errors() {
+			ids = append(ids, issue.Rule)
+		}
+		return ids
+	}
+
+	seconds := int64(300)
+	assert.Empty(t, CheckDevEnvConfig(newCfg(BaseConfig{
+		NodeSelector: map[string]string{"nvidia.com/gpu.present": "true"},
+		Tolerations: []Toleration{
+			{Key: "nvidia.com/gpu", Operator: "Exists", Effect: "NoSchedule"},
+			{Key: "node.kubernetes.io/unreachable", Operator: "Exists", Effect: "NoExecute", TolerationSeconds: &seconds},
+		},
+		Affinity: map[string]any{"podAntiAffinity": map[string]any{}},
+	})).Issues)
+
+	tests := map[string]struct {
+		base BaseConfig
+		rule string
+	}{
+		"selector key":       {BaseConfig{NodeSelector: map[string]string{"bad key": "x"}}, "nodeSelector:key_format"},
+		"selector value":     {BaseConfig{NodeSelector: map[string]string{"gpu": "a b"}}, "nodeSelector:value_format"},
+		"exists with value":  {BaseConfig{Tolerations: []Toleration{{Key: "gpu", Operator: "Exists", Value: "x"}}}, "tolerations:format"},
+		"empty key equal":    {BaseConfig{Tolerations: []Toleration{{Value: "x"}}}, "tolerations:format"},
+		"seconds no execute": {BaseConfig{Tolerations: []Toleration{{Key: "gpu", Effect: "NoSchedule", TolerationSeconds: &seconds}}}, "tolerations:format"},
+		"bad operator":       {BaseConfig{Tolerations: []Toleration{{Key: "gpu", Operator: "In"}}}, "tolerations.operator:oneof"},
+		"unknown affinity":   {BaseConfig{Affinity: map[string]any{"nodeSelector": map[string]any{}}}, "affinity:kind"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Contains(t, rules(CheckDevEnvConfig(newCfg(tt.base))), tt.rule)
+		})
+	}
+
+	cfg := newCfg(BaseConfig{Affinity: map[string]any{"nodeAffinity": map[string]any{}}})
+	cfg.TargetNodes = []string{"node1"}
+	assert.Equal(t, []string{"affinity:target_nodes_conflict"}, rules(CheckDevEnvConfig(cfg)))
+}
+
+func TestLoadDeveloperConfigWithBaseConfig_MergesScheduling(t *testing.T) {
+	tempDir := t.TempDir()
+
+	globalConfigYAML := `nodeSelector:
+  pool: general
+tolerations:
+  - key: dedicated
+    operator: Equal
+    value: devenv
+    effect: NoSchedule
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "devenv.yaml"), []byte(globalConfigYAML), 0o644))
+
+	writeDeveloper := func(name, extra string) {
+		dir := filepath.Join(tempDir, name)
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		content := "name: " + name + "\nsshPublicKey: \"ssh-rsa AAAAB3NzaC1yc2E " + name + "@example.com\"\n" + extra
+		require.NoError(t, os.WriteFile(filepath.Join(dir, DeveloperConfigFile), []byte(content), 0o644))
+	}
+	writeDeveloper("alice", `nodeSelector:
+  pool: gpu
+tolerations:
+  - key: nvidia.com/gpu
+    operator: Exists
+    effect: NoSchedule
+affinity:
+  podAntiAffinity:
+    preferredDuringSchedulingIgnoredDuringExecution: []
+`)
+	writeDeveloper("bob", "")
+
+	globalCfg, err := LoadGlobalConfig(tempDir)
+	require.NoError(t, err)
+
+	alice, err := LoadDeveloperConfigWithBaseConfig(tempDir, "alice", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"pool": "gpu"}, alice.NodeSelector)
+	require.Len(t, alice.Tolerations, 2)
+	assert.Equal(t, "dedicated", alice.Tolerations[0].Key)
+	assert.Equal(t, "nvidia.com/gpu", alice.Tolerations[1].Key)
+	assert.Contains(t, alice.Affinity, "podAntiAffinity")
+
+	// Alice's values must not leak into the shared global config or bob
+	bob, err := LoadDeveloperConfigWithBaseConfig(tempDir, "bob", globalCfg)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"pool": "general"}, bob.NodeSelector)
+	assert.Len(t, bob.Tolerations, 1)
+	assert.Empty(t, bob.Affinity)
+	assert.Equal(t, map[string]string{"pool": "general"}, globalCfg.NodeSelector)
+}
+
+func TestMergeTolerations(t *testing.T) {
+	gpu := Toleration{Key: "nvidia.com/gpu", Operator: "Exists", Effect: "NoSchedule"}
+	dedicated := Toleration{Key: "dedicated", Value: "devenv", Effect: "NoSchedule"}
+
+	assert.Equal(t, []Toleration{gpu, dedicated}, mergeTolerations([]Toleration{gpu}, []Toleration{gpu, dedicated}))
+	assert.Equal(t, []Toleration{gpu}, mergeTolerations(nil, []Toleration{gpu}))
+	assert.Equal(t, []Toleration{gpu}, mergeTolerations([]Toleration{gpu}, nil))
+}
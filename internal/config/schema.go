@@ -0,0 +1,237 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Schema kinds accepted by JSONSchema.
+const (
+	SchemaGlobal      = "global"      // devenv.yaml
+	SchemaDeveloper   = "developer"   // <developer>/devenv-config.yaml
+	SchemaEnvironment = "environment" // <developer>/environments/<name>.yaml
+)
+
+// SchemaKinds lists the supported schema kinds.
+var SchemaKinds = []string{SchemaGlobal, SchemaDeveloper, SchemaEnvironment}
+
+// globalOnlyFields are BaseConfig fields that are only honored in
+// devenv.yaml; developer and environment schemas leave them out.
+var globalOnlyFields = []string{"profiles", "validation", "hooks", "sshPortRange", "dataSources", "imageVariants", "preemptibleNodes", "gpuTypes", "namespaceStrategy"}
+
+// JSONSchemaDraft is the JSON Schema dialect of generated schemas.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema. Only the keywords needed
+// to describe config files are supported.
+type Schema struct {
+	Draft                string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"` // false or *Schema
+	Items                *Schema            `json:"items,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+// flexibleSchemas describes fields of type any by their validator tag.
+var flexibleSchemas = map[string]func() *Schema{
+	"ssh_keys": func() *Schema {
+		return &Schema{
+			Description: "One OpenSSH public key or a list of keys",
+			OneOf: []*Schema{
+				{Type: "string", MinLength: intPtr(1)},
+				{Type: "array", Items: &Schema{Type: "string", MinLength: intPtr(1)}, MinItems: intPtr(1)},
+			},
+		}
+	},
+	"k8s_cpu": func() *Schema {
+		return &Schema{
+			Description: "Cores as a number (e.g., 2, 1.5) or a Kubernetes quantity (e.g., \"500m\")",
+			OneOf: []*Schema{
+				{Type: "number", Minimum: floatPtr(0)},
+				{Type: "string", Pattern: `^[0-9]+(\.[0-9]+)?m?$`},
+			},
+		}
+	},
+	"k8s_memory": func() *Schema {
+		return &Schema{
+			Description: "Gi as a number (e.g., 16) or a Kubernetes quantity (e.g., \"512Mi\")",
+			OneOf: []*Schema{
+				{Type: "number", Minimum: floatPtr(0)},
+				{Type: "string", Pattern: `^[0-9]+(\.[0-9]+)?([KMGTPE]i?)?$`},
+			},
+		}
+	},
+}
+
+// JSONSchema returns the JSON Schema of a config file kind (see
+// SchemaKinds), generated from the config structs and their validator tags
+// so it cannot drift from the Go types. Editors can use it for completion
+// and CI can validate files without the devenv binary. Cross-field rules,
+// validation toggles and custom rules are only enforced by devenv itself.
+func JSONSchema(kind string) (*Schema, error) {
+	var schema *Schema
+	switch kind {
+	case SchemaGlobal:
+		schema = structSchema(reflect.TypeOf(BaseConfig{}))
+		schema.Title = "devenv global config (devenv.yaml)"
+	case SchemaDeveloper, SchemaEnvironment:
+		schema = structSchema(reflect.TypeOf(DevEnvConfig{}))
+		for _, field := range globalOnlyFields {
+			delete(schema.Properties, field)
+		}
+		schema.Title = "devenv developer config (" + DeveloperConfigFile + ")"
+		if kind == SchemaEnvironment {
+			// Environments inherit the developer's name and cannot change it
+			delete(schema.Properties, "name")
+			schema.Required = nil
+			schema.Title = "devenv environment config (" + EnvironmentsDir + "/<name>.yaml)"
+		}
+	default:
+		return nil, fmt.Errorf("unknown schema kind %q (supported: %s)", kind, strings.Join(SchemaKinds, ", "))
+	}
+	schema.Draft = JSONSchemaDraft
+	return schema, nil
+}
+
+// structSchema describes a struct by its YAML fields. Unknown keys are
+// rejected so typos surface in editors.
+func structSchema(t reflect.Type) *Schema {
+	schema := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+	addStructFields(schema, t)
+	return schema
+}
+
+// addStructFields adds the YAML fields of t, including inlined structs, to
+// schema.
+func addStructFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("yaml") == "-" {
+			continue
+		}
+		name, inline := yamlFieldName(field)
+		if inline {
+			addStructFields(schema, field.Type)
+			continue
+		}
+
+		tags := strings.Split(field.Tag.Get("validate"), ",")
+		schema.Properties[name] = fieldSchema(field.Type, tags)
+		for _, tag := range tags {
+			if tag == "required" {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+}
+
+// fieldSchema describes a value of type t validated by tags. Tags after
+// "dive" apply to the elements of a list or the values of a map.
+func fieldSchema(t reflect.Type, tags []string) *Schema {
+	var elemTags []string
+	for i, tag := range tags {
+		if tag == "dive" {
+			tags, elemTags = tags[:i], tags[i+1:]
+			break
+		}
+	}
+
+	var schema *Schema
+	switch t.Kind() {
+	case reflect.String:
+		schema = &Schema{Type: "string"}
+	case reflect.Bool:
+		schema = &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema = &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		schema = &Schema{Type: "number"}
+	case reflect.Slice:
+		schema = &Schema{Type: "array", Items: fieldSchema(t.Elem(), elemTags)}
+	case reflect.Map:
+		schema = &Schema{Type: "object", AdditionalProperties: fieldSchema(t.Elem(), elemTags)}
+	case reflect.Struct:
+		schema = structSchema(t)
+	case reflect.Pointer:
+		return fieldSchema(t.Elem(), tags)
+	default: // Flexible fields (any) are described by their validator tag
+		for _, tag := range tags {
+			if flexible, ok := flexibleSchemas[tag]; ok {
+				return flexible()
+			}
+		}
+		return &Schema{}
+	}
+
+	for _, tag := range tags {
+		applyTagConstraint(schema, tag)
+	}
+	return schema
+}
+
+// applyTagConstraint translates a validator tag into JSON Schema keywords.
+// Tags without a JSON Schema equivalent are left to devenv.
+func applyTagConstraint(schema *Schema, tag string) {
+	name, param, _ := strings.Cut(tag, "=")
+	switch name {
+	case "min", "max":
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return
+		}
+		switch {
+		case schema.Type == "string" && name == "min":
+			schema.MinLength = intPtr(n)
+		case schema.Type == "string":
+			schema.MaxLength = intPtr(n)
+		case schema.Type == "array" && name == "min":
+			schema.MinItems = intPtr(n)
+		case schema.Type == "array":
+			schema.MaxItems = intPtr(n)
+		case name == "min":
+			schema.Minimum = floatPtr(float64(n))
+		default:
+			schema.Maximum = floatPtr(float64(n))
+		}
+	case "oneof":
+		schema.Enum = strings.Fields(param)
+	case "email":
+		schema.Format = "email"
+	case "url":
+		schema.Format = "uri"
+	case "hostname", "fqdn":
+		schema.Format = "hostname"
+	case "alphanum":
+		schema.Pattern = "^[A-Za-z0-9]*$"
+	case "mount_path":
+		schema.Pattern = "^/"
+	case "datetime":
+		if param == "2006-01-02" {
+			schema.Format = "date"
+		}
+	}
+}
+
+func intPtr(n int) *int { return &n }
+
+func floatPtr(f float64) *float64 { return &f }
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestJSONSchema_Developer(t *testing.T) {
+	schema, err := JSONSchema(SchemaDeveloper)
+	require.NoError(t, err)
+
+	assert.Equal(t, JSONSchemaDraft, schema.Draft)
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, false, schema.AdditionalProperties)
+	assert.Equal(t, []string{"name"}, schema.Required)
+
+	// Inlined BaseConfig fields are flattened; global-only fields are not offered
+	assert.Contains(t, schema.Properties, "image")
+	assert.Contains(t, schema.Properties, "sshPort")
+	assert.NotContains(t, schema.Properties, "profiles")
+	assert.NotContains(t, schema.Properties, "validation")
+
+	name := schema.Properties["name"]
+	assert.Equal(t, "string", name.Type)
+	assert.Equal(t, "hostname", name.Format)
+	assert.Equal(t, 63, *name.MaxLength)
+
+	sshPort := schema.Properties["sshPort"]
+	assert.Equal(t, "integer", sshPort.Type)
+	assert.Equal(t, 30000.0, *sshPort.Minimum)
+	assert.Equal(t, 32767.0, *sshPort.Maximum)
+
+	// Flexible fields accept either of their YAML shapes
+	sshKeys := schema.Properties["sshPublicKey"]
+	require.Len(t, sshKeys.OneOf, 2)
+	assert.Equal(t, "string", sshKeys.OneOf[0].Type)
+	assert.Equal(t, "array", sshKeys.OneOf[1].Type)
+
+	cpu := schema.Properties["resources"].Properties["cpu"]
+	require.Len(t, cpu.OneOf, 2)
+	assert.Equal(t, "number", cpu.OneOf[0].Type)
+	assert.Equal(t, "string", cpu.OneOf[1].Type)
+
+	// Tags after dive apply to list items
+	volumes := schema.Properties["volumes"]
+	assert.Equal(t, "array", volumes.Type)
+	assert.ElementsMatch(t, []string{"name", "containerPath"}, volumes.Items.Required)
+	assert.Equal(t, "^/", volumes.Items.Properties["localPath"].Pattern)
+	assert.Equal(t, 1, *schema.Properties["packages"].Properties["apt"].Items.MinLength)
+}
+
+func TestJSONSchema_Kinds(t *testing.T) {
+	global, err := JSONSchema(SchemaGlobal)
+	require.NoError(t, err)
+	assert.Contains(t, global.Properties, "profiles")
+	assert.Contains(t, global.Properties, "validation")
+	assert.NotContains(t, global.Properties, "name")
+	assert.Empty(t, global.Required)
+
+	environment, err := JSONSchema(SchemaEnvironment)
+	require.NoError(t, err)
+	assert.NotContains(t, environment.Properties, "name")
+	assert.Contains(t, environment.Properties, "profile")
+	assert.Empty(t, environment.Required)
+
+	_, err = JSONSchema("cluster")
+	assert.ErrorContains(t, err, `unknown schema kind "cluster"`)
+}
+
+func TestJSONSchema_MarshalsAsJSON(t *testing.T) {
+	schema, err := JSONSchema(SchemaDeveloper)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, JSONSchemaDraft, doc["$schema"])
+	assert.Equal(t, false, doc["additionalProperties"])
+}
+
+// Every key used by the testdata configs must be described, so the schema
+// never rejects a config devenv accepts.
+func TestJSONSchema_CoversTestdata(t *testing.T) {
+	schema, err := JSONSchema(SchemaDeveloper)
+	require.NoError(t, err)
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "*", DeveloperConfigFile))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var node yaml.Node
+		require.NoError(t, yaml.Unmarshal(data, &node))
+		assertKeysInSchema(t, path, schema, node.Content[0])
+	}
+}
+
+func assertKeysInSchema(t *testing.T, path string, schema *Schema, node *yaml.Node) {
+	t.Helper()
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			if sub, ok := schema.AdditionalProperties.(*Schema); ok {
+				assertKeysInSchema(t, path, sub, value)
+				continue
+			}
+			sub, ok := schema.Properties[key]
+			if assert.True(t, ok, "%s: key %q is not in the schema", path, key) {
+				assertKeysInSchema(t, path, sub, value)
+			}
+		}
+	case yaml.SequenceNode:
+		if schema.Items != nil {
+			for _, item := range node.Content {
+				assertKeysInSchema(t, path, schema.Items, item)
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import "github.com/go-playground/validator/v10"
+
+// SecretRef mounts an existing Kubernetes Secret in the developer's
+// namespace into the environment: all of its keys as environment variables
+// (Env), as files under MountPath, or both. devenv never creates secrets.
+type SecretRef struct {
+	Name      string `yaml:"name" validate:"required,max=253,hostname"`
+	Env       bool   `yaml:"env,omitempty"`
+	MountPath string `yaml:"mountPath,omitempty" validate:"omitempty,mount_path"`
+	Optional  bool   `yaml:"optional,omitempty"` // Start the pod even if the secret does not exist
+}
+
+// validateSecretRef requires a secret to be used as env vars, files or both.
+func validateSecretRef(sl validator.StructLevel) {
+	secret := sl.Current().Interface().(SecretRef)
+	if !secret.Env && secret.MountPath == "" {
+		sl.ReportError(secret.MountPath, "MountPath", "MountPath", "required_without_env", "")
+	}
+}
+
+// mergeSecrets combines global and user secrets; a user secret replaces a
+// global secret with the same name.
+func mergeSecrets(global, user []SecretRef) []SecretRef {
+	if len(global) == 0 {
+		return user
+	}
+	if len(user) == 0 {
+		return global
+	}
+
+	userNames := make(map[string]bool, len(user))
+	for _, secret := range user {
+		userNames[secret.Name] = true
+	}
+
+	var result []SecretRef
+	for _, secret := range global {
+		if !userNames[secret.Name] {
+			result = append(result, secret)
+		}
+	}
+	return append(result, user...)
+}
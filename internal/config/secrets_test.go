@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBaseConfig_Secrets(t *testing.T) {
+	ok := &BaseConfig{Secrets: []SecretRef{
+		{Name: "api-tokens", Env: true},
+		{Name: "wandb.credentials", MountPath: "/etc/wandb"},
+	}}
+	require.NoError(t, ValidateBaseConfig(ok))
+
+	for message, secret := range map[string]SecretRef{
+		"'Name' is required":     {Env: true},
+		"'Name' must be a valid": {Name: "Not_A_Secret", Env: true},
+		"'MountPath' must be":    {Name: "wandb", MountPath: "relative/path"},
+		"unless 'env' is true":   {Name: "wandb"},
+	} {
+		err := ValidateBaseConfig(&BaseConfig{Secrets: []SecretRef{secret}})
+		require.Error(t, err, message)
+		assert.Contains(t, err.Error(), message)
+	}
+}
+
+func TestMergeSecrets(t *testing.T) {
+	global := []SecretRef{
+		{Name: "api-tokens", Env: true},
+		{Name: "wandb", MountPath: "/etc/wandb"},
+	}
+	user := []SecretRef{
+		{Name: "wandb", Env: true}, // Override
+		{Name: "hf-token", Env: true},
+	}
+
+	assert.Equal(t, []SecretRef{
+		{Name: "api-tokens", Env: true},
+		{Name: "wandb", Env: true},
+		{Name: "hf-token", Env: true},
+	}, mergeSecrets(global, user))
+	assert.Equal(t, global, mergeSecrets(global, nil))
+	assert.Equal(t, user, mergeSecrets(nil, user))
+}
@@ -39,6 +39,85 @@ type BaseConfig struct {
 	// DevENV wide settings
 	Namespace       string `yaml:"namespace,omitempty" validate:"omitempty,min=1,max=63,hostname"`
 	EnvironmentName string `yaml:"environmentName,omitempty" validate:"omitempty,min=1,max=63,hostname"`
+	ClusterDomain   string `yaml:"clusterDomain,omitempty" validate:"omitempty,min=1,fqdn"`
+
+	// 'shared' namespace or one namespace per developer; only honored from global config
+	NamespaceStrategy string `yaml:"namespaceStrategy,omitempty" validate:"omitempty,oneof=shared per-developer"`
+
+	// Environment variables added to the env-vars ConfigMap
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// Existing Kubernetes Secrets exposed as environment variables or files
+	Secrets []SecretRef `yaml:"secrets,omitempty" validate:"dive"`
+
+	// Free-form values for custom templates, available as {{.Extra.<key>}}
+	ExtraValues map[string]any `yaml:"extraValues,omitempty"`
+
+	// Extra annotations on generated Service/Ingress objects
+	Annotations AnnotationsConfig `yaml:"annotations,omitempty"`
+
+	// NetworkPolicy isolation of the environment's pod
+	Network NetworkConfig `yaml:"network,omitempty"`
+
+	// Ingress class, extra hosts and TLS secret of the generated Ingress
+	Ingress IngressConfig `yaml:"ingress,omitempty"`
+
+	// Scheduling constraints for the environment's pod, rendered as in a PodSpec
+	NodeSelector map[string]string `yaml:"nodeSelector,omitempty"`
+	Tolerations  []Toleration      `yaml:"tolerations,omitempty" validate:"dive"`
+	Affinity     map[string]any    `yaml:"affinity,omitempty"`
+
+	// Liveness, readiness and startup probes of the environment's container
+	Probes ProbesConfig `yaml:"probes,omitempty"`
+
+	// Commands run after the environment's container starts and before it stops
+	Lifecycle LifecycleConfig `yaml:"lifecycle,omitempty"`
+
+	// Prometheus scraping of a metrics port and the Grafana dashboard
+	Observability ObservabilityConfig `yaml:"observability,omitempty"`
+
+	// Log tags, and the sink a fluent-bit sidecar ships log files to
+	Logging LoggingConfig `yaml:"logging,omitempty"`
+
+	// Generate a read-only ServiceAccount support staff can get time-limited tokens for
+	SupportAccess bool `yaml:"supportAccess,omitempty"`
+
+	// Run on preemptible (spot) nodes, which the cluster may reclaim at any time
+	Preemptible bool `yaml:"preemptible,omitempty"`
+
+	// How preemptible nodes are labeled and tainted; only honored from global config
+	PreemptibleNodes PreemptibleNodesConfig `yaml:"preemptibleNodes,omitempty"`
+
+	// CPU architecture of the nodes to run on; selects the image variant from imageVariants
+	Arch string `yaml:"arch,omitempty" validate:"omitempty,oneof=amd64 arm64"`
+
+	// Per-architecture variants of images, keyed by image and then arch; only honored from global config
+	ImageVariants map[string]map[string]string `yaml:"imageVariants,omitempty"`
+
+	// GPU types developers may request with resources.gpuType; only honored from global config
+	GPUTypes map[string]GPUTypeConfig `yaml:"gpuTypes,omitempty" validate:"dive"`
+
+	// Patterns for synthesizing unset developer fields (e.g., git email)
+	DerivedDefaults DerivedDefaultsConfig `yaml:"derivedDefaults,omitempty"`
+
+	// Named bundles developers opt into with 'profile'; only honored from global config
+	Profiles map[string]ProfileConfig `yaml:"profiles,omitempty" validate:"dive"`
+
+	// Validation tuning (rule toggles and custom rules); only honored from global config
+	Validation ValidationConfig `yaml:"validation,omitempty"`
+
+	// Commands run around generation; only honored from global config
+	Hooks HooksConfig `yaml:"hooks,omitempty"`
+
+	// Range 'devenv ports assign' picks SSH ports from; only honored from global config
+	SSHPortRange PortRangeConfig `yaml:"sshPortRange,omitempty"`
+
+	// External sources templates read values from with lookup; only honored from global config
+	DataSources map[string]DataSourceConfig `yaml:"dataSources,omitempty" validate:"dive"`
+
+	// loadWarnings records encoding fixes applied while reading the file
+	// (BOM, line endings); validation reports them as warnings
+	loadWarnings []ValidationIssue
 }
 
 // DevEnvConfig represents the complete configuration for a developer environment.
@@ -58,7 +137,18 @@ type DevEnvConfig struct {
 	TargetNodes  []string      `yaml:"targetNodes,omitempty" validate:"dive,hostname"`
 	Git          GitConfig     `yaml:"git,omitempty"`
 	Refresh      RefreshConfig `yaml:"refresh,omitempty"`
-	DeveloperDir string        `yaml:"-"` // Directory where the developer config is located
+	Profile      string        `yaml:"profile,omitempty"` // Name of a profile from devenv.yaml applied before this config
+	DeveloperDir string        `yaml:"-"`                 // Directory where the developer config is located
+	Environment  string        `yaml:"-"`                 // Named environment applied on top (environments/<name>.yaml), if any
+
+	// Last day (YYYY-MM-DD) the environment is needed; validation warns as it approaches
+	Expires string `yaml:"expires,omitempty" validate:"omitempty,datetime=2006-01-02"`
+
+	// Derived lists values synthesized from DerivedDefaults during loading
+	Derived []DerivedValue `yaml:"-"`
+
+	// Warnings lists non-blocking validation issues found during loading
+	Warnings []ValidationIssue `yaml:"-"`
 }
 
 // GitConfig represents Git-related configuration
@@ -83,19 +173,80 @@ type GitRepo struct {
 	Directory  string `yaml:"directory,omitempty" validate:"omitempty,min=1,filepath"`
 }
 
-// ResourceConfig represents resource allocation
+// ResourceConfig represents resource allocation. CPU and Memory are the
+// requested amounts; they also act as limits unless Limits overrides them.
 type ResourceConfig struct {
 	CPU     any    `yaml:"cpu,omitempty" validate:"omitempty,k8s_cpu"`
 	Memory  any    `yaml:"memory,omitempty" validate:"omitempty,k8s_memory"`
 	Storage string `yaml:"storage,omitempty" validate:"omitempty,k8s_memory"`
-	GPU     int    `yaml:"gpu,omitempty" validate:"omitempty,min=0,max=8"` // Number of GPUs requested
+	// Home directory PVC StorageClass; unset keeps the home directory on a host path
+	StorageClass string `yaml:"storageClass,omitempty" validate:"omitempty,max=253,hostname"`
+	// Access mode of the generated PVCs; ReadWriteMany lets named environments on other nodes share them
+	StorageAccessMode string         `yaml:"storageAccessMode,omitempty" validate:"omitempty,oneof=ReadWriteOnce ReadWriteMany"`
+	GPU               int            `yaml:"gpu,omitempty" validate:"omitempty,min=0,max=8"` // Number of GPUs requested
+	Limits            ResourceLimits `yaml:"limits,omitempty"`
+	// GPU type from gpuTypes in global config, and MIG slice of it to request instead of whole GPUs
+	GPUType    string `yaml:"gpuType,omitempty"`
+	MIGProfile string `yaml:"migProfile,omitempty"`
+}
+
+// ResourceLimits sets container limits that differ from the requested
+// amounts, e.g. request 2 cores but allow bursting to 8. Unset fields fall
+// back to the corresponding request.
+type ResourceLimits struct {
+	CPU    any `yaml:"cpu,omitempty" validate:"omitempty,k8s_cpu"`
+	Memory any `yaml:"memory,omitempty" validate:"omitempty,k8s_memory"`
 }
 
-// VolumeMount represents a volume mount configuration
+// VolumeMount represents a volume mount configuration. A volume is either
+// a host path (LocalPath) or, when Size is set, a PersistentVolumeClaim
+// generated for the developer.
 type VolumeMount struct {
 	Name          string `yaml:"name" validate:"required,min=1,max=63,alphanum"`
-	LocalPath     string `yaml:"localPath" validate:"required,mount_path"`
+	LocalPath     string `yaml:"localPath,omitempty" validate:"omitempty,mount_path"`
 	ContainerPath string `yaml:"containerPath" validate:"required,mount_path"`
+	Size          string `yaml:"size,omitempty" validate:"omitempty,k8s_memory"`               // PVC size, e.g. "100Gi"
+	StorageClass  string `yaml:"storageClass,omitempty" validate:"omitempty,max=253,hostname"` // Defaults to resources.storageClass
+}
+
+// ValidationConfig lets operators tune configuration validation without
+// recompiling. Rule IDs have the form "<field>:<rule>", where <field> is the
+// YAML path of the field (e.g., "uid", "git.email") and <rule> is the
+// validator tag or semantic check name (e.g., "min", "absolute").
+// Rules listed in WarnRules are reported as warnings instead of errors.
+type ValidationConfig struct {
+	DisabledRules []string     `yaml:"disabledRules,omitempty"`
+	WarnRules     []string     `yaml:"warnRules,omitempty"`
+	CustomRules   []CustomRule `yaml:"customRules,omitempty" validate:"dive"`
+	Limits        InputLimits  `yaml:"limits,omitempty"`
+}
+
+// CustomRule is a regex rule evaluated against a string field. For list
+// fields (e.g., packages.apt) every element must match the pattern.
+type CustomRule struct {
+	Name    string `yaml:"name" validate:"required,min=1"`
+	Field   string `yaml:"field" validate:"required,min=1"`
+	Pattern string `yaml:"pattern" validate:"required,min=1"`
+	Message string `yaml:"message,omitempty"`
+}
+
+// HooksConfig lists shell commands devenv generate runs before and after
+// writing manifests, so sites can add their own steps (e.g., registering
+// environments in an inventory) without forking. Commands run in order
+// with sh -c and receive the run context as DEVENV_* environment variables.
+type HooksConfig struct {
+	PreGenerate  []string `yaml:"preGenerate,omitempty" validate:"dive,min=1"`
+	PostGenerate []string `yaml:"postGenerate,omitempty" validate:"dive,min=1"`
+}
+
+// PortRangeConfig bounds the NodePorts devenv assigns automatically, so a
+// cluster can reserve part of the NodePort range for other services. Unset
+// bounds default to the ends of the NodePort range. Reserved ports are
+// never assigned, and validation rejects developers that use them.
+type PortRangeConfig struct {
+	Min      int   `yaml:"min,omitempty" validate:"omitempty,min=30000,max=32767"`
+	Max      int   `yaml:"max,omitempty" validate:"omitempty,min=30000,max=32767,gtefield=Min"`
+	Reserved []int `yaml:"reserved,omitempty" validate:"dive,min=30000,max=32767"`
 }
 
 // RefreshConfig represents auto-refresh settings
@@ -106,6 +257,9 @@ type RefreshConfig struct {
 	PreserveHome bool   `yaml:"preserveHome,omitempty"`
 }
 
+// defaultClusterDomain is the DNS domain used by most Kubernetes clusters.
+const defaultClusterDomain = "cluster.local"
+
 // NewBaseConfigWithDefaults creates a BaseConfig instance pre-populated with system defaults
 func NewBaseConfigWithDefaults() BaseConfig {
 	return BaseConfig{
@@ -130,6 +284,7 @@ func NewBaseConfigWithDefaults() BaseConfig {
 		Volumes:         []VolumeMount{}, // Empty slice - no default volumes
 		Namespace:       "devenv",        // Default namespace
 		EnvironmentName: "development",   // Default environment name
+		ClusterDomain:   defaultClusterDomain,
 	}
 }
 
@@ -145,6 +300,13 @@ func (c *BaseConfig) GetSSHKeys() ([]string, error) {
 	return normalizeSSHKeys(c.SSHPublicKey)
 }
 
+// Extra returns the free-form extraValues map so templates can reference
+// {{.Extra.foo}}. Values are not validated beyond YAML parsing; a missing
+// key renders as "<no value>" unless the template guards it.
+func (c *BaseConfig) Extra() map[string]any {
+	return c.ExtraValues
+}
+
 // Methods for DevEnvConfig (these are NOT promoted from BaseConfig)
 
 // GetDeveloperDir returns the filesystem path to the developer's configuration directory.
@@ -177,10 +339,10 @@ func (c *DevEnvConfig) GPU() int {
 // manifests.
 func (c *DevEnvConfig) CPU() string {
 	CPU_in_millicores, err := c.Resources.getCanonicalCPU()
-	if err != nil || CPU_in_millicores <= 0 {
+	if err != nil {
 		return "0"
 	}
-	return fmt.Sprintf("%dm", CPU_in_millicores)
+	return formatMillicores(CPU_in_millicores)
 }
 
 // Memory returns the canonical Kubernetes memory quantity for this config,
@@ -192,29 +354,95 @@ func (c *DevEnvConfig) CPU() string {
 // can omit the field in generated manifests.
 func (c *DevEnvConfig) Memory() string {
 	memory_in_Mi, err := c.Resources.getCanonicalMemory()
-	if err != nil || memory_in_Mi <= 0 {
+	if err != nil {
 		return ""
 	}
-	if memory_in_Mi%1024 == 0 {
-		return fmt.Sprintf("%dGi", memory_in_Mi/1024)
+	return formatMebibytes(memory_in_Mi)
+}
+
+// StorageSize returns the canonical size of the home directory volume
+// (e.g., "20Gi"), or the empty string when resources.storage is invalid.
+func (c *DevEnvConfig) StorageSize() string {
+	mebibytes, err := canonicalMemory(c.Resources.Storage)
+	if err != nil {
+		return ""
 	}
-	return fmt.Sprintf("%dMi", memory_in_Mi)
+	return formatMebibytes(mebibytes)
+}
+
+// HomeVolumeClaim reports whether the home directory is stored on a
+// PersistentVolumeClaim (resources.storageClass is set) instead of a host path.
+func (c *DevEnvConfig) HomeVolumeClaim() bool {
+	return c.Resources.StorageClass != ""
+}
+
+// Access modes of the generated PersistentVolumeClaims.
+const (
+	AccessModeReadWriteOnce = "ReadWriteOnce" // Mountable by pods on one node at a time
+	AccessModeReadWriteMany = "ReadWriteMany" // Mountable from any node; needs a StorageClass that supports it
+)
+
+// VolumeAccessMode returns the access mode of the generated
+// PersistentVolumeClaims, defaulting to ReadWriteOnce.
+func (c *DevEnvConfig) VolumeAccessMode() string {
+	if c.Resources.StorageAccessMode != "" {
+		return c.Resources.StorageAccessMode
+	}
+	return AccessModeReadWriteOnce
+}
+
+// HasVolumeClaims reports whether any PersistentVolumeClaim is generated,
+// for the home directory or for a volume with a size.
+func (c *DevEnvConfig) HasVolumeClaims() bool {
+	if c.HomeVolumeClaim() {
+		return true
+	}
+	for _, volume := range c.Volumes {
+		if volume.Size != "" {
+			return true
+		}
+	}
+	return false
 }
 
 // CPURequest returns the CPU resource request as a string suitable for Kubernetes manifests.
-// This is currently an alias for the CPU method, but separated for potential future
-// differentiation between limits and requests.
+// The request is the configured resources.cpu value, so this is the same as CPU.
 func (c *DevEnvConfig) CPURequest() string {
 	return c.CPU()
 }
 
 // MemoryRequest returns the memory resource request as a string suitable for Kubernetes manifests.
-// This is currently an alias for the Memory method, but separated for potential future
-// differentiation between limits and requests.
+// The request is the configured resources.memory value, so this is the same as Memory.
 func (c *DevEnvConfig) MemoryRequest() string {
 	return c.Memory()
 }
 
+// CPULimit returns the CPU limit in the same format as CPU. It is
+// resources.limits.cpu when set and falls back to the request otherwise.
+func (c *DevEnvConfig) CPULimit() string {
+	if c.Resources.Limits.CPU == nil {
+		return c.CPU()
+	}
+	millicores, err := c.Resources.Limits.getCanonicalCPU()
+	if err != nil {
+		return "0"
+	}
+	return formatMillicores(millicores)
+}
+
+// MemoryLimit returns the memory limit in the same format as Memory. It is
+// resources.limits.memory when set and falls back to the request otherwise.
+func (c *DevEnvConfig) MemoryLimit() string {
+	if c.Resources.Limits.Memory == nil {
+		return c.Memory()
+	}
+	mebibytes, err := c.Resources.Limits.getCanonicalMemory()
+	if err != nil {
+		return ""
+	}
+	return formatMebibytes(mebibytes)
+}
+
 // NodePort returns the SSH port number for NodePort service configuration.
 // This is an alias for the SSHPort field, providing template-friendly access
 // to the port value for Kubernetes NodePort services.
@@ -222,6 +450,41 @@ func (c *DevEnvConfig) NodePort() int {
 	return c.SSHPort
 }
 
+// PodHostname returns the stable name of the developer's pod, which is the
+// StatefulSet name with the "-0" ordinal suffix.
+func (c *DevEnvConfig) PodHostname() string {
+	name, _ := ResourceName(ResourceDevEnv, c.InstanceName())
+	return name + "-0"
+}
+
+// InstanceName returns the name generated resources are derived from: the
+// developer name, suffixed with the environment name when one is applied
+// (e.g., "alice-gpu"), so environments can run side by side.
+func (c *DevEnvConfig) InstanceName() string {
+	if c.Environment == "" {
+		return c.Name
+	}
+	return c.Name + "-" + c.Environment
+}
+
+// ServiceFQDN returns the fully qualified DNS name of the headless governing
+// Service of the developer's StatefulSet. An unset cluster domain falls back
+// to the Kubernetes default.
+func (c *DevEnvConfig) ServiceFQDN() string {
+	name, _ := ResourceName(ResourceDevEnv, c.InstanceName())
+	domain := c.ClusterDomain
+	if domain == "" {
+		domain = defaultClusterDomain
+	}
+	return fmt.Sprintf("%s.%s.svc.%s", name, c.Namespace, domain)
+}
+
+// PodFQDN returns the stable DNS name of the developer's pod
+// (<pod>.<service>.<namespace>.svc.<clusterDomain>) for pod-to-pod addressing.
+func (c *DevEnvConfig) PodFQDN() string {
+	return c.PodHostname() + "." + c.ServiceFQDN()
+}
+
 // VolumeMounts returns the configured volume mount specifications.
 // Returns the slice of VolumeMount configurations for binding local directories
 // into the developer environment container.
@@ -262,6 +525,13 @@ func (c *DevEnvConfig) GetSSHKeysString() string {
 	return fmt.Sprintf("%s\n", strings.Join(keys, "\n"))
 }
 
+// Validate runs full validation and returns an error if any error-severity
+// issue is found. Use Check to also obtain warnings.
 func (c *DevEnvConfig) Validate() error {
 	return ValidateDevEnvConfig(c)
 }
+
+// Check runs full validation and returns a report with every error and warning.
+func (c *DevEnvConfig) Check() *ValidationReport {
+	return CheckDevEnvConfig(c)
+}
@@ -16,6 +16,7 @@ func TestNewBaseConfigWithDefaults(t *testing.T) {
 	assert.Equal(t, "ubuntu:22.04", cfg.Image)
 	assert.Equal(t, 1000, cfg.UID)
 	assert.Equal(t, "/opt/venv/bin", cfg.PythonBinPath)
+	assert.Equal(t, "cluster.local", cfg.ClusterDomain)
 
 	// --- Container setup toggles ---
 	assert.True(t, cfg.InstallHomebrew)
@@ -310,6 +311,45 @@ func TestDevEnvConfig_NodePort(t *testing.T) {
 	}
 }
 
+// TestDevEnvConfig_Limits verifies that explicit limits are normalized like
+// requests and that unset limits fall back to the request.
+func TestDevEnvConfig_Limits(t *testing.T) {
+	cfg := &DevEnvConfig{BaseConfig: BaseConfig{Resources: ResourceConfig{CPU: 2, Memory: "8Gi"}}}
+	assert.Equal(t, "2000m", cfg.CPURequest())
+	assert.Equal(t, "2000m", cfg.CPULimit())
+	assert.Equal(t, "8Gi", cfg.MemoryRequest())
+	assert.Equal(t, "8Gi", cfg.MemoryLimit())
+
+	cfg.Resources.Limits = ResourceLimits{CPU: "8", Memory: 32}
+	assert.Equal(t, "2000m", cfg.CPURequest())
+	assert.Equal(t, "8000m", cfg.CPULimit())
+	assert.Equal(t, "8Gi", cfg.MemoryRequest())
+	assert.Equal(t, "32Gi", cfg.MemoryLimit())
+
+	cfg.Resources.Limits = ResourceLimits{CPU: "1500m", Memory: "1536Mi"}
+	assert.Equal(t, "1500m", cfg.CPULimit())
+	assert.Equal(t, "1536Mi", cfg.MemoryLimit())
+}
+
+// TestDevEnvConfig_PodFQDN verifies the stable DNS names derived from the
+// StatefulSet and its headless governing Service.
+func TestDevEnvConfig_PodFQDN(t *testing.T) {
+	cfg := &DevEnvConfig{
+		Name:       "alice",
+		BaseConfig: BaseConfig{Namespace: "devenv", ClusterDomain: "cluster.local"},
+	}
+	assert.Equal(t, "devenv-alice-0", cfg.PodHostname())
+	assert.Equal(t, "devenv-alice.devenv.svc.cluster.local", cfg.ServiceFQDN())
+	assert.Equal(t, "devenv-alice-0.devenv-alice.devenv.svc.cluster.local", cfg.PodFQDN())
+
+	cfg.ClusterDomain = "corp.internal"
+	assert.Equal(t, "devenv-alice-0.devenv-alice.devenv.svc.corp.internal", cfg.PodFQDN())
+
+	// Unset domain falls back to the Kubernetes default
+	cfg.ClusterDomain = ""
+	assert.Equal(t, "devenv-alice.devenv.svc.cluster.local", cfg.ServiceFQDN())
+}
+
 // TestDevEnvConfig_VolumeMounts verifies that VolumeMounts() returns a stable,
 // template-friendly view of the configured mounts:
 //   - order is preserved
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// hiddenRuneNames names the invisible characters most often pasted in from
+// wikis, chat tools and word processors.
+var hiddenRuneNames = map[rune]string{
+	'\u00A0': "non-breaking space",
+	'\u00AD': "soft hyphen",
+	'\u2007': "figure space",
+	'\u200B': "zero-width space",
+	'\u200C': "zero-width non-joiner",
+	'\u200D': "zero-width joiner",
+	'\u200E': "left-to-right mark",
+	'\u200F': "right-to-left mark",
+	'\u2060': "word joiner",
+	'\u202F': "narrow no-break space",
+	'\u3000': "ideographic space",
+	'\uFEFF': "byte order mark",
+}
+
+// isHiddenRune reports whether r is invisible or easily mistaken for an
+// ASCII space: Unicode format characters (zero-width spaces, joiners,
+// direction marks, BOM), non-ASCII spaces and control characters other than
+// tab and newlines.
+func isHiddenRune(r rune) bool {
+	switch {
+	case r == '\t' || r == '\n' || r == '\r':
+		return false
+	case unicode.Is(unicode.Cf, r), unicode.IsControl(r):
+		return true
+	case r > unicode.MaxASCII && unicode.IsSpace(r):
+		return true
+	}
+	return false
+}
+
+// trimHidden trims whitespace and hidden characters from both ends of s.
+// Unlike strings.TrimSpace it also removes zero-width characters and BOMs,
+// which are never meaningful at the edges of a value.
+func trimHidden(s string) string {
+	return strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || isHiddenRune(r)
+	})
+}
+
+// describeRune returns a human-readable name for a hidden character.
+func describeRune(r rune) string {
+	if name, ok := hiddenRuneNames[r]; ok {
+		return fmt.Sprintf("%s (U+%04X)", name, r)
+	}
+	if unicode.IsControl(r) {
+		return fmt.Sprintf("control character (U+%04X)", r)
+	}
+	return fmt.Sprintf("invisible character (U+%04X)", r)
+}
+
+// checkHiddenRunes returns an error naming the first hidden character in
+// value and its 1-based character position, or nil if there is none.
+func checkHiddenRunes(field, value string) error {
+	for i, r := range value {
+		if !isHiddenRune(r) {
+			continue
+		}
+		position := utf8.RuneCountInString(value[:i]) + 1
+		return fmt.Errorf("'%s' contains a %s at character %d; it was probably pasted from a web page or document, retype the value by hand",
+			field, describeRune(r), position)
+	}
+	return nil
+}
+
+// normalizeStringFields trims whitespace and hidden characters around the
+// fields that end up in Kubernetes object names and image references.
+func (c *BaseConfig) normalizeStringFields() {
+	c.Image = trimHidden(c.Image)
+	c.HostName = trimHidden(c.HostName)
+	c.Namespace = trimHidden(c.Namespace)
+}
+
+// addHiddenRuneIssues reports hidden characters left inside identifier-like
+// fields after normalization. Kubernetes would reject these values much
+// later with a far less helpful error.
+func addHiddenRuneIssues(report *ValidationReport, config *BaseConfig) {
+	report.addError(ruleImageHiddenRunes, checkHiddenRunes("image", config.Image))
+	report.addError(ruleHostNameHiddenRunes, checkHiddenRunes("hostName", config.HostName))
+	report.addError(ruleNamespaceHiddenRunes, checkHiddenRunes("namespace", config.Namespace))
+
+	keys, err := config.GetSSHKeys()
+	if err != nil {
+		return // reported by the ssh key checks
+	}
+	for i, key := range keys {
+		field := "sshPublicKey"
+		if len(keys) > 1 {
+			field = fmt.Sprintf("sshPublicKey[%d]", i)
+		}
+		report.addError(ruleSSHKeysHiddenRunes, checkHiddenRunes(field, key))
+	}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrimHidden(t *testing.T) {
+	assert.Equal(t, "alice", trimHidden("\uFEFF alice\u200B\u00A0"))
+	assert.Equal(t, "a\u200Bb", trimHidden("a\u200Bb"), "interior characters are kept for validation to report")
+	assert.Equal(t, "", trimHidden("\u200B\u00A0"))
+}
+
+func TestCheckHiddenRunes(t *testing.T) {
+	assert.NoError(t, checkHiddenRunes("image", "ubuntu:22.04"))
+	assert.NoError(t, checkHiddenRunes("git.name", "José Müller"), "visible non-ASCII characters are fine")
+
+	err := checkHiddenRunes("image", "ubuntu:\u200B22.04")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'image' contains a zero-width space (U+200B) at character 8")
+
+	err = checkHiddenRunes("sshPublicKey", "ssh-ed25519\u00A0AAAA")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "non-breaking space (U+00A0) at character 12")
+
+	err = checkHiddenRunes("name", "al\x07ice")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "control character (U+0007)")
+}
+
+func TestCheckDevEnvConfig_HiddenUnicode(t *testing.T) {
+	cfg := &DevEnvConfig{
+		Name: "alice",
+		Git:  GitConfig{Name: "Alice", Email: "alice@example.com"},
+		BaseConfig: BaseConfig{
+			SSHPublicKey: []any{
+				"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAA alice@laptop",
+				"ssh-ed25519\u00A0AAAAC3NzaC1lZDI1NTE5AAAA alice@desktop",
+			},
+			Image: "ghcr.io/example/\u200Bdevenv:latest",
+		},
+	}
+
+	var rules []string
+	for _, issue := range CheckDevEnvConfig(cfg).Errors() {
+		rules = append(rules, issue.Rule)
+	}
+	assert.Contains(t, rules, "image:hidden_unicode")
+	assert.Contains(t, rules, "sshPublicKey:hidden_unicode")
+	assert.Contains(t, CheckDevEnvConfig(cfg).Err().Error(), "'sshPublicKey[1]' contains a non-breaking space")
+}
+
+func TestLoadDeveloperConfig_TrimsPastedCharacters(t *testing.T) {
+	tempDir := t.TempDir()
+	dir := filepath.Join(tempDir, "alice")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	content := "name: \"alice\u200B\"\n" +
+		"image: \"\u00A0ubuntu:22.04 \"\n" +
+		"sshPublicKey: \"\uFEFFssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAA alice@laptop\u00A0\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DeveloperConfigFile), []byte(content), 0o644))
+
+	cfg, err := LoadDeveloperConfig(tempDir, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", cfg.Name)
+	assert.Equal(t, "ubuntu:22.04", cfg.Image)
+	assert.Equal(t, []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAA alice@laptop"}, cfg.GetSSHKeysSlice())
+}
@@ -1,19 +1,20 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"path"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/go-playground/validator/v10"
 )
 
-// Package-level validator used by ValidateBaseConfig / ValidateDevEnvConfig.
-var validate *validator.Validate
-
 // sshKeyRE matches common OpenSSH public key formats:
 //
 //   - ssh-ed25519
@@ -44,25 +45,101 @@ var cpuMillicoresRe = regexp.MustCompile(`^\s*[0-9]+(?:\.[0-9]+)?m\s*$`)
 // Examples: "512Mi", "16Gi", "500M", "1G", "1536", " 2.5Gi ".
 var memoryRe = regexp.MustCompile(`(?i)^\s*[0-9]+(?:\.[0-9]+)?(?:ki|mi|gi|ti|pi|ei|k|m|g|t|p|e)?\s*$`)
 
-func init() {
+// builtinTags are the custom validator tags referenced from config struct
+// tags. Every Validator registers them; ValidatorOptions cannot override them.
+var builtinTags = map[string]validator.Func{
+	"ssh_keys":   validateSSHKeys,
+	"k8s_cpu":    validateKubernetesCPU,
+	"k8s_memory": validateKubernetesMemory,
+	"mount_path": validateMountPath,
+}
+
+// ValidatorOptions customizes a Validator beyond the built-in rules.
+type ValidatorOptions struct {
+	// Tags registers additional validator tags by name, e.g. an
+	// organization-specific "corp_registry" check. Built-in tag names
+	// cannot be reused.
+	Tags map[string]validator.Func
+
+	// FieldRules applies validator tag expressions to string fields of the
+	// merged developer config, keyed by YAML path (e.g. "image" or
+	// "packages.apt"). Expressions may combine built-in validator tags and
+	// names registered in Tags, such as "corp_registry" or
+	// "startswith=registry.example.com/". Failures are reported under the
+	// rule ID "<path>:<tag>".
+	FieldRules map[string]string
+}
+
+// Validator checks configs against the built-in rules plus any rules added
+// through ValidatorOptions. A Validator is not modified after NewValidator
+// returns and is safe for concurrent use.
+type Validator struct {
+	validate   *validator.Validate
+	fieldRules map[string]string
+}
+
+// NewValidator creates a Validator with the built-in tags and the additional
+// tags and field rules in opts.
+func NewValidator(opts ValidatorOptions) (*Validator, error) {
 	// Enable "required on structs" semantics and register custom validators.
-	validate = validator.New(validator.WithRequiredStructEnabled())
+	validate := validator.New(validator.WithRequiredStructEnabled())
 
-	if err := validate.RegisterValidation("ssh_keys", validateSSHKeys); err != nil {
-		panic(fmt.Errorf("register validator ssh_keys: %w", err))
-	}
-	if err := validate.RegisterValidation("k8s_cpu", validateKubernetesCPU); err != nil {
-		panic(fmt.Errorf("register validator k8s_cpu: %w", err))
+	for name, fn := range builtinTags {
+		if err := validate.RegisterValidation(name, fn); err != nil {
+			return nil, fmt.Errorf("register validator %s: %w", name, err)
+		}
 	}
-	if err := validate.RegisterValidation("k8s_memory", validateKubernetesMemory); err != nil {
-		panic(fmt.Errorf("register validator k8s_memory: %w", err))
+	validate.RegisterStructValidation(validateGitRepo, GitRepo{})
+	validate.RegisterStructValidation(validateVolumeMount, VolumeMount{})
+	validate.RegisterStructValidation(validateSecretRef, SecretRef{})
+	validate.RegisterStructValidation(validateDataSource, DataSourceConfig{})
+
+	for name, fn := range opts.Tags {
+		if _, builtin := builtinTags[name]; builtin {
+			return nil, fmt.Errorf("validator tag %q is built in and cannot be replaced", name)
+		}
+		if err := validate.RegisterValidation(name, fn); err != nil {
+			return nil, fmt.Errorf("register validator %s: %w", name, err)
+		}
 	}
-	if err := validate.RegisterValidation("mount_path", validateMountPath); err != nil {
-		panic(fmt.Errorf("register validator mount_path: %w", err))
+
+	fieldRules := make(map[string]string, len(opts.FieldRules))
+	for field, tag := range opts.FieldRules {
+		if _, err := lookupYAMLField(reflect.ValueOf(&DevEnvConfig{}), strings.Split(field, ".")); err != nil {
+			return nil, fmt.Errorf("field rule for %q: %w", field, err)
+		}
+		if err := checkTagExpression(validate, tag); err != nil {
+			return nil, fmt.Errorf("field rule for %q: %w", field, err)
+		}
+		fieldRules[field] = tag
 	}
-	validate.RegisterStructValidation(validateGitRepo, GitRepo{})
+
+	return &Validator{validate: validate, fieldRules: fieldRules}, nil
 }
 
+// checkTagExpression reports whether tag can be evaluated. The validator
+// library panics on unknown tags, so this probes the expression once up
+// front rather than failing in the middle of a check.
+func checkTagExpression(validate *validator.Validate, tag string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid tag expression %q: %v", tag, r)
+		}
+	}()
+	_ = validate.Var("", tag)
+	return nil
+}
+
+// defaultValidator backs the package-level Check and Validate functions. It
+// is built once on first use and never modified afterwards.
+var defaultValidator = sync.OnceValue(func() *Validator {
+	v, err := NewValidator(ValidatorOptions{})
+	if err != nil {
+		panic(err)
+	}
+	return v
+})
+
 // validateSSHKeys implements the "ssh_keys" tag.
 // It normalizes the flexible field (nil | string | []string | []any of string) to []string,
 // trims each entry, and validates format via sshKeyRE. It returns true iff all present
@@ -113,6 +190,18 @@ func validateGitRepo(sl validator.StructLevel) {
 	}
 }
 
+// validateVolumeMount requires a volume to be either a host path or a
+// PersistentVolumeClaim, but not both.
+func validateVolumeMount(sl validator.StructLevel) {
+	volume := sl.Current().Interface().(VolumeMount)
+	switch {
+	case volume.LocalPath == "" && volume.Size == "":
+		sl.ReportError(volume.LocalPath, "LocalPath", "LocalPath", "required", "")
+	case volume.LocalPath != "" && volume.Size != "":
+		sl.ReportError(volume.Size, "Size", "Size", "excluded_with_localpath", "")
+	}
+}
+
 // validateKubernetesCPU implements the "k8s_cpu" tag for *raw* CPU fields.
 // Accepts:
 //   - Strings: "", "unlimited", plain number ("2", "2.5"), or millicores ("500m")
@@ -218,51 +307,236 @@ func validateMountPath(fl validator.FieldLevel) bool {
 	return clean != "" && clean != "."
 }
 
-// ValidateDevEnvConfig runs tag-based validation and then applies
-// additional semantic checks that are easier to express in code.
+// ValidateDevEnvConfig runs full validation and returns an error describing
+// every error-severity issue, or nil if there are none. Warnings are ignored;
+// use CheckDevEnvConfig to obtain them.
 func ValidateDevEnvConfig(config *DevEnvConfig) error {
-	if err := validate.Struct(config); err != nil {
-		return formatValidationError(err)
-	}
-	if err := validatePythonBinPathAbsolute(config.PythonBinPath); err != nil {
-		return err
+	return CheckDevEnvConfig(config).Err()
+}
+
+// CheckDevEnvConfig runs tag-based validation and then applies additional
+// semantic checks that are easier to express in code, collecting all issues
+// into a ValidationReport instead of stopping at the first failure.
+//
+// Rules listed in config.Validation.DisabledRules are skipped, rules listed
+// in config.Validation.WarnRules are downgraded to warnings, and any
+// config.Validation.CustomRules are evaluated after the built-in checks.
+func CheckDevEnvConfig(config *DevEnvConfig) *ValidationReport {
+	return defaultValidator().CheckDevEnvConfig(config)
+}
+
+// CheckDevEnvConfig is like the package-level CheckDevEnvConfig but also
+// applies the validator's custom tags and field rules.
+func (v *Validator) CheckDevEnvConfig(config *DevEnvConfig) *ValidationReport {
+	report := newValidationReport(config.Validation)
+
+	if err := v.validate.Struct(config); err != nil {
+		report.addFieldErrors(err, reflect.TypeOf(*config))
 	}
+	report.addError(rulePythonBinPathAbsolute, validatePythonBinPathAbsolute(config.PythonBinPath))
+	report.addError(ruleNameHiddenRunes, checkHiddenRunes("name", config.Name))
+	addHiddenRuneIssues(report, &config.BaseConfig)
+	addDeveloperNameIssues(report, config.Name)
+	addResourceNameIssues(report, config.InstanceName())
 
 	// Require ≥1 SSH public key with valid format.
 	sshKeys, err := config.GetSSHKeys()
 	if err != nil {
-		return fmt.Errorf("invalid SSH public key(s): %w", err)
+		report.addError(ruleSSHKeysFormat, fmt.Errorf("invalid SSH public key(s): %w", err))
+	} else if len(sshKeys) == 0 {
+		report.addError(ruleSSHKeysRequired, fmt.Errorf("at least one SSH public key is required"))
 	}
 
-	if len(sshKeys) == 0 {
-		return fmt.Errorf("at least one SSH public key is required")
+	if _, err := config.Resources.getCanonicalCPU(); err != nil {
+		report.addError(ruleCPUQuantity, err) // "cpu must be >= 0"
 	}
 
-	if mc, err := config.Resources.getCanonicalCPU(); err != nil || mc < 0 {
-		return err // "cpu must be >= 0"
+	if _, err := config.Resources.getCanonicalMemory(); err != nil {
+		report.addError(ruleMemoryQuantity, err) // "memory must be >= 0"
 	}
 
-	if mi, err := config.Resources.getCanonicalMemory(); err != nil || mi < 0 {
-		return err // "memory must be >= 0"
+	if config.Resources.GPU < 0 {
+		report.addError(ruleGPUNonNegative, fmt.Errorf("gpu must be >= 0"))
+	}
+	addResourceLimitIssues(report, &config.Resources)
+	addInputLimitIssues(report, config)
+
+	addCrossFieldIssues(report, config)
+	addAnnotationIssues(report, config.Annotations)
+	addIngressIssues(report, config)
+	addSchedulingIssues(report, &config.BaseConfig)
+	addArchIssues(report, &config.BaseConfig)
+	addPreemptibleIssues(report, config)
+	addGPUTypeIssues(report, config)
+	addProbeIssues(report, config.Probes)
+	addLifecycleIssues(report, config.Lifecycle)
+	addObservabilityIssues(report, config.Observability, config.HTTPPort)
+	addLoggingIssues(report, config.Logging)
+	addExpiryIssues(report, config)
+	if _, ok := config.Affinity["nodeAffinity"]; ok && len(config.TargetNodes) > 0 {
+		report.addError(ruleAffinityTargetNodes, fmt.Errorf(
+			"'affinity.nodeAffinity' cannot be combined with 'targetNodes'; move the hostnames into the node affinity"))
+	}
+	addEnvIssues(report, config.Env)
+	v.addFieldRuleIssues(report, config)
+	report.addCustomRuleIssues(config)
+
+	// Soft checks: useful to fix, but never block generation.
+	report.addLoadWarnings(config.loadWarnings)
+	if config.Git.Email == "" {
+		report.addWarning(ruleGitEmailRecommended, "'git.email' is not set; commits made in the environment will have no author email")
+	}
+	if config.Git.Name == "" {
+		report.addWarning(ruleGitNameRecommended, "'git.name' is not set; commits made in the environment will have no author name")
 	}
 
-	if config.Resources.GPU < 0 {
-		return fmt.Errorf("gpu must be >= 0")
+	return report
+}
+
+// addResourceLimitIssues checks explicit limits: each must be a valid
+// quantity and must not be lower than the corresponding request, which
+// Kubernetes would reject when applying the StatefulSet.
+func addResourceLimitIssues(report *ValidationReport, resources *ResourceConfig) {
+	if resources.Limits.CPU != nil {
+		limit, err := resources.Limits.getCanonicalCPU()
+		if err != nil {
+			report.addError(ruleCPULimitQuantity, fmt.Errorf("'resources.limits.cpu': %w", err))
+		} else if request, err := resources.getCanonicalCPU(); err == nil && limit < request {
+			report.addError(ruleCPULimitBelowRequest, fmt.Errorf(
+				"'resources.limits.cpu' (%s) must not be lower than the requested 'resources.cpu' (%s)",
+				formatMillicores(limit), formatMillicores(request)))
+		}
 	}
 
-	return nil
+	if resources.Limits.Memory != nil {
+		limit, err := resources.Limits.getCanonicalMemory()
+		if err != nil {
+			report.addError(ruleMemoryLimitQuantity, fmt.Errorf("'resources.limits.memory': %w", err))
+		} else if request, err := resources.getCanonicalMemory(); err == nil && limit < request {
+			report.addError(ruleMemLimitBelowRequest, fmt.Errorf(
+				"'resources.limits.memory' (%s) must not be lower than the requested 'resources.memory' (%s)",
+				formatMebibytes(limit), formatMebibytes(request)))
+		}
+	}
+}
+
+// gpuImageHints are substrings that mark an image as GPU-capable. The check
+// is a heuristic, so a mismatch is reported as a warning rather than an error.
+var gpuImageHints = []string{"cuda", "gpu", "nvidia", "rocm", "tensorflow", "pytorch"}
+
+// addCrossFieldIssues applies semantic checks that span several fields and
+// therefore cannot be expressed as single-field validator tags.
+func addCrossFieldIssues(report *ValidationReport, config *DevEnvConfig) {
+	if image := config.ContainerImage(); config.GPU() > 0 && image != "" && !isGPUImage(image) {
+		report.addWarning(ruleGPUImage, fmt.Sprintf(
+			"'resources.gpu' requests %d GPU(s) but image %q does not look GPU-capable; use a CUDA/ROCm image or set 'validation.disabledRules: [%s]' if it is",
+			config.GPU(), image, ruleGPUImage))
+	}
+
+	if config.EnableAuth && !config.SkipAuth {
+		if strings.TrimSpace(config.AuthURL) == "" {
+			report.addError(ruleAuthURLRequired, fmt.Errorf(
+				"'authURL' is required when 'enableAuth' is true; set it to your OAuth2 proxy auth endpoint or set 'skipAuth: true'"))
+		}
+		if strings.TrimSpace(config.AuthSignIn) == "" {
+			report.addError(ruleAuthSignInRequired, fmt.Errorf(
+				"'authSignIn' is required when 'enableAuth' is true; set it to your OAuth2 proxy sign-in endpoint or set 'skipAuth: true'"))
+		}
+	}
+
+	if config.HTTPPort != 0 && strings.TrimSpace(config.HostName) == "" {
+		report.addError(ruleHostNameRequired, fmt.Errorf(
+			"'hostName' is required when 'httpPort' is set so the ingress host %s.<hostName> can be generated",
+			config.Name))
+	}
+
+	if config.Refresh.Enabled && strings.TrimSpace(config.Refresh.Schedule) == "" {
+		report.addError(ruleRefreshSchedule, fmt.Errorf(
+			"'refresh.schedule' is required when 'refresh.enabled' is true; provide a cron expression such as \"0 4 * * 0\""))
+	}
+}
+
+// isGPUImage reports whether image looks like a GPU-capable container image.
+func isGPUImage(image string) bool {
+	lower := strings.ToLower(image)
+	for _, hint := range gpuImageHints {
+		if strings.Contains(lower, hint) {
+			return true
+		}
+	}
+	return false
 }
 
 // ValidateBaseConfig validates only the BaseConfig portion; useful for
 // validating global defaults or partial configs before embedding.
 func ValidateBaseConfig(config *BaseConfig) error {
-	if err := validate.Struct(config); err != nil {
-		return formatValidationError(err)
+	return CheckBaseConfig(config).Err()
+}
+
+// CheckBaseConfig is the report-producing counterpart of ValidateBaseConfig.
+func CheckBaseConfig(config *BaseConfig) *ValidationReport {
+	return defaultValidator().CheckBaseConfig(config)
+}
+
+// CheckBaseConfig is like the package-level CheckBaseConfig but uses the
+// validator's custom tags. Field rules only apply to developer configs.
+func (v *Validator) CheckBaseConfig(config *BaseConfig) *ValidationReport {
+	report := newValidationReport(config.Validation)
+
+	if err := v.validate.Struct(config); err != nil {
+		report.addFieldErrors(err, reflect.TypeOf(*config))
 	}
-	if err := validatePythonBinPathAbsolute(config.PythonBinPath); err != nil {
-		return err
+	report.addError(rulePythonBinPathAbsolute, validatePythonBinPathAbsolute(config.PythonBinPath))
+	addHiddenRuneIssues(report, config)
+	addAnnotationIssues(report, config.Annotations)
+	addSchedulingIssues(report, config)
+	addArchIssues(report, config)
+	addGPUTypesIssues(report, config)
+	addProbeIssues(report, config.Probes)
+	addLifecycleIssues(report, config.Lifecycle)
+	addObservabilityIssues(report, config.Observability, 0)
+	addLoggingIssues(report, config.Logging)
+	addEnvIssues(report, config.Env)
+
+	for _, rule := range config.Validation.CustomRules {
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			report.addError(ruleID("customRules", rule.Name),
+				fmt.Errorf("custom validation rule %q has invalid pattern %q: %w", rule.Name, rule.Pattern, err))
+		}
+	}
+	report.addLoadWarnings(config.loadWarnings)
+
+	return report
+}
+
+// addFieldRuleIssues evaluates the validator's field rules against cfg.
+// Fields are visited in sorted order so reports are stable.
+func (v *Validator) addFieldRuleIssues(report *ValidationReport, cfg *DevEnvConfig) {
+	fields := make([]string, 0, len(v.fieldRules))
+	for field := range v.fieldRules {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		tag := v.fieldRules[field]
+		values, err := lookupYAMLField(reflect.ValueOf(cfg), strings.Split(field, "."))
+		if err != nil {
+			report.addError(ruleID(field, "field_rule"), fmt.Errorf("field rule for %q: %w", field, err))
+			continue
+		}
+		for _, value := range values {
+			var validationErrors validator.ValidationErrors
+			if err := v.validate.Var(value, tag); errors.As(err, &validationErrors) {
+				for _, fieldError := range validationErrors {
+					report.add(SeverityError, ruleID(field, fieldError.Tag()),
+						fmt.Sprintf("'%s' failed '%s' validation, got '%s'", field, fieldError.Tag(), value))
+				}
+			} else if err != nil {
+				report.addError(ruleID(field, "field_rule"), err)
+			}
+		}
 	}
-	return nil
 }
 
 func validatePythonBinPathAbsolute(p string) error {
@@ -276,20 +550,6 @@ func validatePythonBinPathAbsolute(p string) error {
 	return nil
 }
 
-// formatValidationError renders go-playground/validator errors as concise, user-facing text.
-func formatValidationError(err error) error {
-	var errorMessages []string
-
-	validationErrors := err.(validator.ValidationErrors)
-	for _, fieldError := range validationErrors {
-		message := formatFieldError(fieldError)
-		errorMessages = append(errorMessages, message)
-	}
-
-	return fmt.Errorf("configuration validation failed:\n  - %s",
-		strings.Join(errorMessages, "\n  - "))
-}
-
 // formatFieldError creates user-friendly error messages for field validation failures
 func formatFieldError(fieldError validator.FieldError) string {
 	fieldName := fieldError.Field()
@@ -316,6 +576,20 @@ func formatFieldError(fieldError validator.FieldError) string {
 		return fmt.Sprintf("'%s' must be a valid absolute mount path, got '%v'", fieldName, value)
 	case "cron":
 		return fmt.Sprintf("'%s' must be a valid cron expression, got '%v'", fieldName, value)
+	case "excluded_with_localpath":
+		return fmt.Sprintf("'%s' cannot be combined with 'localPath'; a volume is either a host path or a PersistentVolumeClaim", fieldName)
+	case "required_without_env":
+		return fmt.Sprintf("'%s' is required unless 'env' is true; a secret is mounted as files, environment variables or both", fieldName)
+	case "required_without_file":
+		return fmt.Sprintf("'%s' is required unless 'file' is set; a data source is a command or a file", fieldName)
+	case "excluded_with_command":
+		return fmt.Sprintf("'%s' cannot be combined with 'command'; a data source is a command or a file", fieldName)
+	case "gtefield":
+		return fmt.Sprintf("'%s' must be at least '%s', got '%v'", fieldName, param, value)
+	case "datetime":
+		return fmt.Sprintf("'%s' must be a date in the form YYYY-MM-DD, got '%v'", fieldName, value)
+	case "oneof":
+		return fmt.Sprintf("'%s' must be one of: %s, got '%v'", fieldName, strings.Join(strings.Fields(param), ", "), value)
 
 	case "ssh_keys":
 		return fmt.Sprintf("'%s' contains invalid SSH key format", fieldName)
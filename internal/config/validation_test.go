@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -45,7 +46,7 @@ func TestValidator_SSHKeys(t *testing.T) {
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := validate.Struct(&S{Keys: tc.val})
+			err := defaultValidator().validate.Struct(&S{Keys: tc.val})
 			if tc.ok {
 				require.NoError(t, err)
 			} else {
@@ -89,7 +90,7 @@ func TestValidator_K8SCPU(t *testing.T) {
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := validate.Struct(&S{CPU: tc.val})
+			err := defaultValidator().validate.Struct(&S{CPU: tc.val})
 			if tc.ok {
 				require.NoError(t, err)
 			} else {
@@ -135,7 +136,7 @@ func TestValidator_K8SMemory(t *testing.T) {
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := validate.Struct(&S{Mem: tc.val})
+			err := defaultValidator().validate.Struct(&S{Mem: tc.val})
 			if tc.ok {
 				require.NoError(t, err)
 			} else {
@@ -300,7 +301,7 @@ func TestValidator_MountPath(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := validate.Struct(&S{Path: tc.val})
+			err := defaultValidator().validate.Struct(&S{Path: tc.val})
 			if tc.ok {
 				require.NoError(t, err)
 			} else {
@@ -346,4 +347,229 @@ func TestValidateDevEnvConfig_VolumeMountPaths(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "ContainerPath")
 	})
+
+	t.Run("accepts a PVC volume without localPath", func(t *testing.T) {
+		cfg := newCfg("", "/data")
+		cfg.Volumes[0].Size = "100Gi"
+		require.NoError(t, ValidateDevEnvConfig(cfg))
+	})
+
+	t.Run("rejects a volume with both localPath and size", func(t *testing.T) {
+		cfg := newCfg("/mnt", "/mnt")
+		cfg.Volumes[0].Size = "100Gi"
+		err := ValidateDevEnvConfig(cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot be combined with 'localPath'")
+	})
+
+	t.Run("rejects an invalid size", func(t *testing.T) {
+		cfg := newCfg("", "/data")
+		cfg.Volumes[0].Size = "lots"
+		err := ValidateDevEnvConfig(cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Size")
+	})
+}
+
+//
+// --- cross-field rules -------------------------------------------------------
+//
+
+func TestCheckDevEnvConfig_CrossFieldRules(t *testing.T) {
+	newCfg := func() *DevEnvConfig {
+		return &DevEnvConfig{
+			Name: "alice",
+			Git:  GitConfig{Name: "Alice", Email: "alice@example.com"},
+			BaseConfig: BaseConfig{
+				Image:        "ubuntu:22.04",
+				SSHPublicKey: "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host",
+			},
+		}
+	}
+	rulesOf := func(issues []ValidationIssue) []string {
+		var rules []string
+		for _, issue := range issues {
+			rules = append(rules, issue.Rule)
+		}
+		return rules
+	}
+
+	t.Run("baseline has no issues", func(t *testing.T) {
+		assert.Empty(t, CheckDevEnvConfig(newCfg()).Issues)
+	})
+
+	t.Run("gpu with non-GPU image warns", func(t *testing.T) {
+		cfg := newCfg()
+		cfg.Resources.GPU = 1
+		report := CheckDevEnvConfig(cfg)
+		require.NoError(t, report.Err())
+		assert.Equal(t, []string{"image:gpu_capable"}, rulesOf(report.Warnings()))
+
+		cfg.Image = "nvcr.io/nvidia/cuda:12.4.1-devel-ubuntu22.04"
+		assert.Empty(t, CheckDevEnvConfig(cfg).Issues)
+	})
+
+	t.Run("enableAuth requires auth endpoints", func(t *testing.T) {
+		cfg := newCfg()
+		cfg.EnableAuth = true
+		report := CheckDevEnvConfig(cfg)
+		assert.ElementsMatch(t, []string{"authURL:required_with_auth", "authSignIn:required_with_auth"}, rulesOf(report.Errors()))
+
+		cfg.SkipAuth = true
+		assert.False(t, CheckDevEnvConfig(cfg).HasErrors())
+	})
+
+	t.Run("httpPort requires hostName", func(t *testing.T) {
+		cfg := newCfg()
+		cfg.HTTPPort = 8080
+		report := CheckDevEnvConfig(cfg)
+		assert.Equal(t, []string{"hostName:required_with_http"}, rulesOf(report.Errors()))
+		assert.Contains(t, report.Err().Error(), "alice.<hostName>")
+
+		cfg.HostName = "devenv.example.com"
+		assert.False(t, CheckDevEnvConfig(cfg).HasErrors())
+	})
+
+	t.Run("refresh.enabled requires schedule", func(t *testing.T) {
+		cfg := newCfg()
+		cfg.Refresh.Enabled = true
+		report := CheckDevEnvConfig(cfg)
+		assert.Equal(t, []string{"refresh.schedule:required_with_enabled"}, rulesOf(report.Errors()))
+
+		cfg.Refresh.Schedule = "0 4 * * 0"
+		assert.False(t, CheckDevEnvConfig(cfg).HasErrors())
+	})
+}
+
+//
+// --- developer name policy ---------------------------------------------------
+//
+
+func TestCheckDevEnvConfig_ResourceLimits(t *testing.T) {
+	newCfg := func(cpu, memory, cpuLimit, memoryLimit any) *DevEnvConfig {
+		return &DevEnvConfig{
+			Name: "alice",
+			Git:  GitConfig{Name: "Alice", Email: "alice@example.com"},
+			BaseConfig: BaseConfig{
+				SSHPublicKey: "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host",
+				Resources: ResourceConfig{
+					CPU:    cpu,
+					Memory: memory,
+					Limits: ResourceLimits{CPU: cpuLimit, Memory: memoryLimit},
+				},
+			},
+		}
+	}
+
+	assert.Empty(t, CheckDevEnvConfig(newCfg(2, "8Gi", 8, "32Gi")).Issues)
+	assert.Empty(t, CheckDevEnvConfig(newCfg(2, "8Gi", nil, nil)).Issues, "limits are optional")
+	assert.Empty(t, CheckDevEnvConfig(newCfg(2, "8Gi", "2000m", "8192Mi")).Issues, "equal limits are allowed")
+
+	report := CheckDevEnvConfig(newCfg(4, "16Gi", 2, "8Gi"))
+	var rules []string
+	for _, issue := range report.Errors() {
+		rules = append(rules, issue.Rule)
+	}
+	assert.ElementsMatch(t, []string{"resources.limits.cpu:gte_request", "resources.limits.memory:gte_request"}, rules)
+	assert.Contains(t, report.Err().Error(), "'resources.limits.cpu' (2000m) must not be lower than the requested 'resources.cpu' (4000m)")
+
+	report = CheckDevEnvConfig(newCfg(2, "8Gi", "lots", nil))
+	require.Error(t, report.Err())
+	assert.Contains(t, report.Err().Error(), "limits")
+}
+
+func TestCheckDevEnvConfig_NamePolicy(t *testing.T) {
+	cases := []struct {
+		name string
+		rule string // expected error rule, empty when valid
+	}{
+		{"alice", ""},
+		{"bob-2", ""},
+		{"Alice", "name:dns_label"},
+		{"alice.smith", "name:dns_label"},
+		{"manager", "name:reserved"},
+		{"Namespace", "name:reserved"},
+		{"home", "name:reserved"},
+		{"ssh-bob", "name:reserved_prefix"},
+		{"home-al", "name:reserved_prefix"},
+		{"kube-admin", "name:reserved_prefix"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &DevEnvConfig{
+				Name: tc.name,
+				Git:  GitConfig{Name: "Dev", Email: "dev@example.com"},
+				BaseConfig: BaseConfig{
+					SSHPublicKey: "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host",
+				},
+			}
+			report := CheckDevEnvConfig(cfg)
+			if tc.rule == "" {
+				assert.False(t, report.HasErrors(), "unexpected errors: %v", report.Err())
+				return
+			}
+			var rules []string
+			for _, issue := range report.Errors() {
+				rules = append(rules, issue.Rule)
+			}
+			assert.Contains(t, rules, tc.rule)
+		})
+	}
+}
+
+func TestSuggestDNSLabel(t *testing.T) {
+	assert.Equal(t, "alice-smith", suggestDNSLabel("Alice.Smith"))
+	assert.Equal(t, "bob", suggestDNSLabel("_Bob_"))
+}
+
+func TestNewValidator_CustomTagsAndFieldRules(t *testing.T) {
+	v, err := NewValidator(ValidatorOptions{
+		Tags: map[string]validator.Func{
+			"corp_registry": func(fl validator.FieldLevel) bool {
+				return strings.HasPrefix(fl.Field().String(), "registry.example.com/")
+			},
+		},
+		FieldRules: map[string]string{
+			"image":        "corp_registry",
+			"packages.apt": "excludes=telnet",
+		},
+	})
+	require.NoError(t, err)
+
+	cfg := &DevEnvConfig{
+		Name: "alice",
+		Git:  GitConfig{Name: "Alice", Email: "alice@example.com"},
+		BaseConfig: BaseConfig{
+			SSHPublicKey: "ssh-ed25519 AAAAB3NzaC1lZDI1NTE5AAAA user@host",
+			Image:        "registry.example.com/devenv:1.0",
+			Packages:     PackageConfig{APT: []string{"vim"}},
+		},
+	}
+	assert.Empty(t, v.CheckDevEnvConfig(cfg).Issues)
+	assert.Empty(t, CheckDevEnvConfig(cfg).Issues)
+
+	cfg.Image = "docker.io/ubuntu:22.04"
+	cfg.Packages.APT = []string{"vim", "telnet"}
+	var rules []string
+	for _, issue := range v.CheckDevEnvConfig(cfg).Errors() {
+		rules = append(rules, issue.Rule)
+	}
+	assert.Equal(t, []string{"image:corp_registry", "packages.apt:excludes"}, rules)
+	assert.Empty(t, CheckDevEnvConfig(cfg).Issues, "the default validator has no field rules")
+
+	cfg.Validation.DisabledRules = []string{"image:corp_registry"}
+	require.Len(t, v.CheckDevEnvConfig(cfg).Errors(), 1)
+}
+
+func TestNewValidator_InvalidOptions(t *testing.T) {
+	_, err := NewValidator(ValidatorOptions{
+		Tags: map[string]validator.Func{"k8s_cpu": func(validator.FieldLevel) bool { return true }},
+	})
+	assert.ErrorContains(t, err, "built in")
+
+	_, err = NewValidator(ValidatorOptions{FieldRules: map[string]string{"imgae": "required"}})
+	assert.ErrorContains(t, err, `unknown field "imgae"`)
+
+	_, err = NewValidator(ValidatorOptions{FieldRules: map[string]string{"image": "no_such_tag"}})
+	assert.ErrorContains(t, err, "invalid tag expression")
 }
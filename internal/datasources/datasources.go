@@ -0,0 +1,150 @@
+// Package datasources resolves the values templates read with lookup from
+// the external sources declared under dataSources in devenv.yaml. A source
+// is a command, run with sh -c and the key as "$1", or a YAML or JSON file
+// mapping keys to values. Templates never reach the network themselves;
+// they only see what the declared sources return.
+package datasources
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Registry resolves lookups against a set of declared sources. Every
+// source and key is resolved once per Registry and the result, including
+// an error, is cached, so all templates of a run see the same value and a
+// command runs at most once per key. A Registry is safe for concurrent use.
+type Registry struct {
+	configDir string
+	sources   map[string]config.DataSourceConfig
+
+	mu    sync.Mutex
+	cache map[string]*result
+}
+
+// result is a cached lookup.
+type result struct {
+	once  sync.Once
+	value any
+	err   error
+}
+
+// New returns a Registry for sources, typically the dataSources of
+// devenv.yaml. File sources are relative to configDir.
+func New(configDir string, sources map[string]config.DataSourceConfig) *Registry {
+	return &Registry{
+		configDir: configDir,
+		sources:   sources,
+		cache:     make(map[string]*result),
+	}
+}
+
+// Lookup returns the value of key in the named source.
+func (r *Registry) Lookup(source, key string) (any, error) {
+	sourceConfig, ok := r.sources[source]
+	if !ok {
+		return nil, fmt.Errorf("unknown data source %q (declare it under dataSources in devenv.yaml)", source)
+	}
+
+	entry := r.entry(source + "\x00" + key)
+	entry.once.Do(func() {
+		if sourceConfig.Command != "" {
+			entry.value, entry.err = r.runCommand(source, sourceConfig, key)
+		} else {
+			entry.value, entry.err = r.readFileKey(source, sourceConfig, key)
+		}
+	})
+	return entry.value, entry.err
+}
+
+// entry returns the cache entry for id, creating it if needed.
+func (r *Registry) entry(id string) *result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.cache[id]
+	if !ok {
+		entry = &result{}
+		r.cache[id] = entry
+	}
+	return entry
+}
+
+// runCommand runs a command source for key and parses its output. The key
+// is passed as "$1" and, with the source name and config directory, as
+// DEVENV_* environment variables.
+func (r *Registry) runCommand(source string, sourceConfig config.DataSourceConfig, key string) (any, error) {
+	timeout := time.Duration(sourceConfig.Timeout()) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// sh -c assigns the arguments after the command to $0, $1, ...
+	cmd := exec.CommandContext(ctx, "sh", "-c", sourceConfig.Command, source, key)
+	cmd.Env = append(os.Environ(),
+		"DEVENV_LOOKUP_SOURCE="+source,
+		"DEVENV_LOOKUP_KEY="+key,
+		"DEVENV_CONFIG_DIR="+r.configDir,
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait for children of the shell that outlive a timeout
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			err = fmt.Errorf("%w: %s", err, message)
+		}
+		return nil, fmt.Errorf("data source %s failed for %q: %w", source, key, err)
+	}
+
+	var value any
+	if err := yaml.Unmarshal(stdout.Bytes(), &value); err != nil {
+		return nil, fmt.Errorf("data source %s returned invalid YAML for %q: %w", source, key, err)
+	}
+	return value, nil
+}
+
+// readFileKey returns key from a file source. The file is read once per
+// Registry.
+func (r *Registry) readFileKey(source string, sourceConfig config.DataSourceConfig, key string) (any, error) {
+	entry := r.entry(source)
+	entry.once.Do(func() {
+		path := sourceConfig.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(r.configDir, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			entry.err = fmt.Errorf("data source %s: %w", source, err)
+			return
+		}
+		var values map[string]any
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			entry.err = fmt.Errorf("data source %s: failed to parse %s: %w", source, path, err)
+			return
+		}
+		entry.value = values
+	})
+	if entry.err != nil {
+		return nil, entry.err
+	}
+
+	value, ok := entry.value.(map[string]any)[key]
+	if !ok {
+		return nil, fmt.Errorf("data source %s has no value for %q", source, key)
+	}
+	return value, nil
+}
@@ -0,0 +1,83 @@
+package datasources
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup_Command(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "runs")
+	registry := New("/configs", map[string]config.DataSourceConfig{
+		"cmdb": {Command: `echo run >> ` + counter + `; echo "{costCenter: CC-$1, dir: $DEVENV_CONFIG_DIR, source: $DEVENV_LOOKUP_SOURCE}"`},
+	})
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := registry.Lookup("cmdb", "alice")
+			assert.NoError(t, err)
+			assert.Equal(t, map[string]any{"costCenter": "CC-alice", "dir": "/configs", "source": "cmdb"}, value)
+		}()
+	}
+	wg.Wait()
+
+	// Concurrent lookups of the same key run the command once
+	runs, err := os.ReadFile(counter)
+	require.NoError(t, err)
+	assert.Equal(t, "run\n", string(runs))
+
+	value, err := registry.Lookup("cmdb", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, "CC-bob", value.(map[string]any)["costCenter"])
+}
+
+func TestLookup_CommandErrors(t *testing.T) {
+	registry := New("", map[string]config.DataSourceConfig{
+		"failing": {Command: "echo 'no such host' >&2; exit 3"},
+		"slow":    {Command: "sleep 5", TimeoutSeconds: 1},
+		"invalid": {Command: "echo '{unclosed'"},
+	})
+
+	_, err := registry.Lookup("failing", "alice")
+	assert.ErrorContains(t, err, `data source failing failed for "alice": exit status 3: no such host`)
+
+	_, err = registry.Lookup("slow", "alice")
+	assert.ErrorContains(t, err, "timed out after 1s")
+
+	_, err = registry.Lookup("invalid", "alice")
+	assert.ErrorContains(t, err, "returned invalid YAML")
+
+	_, err = registry.Lookup("missing", "alice")
+	assert.ErrorContains(t, err, `unknown data source "missing"`)
+}
+
+func TestLookup_File(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "ipam.yaml"), []byte("alice:\n  ip: 10.0.0.5\nbob: 10.0.0.6\n"), 0644))
+	registry := New(configDir, map[string]config.DataSourceConfig{
+		"ipam":    {File: "ipam.yaml"},
+		"missing": {File: "nope.yaml"},
+	})
+
+	value, err := registry.Lookup("ipam", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"ip": "10.0.0.5"}, value)
+
+	value, err = registry.Lookup("ipam", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.6", value)
+
+	_, err = registry.Lookup("ipam", "carol")
+	assert.ErrorContains(t, err, `data source ipam has no value for "carol"`)
+
+	_, err = registry.Lookup("missing", "alice")
+	assert.ErrorContains(t, err, "data source missing:")
+}
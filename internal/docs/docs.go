@@ -0,0 +1,203 @@
+// Package docs renders an overview of the developer environments defined in
+// a config directory, as HTML or Markdown, for publishing on an internal
+// portal. Only merged config values that are safe to publish are included:
+// SSH keys, environment variables, extraValues (other than the grouping
+// key), git identities and auth settings are left out.
+package docs
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+)
+
+// Supported output formats.
+const (
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+)
+
+// Formats lists the supported output formats.
+var Formats = []string{FormatHTML, FormatMarkdown}
+
+// Ungrouped is the group of environments without a value for the grouping key.
+const Ungrouped = "Ungrouped"
+
+//go:embed templates
+var templates embed.FS
+
+// Environment is the published summary of one developer environment.
+type Environment struct {
+	Developer   string
+	Environment string // Named environment, empty for the default one
+	Instance    string // Name resources are derived from, e.g. "alice-gpu"
+	Namespace   string
+	Profile     string
+	Image       string
+	CPU         string // Request
+	CPULimit    string
+	Memory      string // Request
+	MemoryLimit string
+	GPU         int
+	Storage     string
+	SSHPort     int
+	HTTPPort    int
+	URL         string // Ingress URL when HTTP is exposed
+	Error       string // Set when the config could not be loaded; not published
+}
+
+// Group is a set of environments sharing a value of the grouping key.
+type Group struct {
+	Name         string
+	Environments []Environment
+}
+
+// Site is the content of the generated page.
+type Site struct {
+	GroupBy    string // extraValues key environments are grouped by, if any
+	Groups     []Group
+	Developers int
+	Failed     int // Environments whose config could not be loaded
+}
+
+// Collect loads every developer and named environment in configDir. When
+// groupBy is set, environments are grouped by that extraValues key (e.g.,
+// "team"). Configs that fail to load are included with Error set so broken
+// entries are visible rather than silently missing.
+func Collect(configDir, groupBy string) (*Site, error) {
+	globalConfig, err := config.LoadGlobalConfig(configDir)
+	if err != nil {
+		return nil, err
+	}
+	developers, err := config.ListDeveloperDirs(configDir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(developers)
+
+	site := &Site{GroupBy: groupBy, Developers: len(developers)}
+	groups := make(map[string][]Environment)
+	add := func(env Environment, cfg *config.DevEnvConfig) {
+		if env.Error != "" {
+			site.Failed++
+		}
+		group := ""
+		if groupBy != "" {
+			group = Ungrouped
+			if cfg != nil {
+				if value, ok := cfg.Extra()[groupBy]; ok && value != nil {
+					group = fmt.Sprint(value)
+				}
+			}
+		}
+		groups[group] = append(groups[group], env)
+	}
+
+	for _, developer := range developers {
+		cfg, err := config.LoadDeveloperConfigWithBaseConfig(configDir, developer, globalConfig)
+		if err != nil {
+			add(Environment{Developer: developer, Instance: developer, Error: err.Error()}, nil)
+			continue
+		}
+		add(summarize(cfg, developer), cfg)
+
+		environments, err := config.ListEnvironments(configDir, developer)
+		if err != nil {
+			return nil, err
+		}
+		for _, environment := range environments {
+			envConfig, err := config.LoadDeveloperEnvironment(configDir, developer, environment, globalConfig)
+			if err != nil {
+				add(Environment{
+					Developer:   developer,
+					Environment: environment,
+					Instance:    developer + "-" + environment,
+					Error:       err.Error(),
+				}, cfg)
+				continue
+			}
+			add(summarize(envConfig, developer), envConfig)
+		}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		// Ungrouped environments come last
+		if (names[i] == Ungrouped) != (names[j] == Ungrouped) {
+			return names[j] == Ungrouped
+		}
+		return names[i] < names[j]
+	})
+	for _, name := range names {
+		site.Groups = append(site.Groups, Group{Name: name, Environments: groups[name]})
+	}
+	return site, nil
+}
+
+// summarize picks the publishable fields of a merged config.
+func summarize(cfg *config.DevEnvConfig, developer string) Environment {
+	env := Environment{
+		Developer:   developer,
+		Environment: cfg.Environment,
+		Instance:    cfg.InstanceName(),
+		Namespace:   cfg.Namespace,
+		Profile:     cfg.Profile,
+		Image:       cfg.ContainerImage(),
+		CPU:         cfg.CPURequest(),
+		CPULimit:    cfg.CPULimit(),
+		Memory:      cfg.MemoryRequest(),
+		MemoryLimit: cfg.MemoryLimit(),
+		GPU:         cfg.GPU(),
+		Storage:     cfg.StorageSize(),
+		SSHPort:     cfg.SSHPort,
+		HTTPPort:    cfg.HTTPPort,
+	}
+	if cfg.HTTPPort != 0 && cfg.HostName != "" {
+		env.URL = fmt.Sprintf("https://%s.%s/", cfg.InstanceName(), cfg.HostName)
+	}
+	return env
+}
+
+// Filename returns the name of the page generated for format.
+func Filename(format string) string {
+	if format == FormatMarkdown {
+		return "index.md"
+	}
+	return "index.html"
+}
+
+// Render writes site as a page in format.
+func Render(site *Site, format string, w io.Writer) error {
+	switch format {
+	case FormatHTML:
+		tmpl, err := htmltemplate.New("index.html.tmpl").ParseFS(templates, "templates/index.html.tmpl")
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(w, site)
+	case FormatMarkdown:
+		tmpl, err := template.New("index.md.tmpl").Funcs(template.FuncMap{"cell": markdownCell}).ParseFS(templates, "templates/index.md.tmpl")
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(w, site)
+	default:
+		return fmt.Errorf("unsupported format %q (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// markdownCell escapes a value for a Markdown table cell.
+func markdownCell(value any) string {
+	s := fmt.Sprint(value)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,79 @@
+package docs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAISECRET alice@example.com"
+
+// writeFixture creates a config directory with a grouped developer and
+// environment, an ungrouped developer and an invalid developer.
+func writeFixture(t *testing.T) string {
+	t.Helper()
+	configDir := t.TempDir()
+	files := map[string]string{
+		"devenv.yaml": "hostName: dev.example.com\nresources:\n  cpu: 2\n  memory: 8Gi\n",
+		"alice/devenv-config.yaml": "name: alice\nsshPort: 30001\nhttpPort: 8888\nsshPublicKey: \"" + testKey + "\"\n" +
+			"env:\n  API_TOKEN: hunter2\nextraValues:\n  team: research\n",
+		"alice/environments/gpu.yaml": "sshPort: 30002\nresources:\n  gpu: 1\n",
+		"bob/devenv-config.yaml":      "name: bob\nsshPort: 30003\nsshPublicKey: \"" + testKey + "\"\n",
+		"carol/devenv-config.yaml":    "name: carol\nsshPort: 10\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(configDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return configDir
+}
+
+func TestCollect_GroupsByExtraValue(t *testing.T) {
+	site, err := Collect(writeFixture(t), "team")
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, site.Developers)
+	assert.Equal(t, 1, site.Failed)
+	require.Len(t, site.Groups, 2)
+
+	research := site.Groups[0]
+	assert.Equal(t, "research", research.Name)
+	require.Len(t, research.Environments, 2)
+	assert.Equal(t, "alice", research.Environments[0].Instance)
+	assert.Equal(t, "https://alice.dev.example.com/", research.Environments[0].URL)
+	assert.Equal(t, "alice-gpu", research.Environments[1].Instance)
+	assert.Equal(t, 30002, research.Environments[1].SSHPort)
+	assert.Equal(t, 1, research.Environments[1].GPU)
+
+	ungrouped := site.Groups[1]
+	assert.Equal(t, Ungrouped, ungrouped.Name)
+	require.Len(t, ungrouped.Environments, 2)
+	assert.Equal(t, "bob", ungrouped.Environments[0].Instance)
+	assert.Equal(t, "carol", ungrouped.Environments[1].Instance)
+	assert.NotEmpty(t, ungrouped.Environments[1].Error)
+}
+
+func TestRender_OmitsSecrets(t *testing.T) {
+	site, err := Collect(writeFixture(t), "")
+	require.NoError(t, err)
+	require.Len(t, site.Groups, 1)
+
+	for _, format := range Formats {
+		var out bytes.Buffer
+		require.NoError(t, Render(site, format, &out), format)
+		page := out.String()
+		assert.Contains(t, page, "alice-gpu", format)
+		assert.Contains(t, page, "https://alice.dev.example.com/", format)
+		assert.Contains(t, page, "invalid config", format)
+		assert.NotContains(t, page, "SECRET", format)
+		assert.NotContains(t, page, "hunter2", format)
+		assert.NotContains(t, page, "sshPort", format, "load errors are not published")
+	}
+
+	assert.Error(t, Render(site, "pdf", &bytes.Buffer{}))
+}
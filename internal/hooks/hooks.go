@@ -0,0 +1,59 @@
+// Package hooks runs the commands configured under hooks in devenv.yaml
+// around generation. Each command runs with sh -c and receives the run
+// context as DEVENV_* environment variables.
+package hooks
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Hook names, as used under hooks in devenv.yaml and in DEVENV_HOOK.
+const (
+	PreGenerate  = "preGenerate"
+	PostGenerate = "postGenerate"
+)
+
+// Context describes the generate run a hook is called for.
+type Context struct {
+	Hook        string   // PreGenerate or PostGenerate
+	ConfigDir   string   // --config-dir
+	OutputDir   string   // --output, empty when writing an archive
+	Archive     string   // --archive, empty when writing to the output directory
+	Environment string   // --env, empty for default environments
+	Developers  []string // Developers being generated
+	Failed      []string // Developers that failed to generate (postGenerate only)
+}
+
+// Environ returns the DEVENV_* variables describing c. Lists are space
+// separated; developer names are DNS labels, so they never contain spaces.
+func (c Context) Environ() []string {
+	return []string{
+		"DEVENV_HOOK=" + c.Hook,
+		"DEVENV_CONFIG_DIR=" + c.ConfigDir,
+		"DEVENV_OUTPUT_DIR=" + c.OutputDir,
+		"DEVENV_ARCHIVE=" + c.Archive,
+		"DEVENV_ENVIRONMENT=" + c.Environment,
+		"DEVENV_DEVELOPERS=" + strings.Join(c.Developers, " "),
+		"DEVENV_FAILED_DEVELOPERS=" + strings.Join(c.Failed, " "),
+	}
+}
+
+// Run runs commands in order with sh -c, on top of the current environment
+// plus c.Environ(). It stops at the first command that fails.
+func Run(commands []string, c Context, stdout, stderr io.Writer) error {
+	env := append(os.Environ(), c.Environ()...)
+	for i, command := range commands {
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Env = env
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook %d (%s) failed: %w", c.Hook, i+1, command, err)
+		}
+	}
+	return nil
+}
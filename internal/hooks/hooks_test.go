@@ -0,0 +1,37 @@
+package hooks
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_PassesContext(t *testing.T) {
+	var stdout bytes.Buffer
+	c := Context{
+		Hook:       PostGenerate,
+		ConfigDir:  "./developers",
+		OutputDir:  "./build",
+		Developers: []string{"alice", "bob"},
+		Failed:     []string{"bob"},
+	}
+
+	err := Run([]string{
+		`echo "$DEVENV_HOOK $DEVENV_OUTPUT_DIR"`,
+		`for d in $DEVENV_DEVELOPERS; do echo "dev $d"; done`,
+		`echo "failed $DEVENV_FAILED_DEVELOPERS"`,
+	}, c, &stdout, &stdout)
+	require.NoError(t, err)
+	assert.Equal(t, "postGenerate ./build\ndev alice\ndev bob\nfailed bob\n", stdout.String())
+}
+
+func TestRun_StopsAtFirstFailure(t *testing.T) {
+	var stdout bytes.Buffer
+
+	err := Run([]string{"echo one", "exit 3", "echo three"}, Context{Hook: PreGenerate}, &stdout, &stdout)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "preGenerate hook 2 (exit 3) failed")
+	assert.Equal(t, "one\n", stdout.String())
+}
@@ -0,0 +1,59 @@
+// Package labels defines the Kubernetes label schema applied to generated
+// developer environment resources. Templates get the keys through the
+// appLabel, componentLabel, serviceLabel and developerLabel functions, so
+// the schema can change in one place (e.g., to a prefixed key such as
+// "devenv.io/developer").
+package labels
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Label keys used on generated resources.
+const (
+	App       = "app"       // Groups all resources of one environment; used by selectors
+	Component = "component" // Kind of workload (e.g., "devenv")
+	Service   = "service"   // Role of a Service ("governing", "ssh", "http")
+	Developer = "developer" // Owning developer, sanitized with Value
+)
+
+// maxValueLength is the Kubernetes limit for label values.
+const maxValueLength = 63
+
+// valueHashLength is the number of hex characters appended to truncated values.
+const valueHashLength = 8
+
+// Value converts s into a valid Kubernetes label value: characters other
+// than alphanumerics, '-', '_' and '.' are replaced with '-', leading and
+// trailing non-alphanumerics are trimmed, and values longer than 63
+// characters are truncated with a short hash suffix so distinct inputs stay
+// distinct.
+func Value(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case isAlphanumeric(r), r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	value := trimNonAlphanumeric(b.String())
+
+	if len(value) > maxValueLength {
+		sum := sha256.Sum256([]byte(value))
+		suffix := hex.EncodeToString(sum[:])[:valueHashLength]
+		value = trimNonAlphanumeric(value[:maxValueLength-valueHashLength-1]) + "-" + suffix
+	}
+	return value
+}
+
+func isAlphanumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+func trimNonAlphanumeric(s string) string {
+	return strings.TrimFunc(s, func(r rune) bool { return !isAlphanumeric(r) })
+}
@@ -0,0 +1,31 @@
+package labels
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValue(t *testing.T) {
+	cases := map[string]string{
+		"alice":           "alice",
+		"Alice.Smith":     "Alice.Smith",
+		"bob_dev-2":       "bob_dev-2",
+		"john doe":        "john-doe",
+		"-leading+trail-": "leading-trail",
+		"":                "",
+	}
+	for input, want := range cases {
+		assert.Equal(t, want, Value(input), input)
+	}
+}
+
+func TestValue_Long(t *testing.T) {
+	long := strings.Repeat("a", 70)
+	value := Value(long)
+	assert.Len(t, value, maxValueLength)
+	assert.True(t, strings.HasPrefix(value, strings.Repeat("a", 54)+"-"))
+	assert.Equal(t, value, Value(long), "truncation must be deterministic")
+	assert.NotEqual(t, value, Value(long+"b"))
+}
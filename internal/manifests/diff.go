@@ -0,0 +1,132 @@
+package manifests
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// diffOp is one line of an edit script.
+type diffOp struct {
+	kind   byte // ' ' (unchanged), '-' (removed) or '+' (added)
+	line   string
+	oldPos int // Old lines before this op
+	newPos int // New lines before this op
+}
+
+// UnifiedDiff returns a unified diff from oldContent to newContent, labeled
+// with oldName and newName, or "" when the contents are equal. Manifests are
+// small, so a quadratic longest-common-subsequence diff is fast enough.
+func UnifiedDiff(oldName, newName string, oldContent, newContent []byte) string {
+	if string(oldContent) == string(newContent) {
+		return ""
+	}
+	ops := diffLines(splitLines(string(oldContent)), splitLines(string(newContent)))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+	for start := 0; start < len(ops); {
+		// Find the next change and extend the hunk while changes are close
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		last := first
+		for next := first + 1; next < len(ops); next++ {
+			if ops[next].kind == ' ' {
+				continue
+			}
+			if next-last-1 > 2*diffContext { // Unchanged lines between the changes
+				break
+			}
+			last = next
+		}
+
+		from := max(first-diffContext, start)
+		to := min(last+diffContext+1, len(ops))
+		writeHunk(&out, ops[from:to])
+		start = to
+	}
+	return out.String()
+}
+
+// splitLines splits content into lines without their newlines.
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// diffLines returns the edit script turning oldLines into newLines.
+func diffLines(oldLines, newLines []string) []diffOp {
+	// common[i][j] is the length of the longest common subsequence of
+	// oldLines[i:] and newLines[j:]
+	common := make([][]int, len(oldLines)+1)
+	for i := range common {
+		common[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			ops = append(ops, diffOp{' ', oldLines[i], i, j})
+			i++
+			j++
+		case j == len(newLines) || (i < len(oldLines) && common[i+1][j] >= common[i][j+1]):
+			ops = append(ops, diffOp{'-', oldLines[i], i, j})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', newLines[j], i, j})
+			j++
+		}
+	}
+	return ops
+}
+
+// writeHunk writes ops as one hunk with its "@@ -a,b +c,d @@" header.
+func writeHunk(out *strings.Builder, ops []diffOp) {
+	var oldCount, newCount int
+	for _, op := range ops {
+		if op.kind != '+' {
+			oldCount++
+		}
+		if op.kind != '-' {
+			newCount++
+		}
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n",
+		hunkRange(ops[0].oldPos, oldCount), hunkRange(ops[0].newPos, newCount))
+	for _, op := range ops {
+		fmt.Fprintf(out, "%c%s\n", op.kind, op.line)
+	}
+}
+
+// hunkRange formats the line range of a hunk side that starts after pos
+// lines. An empty range refers to the line before it, as in GNU diff.
+func hunkRange(pos, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", pos)
+	case 1:
+		return fmt.Sprintf("%d", pos+1)
+	default:
+		return fmt.Sprintf("%d,%d", pos+1, count)
+	}
+}
@@ -0,0 +1,50 @@
+package manifests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	old := "kind: Service\nmetadata:\n  name: a\nspec:\n  type: NodePort\n"
+	changed := "kind: Service\nmetadata:\n  name: b\nspec:\n  type: NodePort\n"
+
+	assert.Empty(t, UnifiedDiff("a/x", "b/x", []byte(old), []byte(old)))
+	assert.Equal(t, `--- a/service.yaml
++++ b/service.yaml
+@@ -1,5 +1,5 @@
+ kind: Service
+ metadata:
+-  name: a
++  name: b
+ spec:
+   type: NodePort
+`, UnifiedDiff("a/service.yaml", "b/service.yaml", []byte(old), []byte(changed)))
+}
+
+func TestUnifiedDiff_NewFile(t *testing.T) {
+	assert.Equal(t, `--- /dev/null
++++ b/namespace.yaml
+@@ -0,0 +1,2 @@
++kind: Namespace
++name: devenv
+`, UnifiedDiff("/dev/null", "b/namespace.yaml", nil, []byte("kind: Namespace\nname: devenv\n")))
+}
+
+func TestUnifiedDiff_SeparateHunks(t *testing.T) {
+	var oldLines []string
+	for i := 1; i <= 20; i++ {
+		oldLines = append(oldLines, "line")
+	}
+	oldLines[0], oldLines[19] = "first", "last"
+	newLines := append([]string(nil), oldLines...)
+	newLines[0], newLines[19] = "FIRST", "LAST"
+
+	diff := UnifiedDiff("a", "b",
+		[]byte(strings.Join(oldLines, "\n")+"\n"), []byte(strings.Join(newLines, "\n")+"\n"))
+	assert.Contains(t, diff, "@@ -1,4 +1,4 @@\n-first\n+FIRST\n line\n")
+	assert.Contains(t, diff, "@@ -17,4 +17,4 @@\n line\n line\n line\n-last\n+LAST\n")
+	assert.Equal(t, 2, strings.Count(diff, "@@ -"))
+}
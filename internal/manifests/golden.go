@@ -0,0 +1,108 @@
+package manifests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadGolden reads the committed manifests (*.yaml files) directly inside
+// dir, keyed by filename. A missing directory yields no files.
+func ReadGolden(dir string) (map[string][]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files[entry.Name()] = content
+	}
+	return files, nil
+}
+
+// WriteGolden replaces the *.yaml files directly inside dir with files,
+// removing golden files that are no longer rendered.
+func WriteGolden(dir string, files map[string][]byte) error {
+	existing, err := ReadGolden(dir)
+	if err != nil {
+		return err
+	}
+	for name := range existing {
+		if _, ok := files[name]; !ok {
+			if err := os.Remove(filepath.Join(dir, name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create golden directory %s: %w", dir, err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			return fmt.Errorf("failed to write golden file: %w", err)
+		}
+	}
+	return nil
+}
+
+// DiffGolden compares rendered files against golden files and describes
+// each difference, sorted by filename. It returns nil when they match.
+func DiffGolden(golden, rendered map[string][]byte) []string {
+	names := make(map[string]bool, len(rendered))
+	for name := range golden {
+		names[name] = true
+	}
+	for name := range rendered {
+		names[name] = true
+	}
+
+	var diffs []string
+	for _, name := range sortedKeys(names) {
+		want, inGolden := golden[name]
+		got, inRendered := rendered[name]
+		switch {
+		case !inGolden:
+			diffs = append(diffs, fmt.Sprintf("%s is rendered but has no golden file", name))
+		case !inRendered:
+			diffs = append(diffs, fmt.Sprintf("%s has a golden file but is no longer rendered", name))
+		case !bytes.Equal(want, got):
+			diffs = append(diffs, fmt.Sprintf("%s differs from golden %s", name, firstDifference(want, got)))
+		}
+	}
+	return diffs
+}
+
+// firstDifference describes the first line at which got departs from want,
+// which must differ.
+func firstDifference(want, got []byte) string {
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+	line := 0
+	for line < len(wantLines) && line < len(gotLines) && wantLines[line] == gotLines[line] {
+		line++
+	}
+	var wantLine, gotLine string
+	if line < len(wantLines) {
+		wantLine = wantLines[line]
+	}
+	if line < len(gotLines) {
+		gotLine = gotLines[line]
+	}
+	return fmt.Sprintf("at line %d: want %q, got %q", line+1, wantLine, gotLine)
+}
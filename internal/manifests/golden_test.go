@@ -0,0 +1,54 @@
+package manifests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGolden_RoundTrip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "alice")
+
+	files, err := ReadGolden(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files, "a missing golden directory has no files")
+
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stale.yaml"), []byte("kind: Old\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("notes\n"), 0644))
+
+	rendered := map[string][]byte{
+		"service.yaml":     []byte("kind: Service\n"),
+		"statefulset.yaml": []byte("kind: StatefulSet\n"),
+	}
+	require.NoError(t, WriteGolden(dir, rendered))
+
+	files, err = ReadGolden(dir)
+	require.NoError(t, err)
+	assert.Equal(t, rendered, files)
+	assert.FileExists(t, filepath.Join(dir, "README.md"), "only manifests are replaced")
+}
+
+func TestDiffGolden(t *testing.T) {
+	golden := map[string][]byte{
+		"removed.yaml": []byte("kind: Old\n"),
+		"service.yaml": []byte("kind: Service\nmetadata:\n  name: a\n"),
+		"same.yaml":    []byte("kind: ConfigMap\n"),
+	}
+	rendered := map[string][]byte{
+		"added.yaml":   []byte("kind: New\n"),
+		"service.yaml": []byte("kind: Service\nmetadata:\n  name: b\n"),
+		"same.yaml":    []byte("kind: ConfigMap\n"),
+	}
+
+	assert.Equal(t, []string{
+		"added.yaml is rendered but has no golden file",
+		"removed.yaml has a golden file but is no longer rendered",
+		`service.yaml differs from golden at line 3: want "  name: a", got "  name: b"`,
+	}, DiffGolden(golden, rendered))
+
+	assert.Nil(t, DiffGolden(rendered, rendered))
+}
@@ -0,0 +1,29 @@
+package manifests
+
+import (
+	"bytes"
+)
+
+// documentSeparator separates the documents of a multi-document YAML file.
+const documentSeparator = "---\n"
+
+// Join concatenates rendered manifests into one multi-document YAML file,
+// starting with header (e.g., a provenance comment block). A manifest that
+// already starts with a separator does not get a second one, and manifests
+// without a trailing newline get one so separators stay on their own line.
+func Join(header []byte, manifests [][]byte) []byte {
+	var out bytes.Buffer
+	out.Write(header)
+	for i, manifest := range manifests {
+		manifest = bytes.TrimLeft(manifest, "\n")
+		manifest = bytes.TrimPrefix(manifest, []byte(documentSeparator))
+		if i > 0 {
+			out.WriteString(documentSeparator)
+		}
+		out.Write(manifest)
+		if len(manifest) > 0 && manifest[len(manifest)-1] != '\n' {
+			out.WriteByte('\n')
+		}
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,32 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoin(t *testing.T) {
+	joined := Join([]byte("# header\n"), [][]byte{
+		[]byte("kind: StatefulSet\n"),
+		[]byte("---\nkind: PersistentVolumeClaim\n---\nkind: PersistentVolumeClaim\n"),
+		[]byte("kind: Service"),
+		[]byte("# No NetworkPolicy\n"),
+	})
+	assert.Equal(t, `# header
+kind: StatefulSet
+---
+kind: PersistentVolumeClaim
+---
+kind: PersistentVolumeClaim
+---
+kind: Service
+---
+# No NetworkPolicy
+`, string(joined))
+}
+
+func TestJoin_Empty(t *testing.T) {
+	assert.Equal(t, "# header\n", string(Join([]byte("# header\n"), nil)))
+	assert.Empty(t, Join(nil, nil))
+}
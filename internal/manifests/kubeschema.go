@@ -0,0 +1,618 @@
+package manifests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fieldSchema describes a field of a Kubernetes object, following the
+// types of the Kubernetes OpenAPI schema. Objects with properties are
+// closed: fields that Kubernetes does not know are reported, as kubectl's
+// strict field validation would. Objects without properties accept any
+// fields, which keeps rarely templated parts of the API (e.g., affinity)
+// out of the embedded schemas.
+type fieldSchema struct {
+	kind       fieldKind
+	properties map[string]*fieldSchema // Fields of a closed object
+	items      *fieldSchema            // Elements of an array, values of a map
+	required   []string
+	enum       []string
+}
+
+type fieldKind int
+
+const (
+	kindObject fieldKind = iota
+	kindMap
+	kindArray
+	kindString
+	kindInteger
+	kindBoolean
+	kindIntOrString
+	kindQuantity
+)
+
+// quantityRe matches Kubernetes resource quantities (e.g., "500m", "8Gi").
+var quantityRe = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+|[numkMGTPE]|[KMGTPE]i)?$`)
+
+func objectOf(properties map[string]*fieldSchema, required ...string) *fieldSchema {
+	return &fieldSchema{kind: kindObject, properties: properties, required: required}
+}
+
+func anyObject() *fieldSchema { return &fieldSchema{kind: kindObject} }
+
+func arrayOf(items *fieldSchema) *fieldSchema { return &fieldSchema{kind: kindArray, items: items} }
+
+func mapOf(values *fieldSchema) *fieldSchema { return &fieldSchema{kind: kindMap, items: values} }
+
+func str() *fieldSchema { return &fieldSchema{kind: kindString} }
+
+func integer() *fieldSchema { return &fieldSchema{kind: kindInteger} }
+
+func boolean() *fieldSchema { return &fieldSchema{kind: kindBoolean} }
+
+func intOrString() *fieldSchema { return &fieldSchema{kind: kindIntOrString} }
+
+func quantity() *fieldSchema { return &fieldSchema{kind: kindQuantity} }
+
+func enum(values ...string) *fieldSchema { return &fieldSchema{kind: kindString, enum: values} }
+
+// Schemas of the objects devenv templates generate, a subset of the
+// Kubernetes OpenAPI schema (v1.30).
+var (
+	objectMeta = objectOf(map[string]*fieldSchema{
+		"name":            str(),
+		"generateName":    str(),
+		"namespace":       str(),
+		"labels":          mapOf(str()),
+		"annotations":     mapOf(str()),
+		"finalizers":      arrayOf(str()),
+		"ownerReferences": arrayOf(anyObject()),
+	})
+
+	protocol = enum("TCP", "UDP", "SCTP")
+
+	resourceRequirements = objectOf(map[string]*fieldSchema{
+		"limits":   mapOf(quantity()),
+		"requests": mapOf(quantity()),
+		"claims":   arrayOf(anyObject()),
+	})
+
+	localObjectReference = objectOf(map[string]*fieldSchema{
+		"name":     str(),
+		"optional": boolean(),
+	})
+
+	container = objectOf(map[string]*fieldSchema{
+		"args":    arrayOf(str()),
+		"command": arrayOf(str()),
+		"env": arrayOf(objectOf(map[string]*fieldSchema{
+			"name":      str(),
+			"value":     str(),
+			"valueFrom": anyObject(),
+		}, "name")),
+		"envFrom": arrayOf(objectOf(map[string]*fieldSchema{
+			"prefix":       str(),
+			"configMapRef": localObjectReference,
+			"secretRef":    localObjectReference,
+		})),
+		"image":           str(),
+		"imagePullPolicy": enum("Always", "Never", "IfNotPresent"),
+		"lifecycle":       anyObject(),
+		"livenessProbe":   anyObject(),
+		"name":            str(),
+		"ports": arrayOf(objectOf(map[string]*fieldSchema{
+			"containerPort": integer(),
+			"hostIP":        str(),
+			"hostPort":      integer(),
+			"name":          str(),
+			"protocol":      protocol,
+		}, "containerPort")),
+		"readinessProbe":           anyObject(),
+		"resizePolicy":             arrayOf(anyObject()),
+		"resources":                resourceRequirements,
+		"restartPolicy":            str(),
+		"securityContext":          anyObject(),
+		"startupProbe":             anyObject(),
+		"stdin":                    boolean(),
+		"stdinOnce":                boolean(),
+		"terminationMessagePath":   str(),
+		"terminationMessagePolicy": enum("File", "FallbackToLogsOnError"),
+		"tty":                      boolean(),
+		"volumeDevices":            arrayOf(anyObject()),
+		"volumeMounts": arrayOf(objectOf(map[string]*fieldSchema{
+			"mountPath":         str(),
+			"mountPropagation":  enum("None", "HostToContainer", "Bidirectional"),
+			"name":              str(),
+			"readOnly":          boolean(),
+			"recursiveReadOnly": str(),
+			"subPath":           str(),
+			"subPathExpr":       str(),
+		}, "name", "mountPath")),
+		"workingDir": str(),
+	}, "name")
+
+	volume = objectOf(map[string]*fieldSchema{
+		"name": str(),
+		"configMap": objectOf(map[string]*fieldSchema{
+			"name":        str(),
+			"items":       arrayOf(anyObject()),
+			"defaultMode": integer(),
+			"optional":    boolean(),
+		}),
+		"emptyDir": anyObject(),
+		"hostPath": objectOf(map[string]*fieldSchema{
+			"path": str(),
+			"type": enum("", "DirectoryOrCreate", "Directory", "FileOrCreate", "File", "Socket", "CharDevice", "BlockDevice"),
+		}, "path"),
+		"persistentVolumeClaim": objectOf(map[string]*fieldSchema{
+			"claimName": str(),
+			"readOnly":  boolean(),
+		}, "claimName"),
+		"secret": objectOf(map[string]*fieldSchema{
+			"secretName":  str(),
+			"items":       arrayOf(anyObject()),
+			"defaultMode": integer(),
+			"optional":    boolean(),
+		}),
+		"awsElasticBlockStore": anyObject(),
+		"azureDisk":            anyObject(),
+		"azureFile":            anyObject(),
+		"cephfs":               anyObject(),
+		"cinder":               anyObject(),
+		"csi":                  anyObject(),
+		"downwardAPI":          anyObject(),
+		"ephemeral":            anyObject(),
+		"fc":                   anyObject(),
+		"flexVolume":           anyObject(),
+		"flocker":              anyObject(),
+		"gcePersistentDisk":    anyObject(),
+		"gitRepo":              anyObject(),
+		"glusterfs":            anyObject(),
+		"image":                anyObject(),
+		"iscsi":                anyObject(),
+		"nfs":                  anyObject(),
+		"photonPersistentDisk": anyObject(),
+		"portworxVolume":       anyObject(),
+		"projected":            anyObject(),
+		"quobyte":              anyObject(),
+		"rbd":                  anyObject(),
+		"scaleIO":              anyObject(),
+		"storageos":            anyObject(),
+		"vsphereVolume":        anyObject(),
+	}, "name")
+
+	podSpec = objectOf(map[string]*fieldSchema{
+		"activeDeadlineSeconds":         integer(),
+		"affinity":                      anyObject(),
+		"automountServiceAccountToken":  boolean(),
+		"containers":                    arrayOf(container),
+		"dnsConfig":                     anyObject(),
+		"dnsPolicy":                     enum("ClusterFirstWithHostNet", "ClusterFirst", "Default", "None"),
+		"enableServiceLinks":            boolean(),
+		"ephemeralContainers":           arrayOf(anyObject()),
+		"hostAliases":                   arrayOf(anyObject()),
+		"hostIPC":                       boolean(),
+		"hostNetwork":                   boolean(),
+		"hostPID":                       boolean(),
+		"hostUsers":                     boolean(),
+		"hostname":                      str(),
+		"imagePullSecrets":              arrayOf(localObjectReference),
+		"initContainers":                arrayOf(container),
+		"nodeName":                      str(),
+		"nodeSelector":                  mapOf(str()),
+		"os":                            anyObject(),
+		"overhead":                      mapOf(quantity()),
+		"preemptionPolicy":              enum("Never", "PreemptLowerPriority"),
+		"priority":                      integer(),
+		"priorityClassName":             str(),
+		"readinessGates":                arrayOf(anyObject()),
+		"resourceClaims":                arrayOf(anyObject()),
+		"resources":                     resourceRequirements,
+		"restartPolicy":                 enum("Always", "OnFailure", "Never"),
+		"runtimeClassName":              str(),
+		"schedulerName":                 str(),
+		"schedulingGates":               arrayOf(anyObject()),
+		"securityContext":               anyObject(),
+		"serviceAccount":                str(),
+		"serviceAccountName":            str(),
+		"setHostnameAsFQDN":             boolean(),
+		"shareProcessNamespace":         boolean(),
+		"subdomain":                     str(),
+		"terminationGracePeriodSeconds": integer(),
+		"tolerations": arrayOf(objectOf(map[string]*fieldSchema{
+			"effect":            enum("NoSchedule", "PreferNoSchedule", "NoExecute"),
+			"key":               str(),
+			"operator":          enum("Exists", "Equal"),
+			"tolerationSeconds": integer(),
+			"value":             str(),
+		})),
+		"topologySpreadConstraints": arrayOf(anyObject()),
+		"volumes":                   arrayOf(volume),
+	}, "containers")
+
+	networkPolicyPorts = arrayOf(objectOf(map[string]*fieldSchema{
+		"endPort":  integer(),
+		"port":     intOrString(),
+		"protocol": protocol,
+	}))
+
+	// Scrape endpoint of a Prometheus Operator ServiceMonitor or PodMonitor
+	// (monitoring.coreos.com/v1, operator v0.75)
+	monitorEndpoint = objectOf(map[string]*fieldSchema{
+		"authorization":     anyObject(),
+		"basicAuth":         anyObject(),
+		"bearerTokenSecret": anyObject(),
+		"enableHttp2":       boolean(),
+		"filterRunning":     boolean(),
+		"followRedirects":   boolean(),
+		"honorLabels":       boolean(),
+		"honorTimestamps":   boolean(),
+		"interval":          str(),
+		"metricRelabelings": arrayOf(anyObject()),
+		"oauth2":            anyObject(),
+		"params":            mapOf(arrayOf(str())),
+		"path":              str(),
+		"port":              str(),
+		"proxyUrl":          str(),
+		"relabelings":       arrayOf(anyObject()),
+		"scheme":            enum("http", "https"),
+		"scrapeTimeout":     str(),
+		"targetPort":        intOrString(),
+		"tlsConfig":         anyObject(),
+	})
+
+	policyRule = objectOf(map[string]*fieldSchema{
+		"apiGroups":       arrayOf(str()),
+		"nonResourceURLs": arrayOf(str()),
+		"resourceNames":   arrayOf(str()),
+		"resources":       arrayOf(str()),
+		"verbs":           arrayOf(str()),
+	}, "verbs")
+)
+
+// kindSchemas maps apiVersion and kind to the schema of the whole object.
+// Objects of other kinds (e.g., extra manifests) are not checked.
+var kindSchemas = map[string]*fieldSchema{
+	"v1/ConfigMap": topLevel(map[string]*fieldSchema{
+		"data":       mapOf(str()),
+		"binaryData": mapOf(str()),
+		"immutable":  boolean(),
+	}),
+	"v1/Namespace": topLevel(map[string]*fieldSchema{
+		"spec": anyObject(),
+	}),
+	"v1/PersistentVolumeClaim": topLevel(map[string]*fieldSchema{
+		"spec": objectOf(map[string]*fieldSchema{
+			"accessModes":               arrayOf(enum("ReadWriteOnce", "ReadOnlyMany", "ReadWriteMany", "ReadWriteOncePod")),
+			"dataSource":                anyObject(),
+			"dataSourceRef":             anyObject(),
+			"resources":                 resourceRequirements,
+			"selector":                  anyObject(),
+			"storageClassName":          str(),
+			"volumeAttributesClassName": str(),
+			"volumeMode":                enum("Filesystem", "Block"),
+			"volumeName":                str(),
+		}),
+	}),
+	"v1/ServiceAccount": topLevel(map[string]*fieldSchema{
+		"automountServiceAccountToken": boolean(),
+		"imagePullSecrets":             arrayOf(anyObject()),
+		"secrets":                      arrayOf(anyObject()),
+	}),
+	"v1/Service": topLevel(map[string]*fieldSchema{
+		"spec": objectOf(map[string]*fieldSchema{
+			"allocateLoadBalancerNodePorts": boolean(),
+			"clusterIP":                     str(),
+			"clusterIPs":                    arrayOf(str()),
+			"externalIPs":                   arrayOf(str()),
+			"externalName":                  str(),
+			"externalTrafficPolicy":         enum("Cluster", "Local"),
+			"healthCheckNodePort":           integer(),
+			"internalTrafficPolicy":         enum("Cluster", "Local"),
+			"ipFamilies":                    arrayOf(enum("IPv4", "IPv6")),
+			"ipFamilyPolicy":                enum("SingleStack", "PreferDualStack", "RequireDualStack"),
+			"loadBalancerClass":             str(),
+			"loadBalancerIP":                str(),
+			"loadBalancerSourceRanges":      arrayOf(str()),
+			"ports": arrayOf(objectOf(map[string]*fieldSchema{
+				"appProtocol": str(),
+				"name":        str(),
+				"nodePort":    integer(),
+				"port":        integer(),
+				"protocol":    protocol,
+				"targetPort":  intOrString(),
+			}, "port")),
+			"publishNotReadyAddresses": boolean(),
+			"selector":                 mapOf(str()),
+			"sessionAffinity":          enum("ClientIP", "None"),
+			"sessionAffinityConfig":    anyObject(),
+			"trafficDistribution":      str(),
+			"type":                     enum("ClusterIP", "NodePort", "LoadBalancer", "ExternalName"),
+		}),
+	}),
+	"apps/v1/StatefulSet": topLevel(map[string]*fieldSchema{
+		"spec": objectOf(map[string]*fieldSchema{
+			"minReadySeconds":                      integer(),
+			"ordinals":                             anyObject(),
+			"persistentVolumeClaimRetentionPolicy": anyObject(),
+			"podManagementPolicy":                  enum("OrderedReady", "Parallel"),
+			"replicas":                             integer(),
+			"revisionHistoryLimit":                 integer(),
+			"selector":                             anyObject(),
+			"serviceName":                          str(),
+			"template": objectOf(map[string]*fieldSchema{
+				"metadata": objectMeta,
+				"spec":     podSpec,
+			}),
+			"updateStrategy":       anyObject(),
+			"volumeClaimTemplates": arrayOf(anyObject()),
+		}, "selector", "template"),
+	}),
+	"monitoring.coreos.com/v1/PodMonitor": topLevel(map[string]*fieldSchema{
+		"spec": objectOf(map[string]*fieldSchema{
+			"jobLabel":            str(),
+			"namespaceSelector":   anyObject(),
+			"podMetricsEndpoints": arrayOf(monitorEndpoint),
+			"podTargetLabels":     arrayOf(str()),
+			"sampleLimit":         integer(),
+			"selector":            anyObject(),
+		}, "selector"),
+	}),
+	"monitoring.coreos.com/v1/ServiceMonitor": topLevel(map[string]*fieldSchema{
+		"spec": objectOf(map[string]*fieldSchema{
+			"endpoints":         arrayOf(monitorEndpoint),
+			"jobLabel":          str(),
+			"namespaceSelector": anyObject(),
+			"podTargetLabels":   arrayOf(str()),
+			"sampleLimit":       integer(),
+			"selector":          anyObject(),
+			"targetLabels":      arrayOf(str()),
+		}, "selector"),
+	}),
+	"rbac.authorization.k8s.io/v1/Role": topLevel(map[string]*fieldSchema{
+		"rules": arrayOf(policyRule),
+	}),
+	"rbac.authorization.k8s.io/v1/RoleBinding": topLevel(map[string]*fieldSchema{
+		"roleRef": objectOf(map[string]*fieldSchema{
+			"apiGroup": str(),
+			"kind":     enum("Role", "ClusterRole"),
+			"name":     str(),
+		}, "apiGroup", "kind", "name"),
+		"subjects": arrayOf(objectOf(map[string]*fieldSchema{
+			"apiGroup":  str(),
+			"kind":      enum("ServiceAccount", "User", "Group"),
+			"name":      str(),
+			"namespace": str(),
+		}, "kind", "name")),
+	}),
+	"networking.k8s.io/v1/Ingress": topLevel(map[string]*fieldSchema{
+		"spec": objectOf(map[string]*fieldSchema{
+			"defaultBackend":   anyObject(),
+			"ingressClassName": str(),
+			"rules": arrayOf(objectOf(map[string]*fieldSchema{
+				"host": str(),
+				"http": objectOf(map[string]*fieldSchema{
+					"paths": arrayOf(objectOf(map[string]*fieldSchema{
+						"backend": objectOf(map[string]*fieldSchema{
+							"resource": anyObject(),
+							"service": objectOf(map[string]*fieldSchema{
+								"name": str(),
+								"port": objectOf(map[string]*fieldSchema{
+									"name":   str(),
+									"number": integer(),
+								}),
+							}, "name"),
+						}),
+						"path":     str(),
+						"pathType": enum("Exact", "Prefix", "ImplementationSpecific"),
+					}, "backend", "pathType")),
+				}, "paths"),
+			})),
+			"tls": arrayOf(objectOf(map[string]*fieldSchema{
+				"hosts":      arrayOf(str()),
+				"secretName": str(),
+			})),
+		}),
+	}),
+	"networking.k8s.io/v1/NetworkPolicy": topLevel(map[string]*fieldSchema{
+		"spec": objectOf(map[string]*fieldSchema{
+			"egress": arrayOf(objectOf(map[string]*fieldSchema{
+				"ports": networkPolicyPorts,
+				"to":    arrayOf(anyObject()),
+			})),
+			"ingress": arrayOf(objectOf(map[string]*fieldSchema{
+				"from":  arrayOf(anyObject()),
+				"ports": networkPolicyPorts,
+			})),
+			"podSelector": anyObject(),
+			"policyTypes": arrayOf(enum("Ingress", "Egress")),
+		}, "podSelector"),
+	}),
+}
+
+// topLevel returns the schema of an object with the given fields besides
+// apiVersion, kind and metadata.
+func topLevel(fields map[string]*fieldSchema) *fieldSchema {
+	properties := map[string]*fieldSchema{
+		"apiVersion": str(),
+		"kind":       str(),
+		"metadata":   objectMeta,
+	}
+	for name, field := range fields {
+		properties[name] = field
+	}
+	return objectOf(properties, "metadata")
+}
+
+// CheckSchema validates the objects in a rendered manifest file against
+// the Kubernetes schemas of the kinds devenv generates and returns one
+// error per problem, with the line it was found on (e.g.,
+// "statefulset.yaml:42: spec.template.spec.containers[0].ports[0].containerPort:
+// must be an integer, got string"). Objects of other kinds and documents
+// that are not valid YAML are skipped; Check reports those.
+func CheckSchema(filename string, content []byte) []error {
+	var problems []error
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) || err != nil {
+			break
+		}
+		if len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+		document := node.Content[0]
+		schema, ok := kindSchemas[typeKey(document)]
+		if !ok {
+			continue
+		}
+		for _, problem := range checkNode(document, schema, "") {
+			problems = append(problems, fmt.Errorf("%s:%d: %s", filename, problem.line, problem.message))
+		}
+	}
+	return problems
+}
+
+// typeKey returns "<apiVersion>/<kind>" of a decoded object.
+func typeKey(document *yaml.Node) string {
+	var apiVersion, kind string
+	for i := 0; i+1 < len(document.Content); i += 2 {
+		switch document.Content[i].Value {
+		case "apiVersion":
+			apiVersion = document.Content[i+1].Value
+		case "kind":
+			kind = document.Content[i+1].Value
+		}
+	}
+	return apiVersion + "/" + kind
+}
+
+// schemaProblem is a schema violation at a line of the manifest.
+type schemaProblem struct {
+	line    int
+	message string
+}
+
+// checkNode validates node against schema; path is the field path of node
+// (e.g., "spec.ports[0]").
+func checkNode(node *yaml.Node, schema *fieldSchema, path string) []schemaProblem {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil // Kubernetes treats null as unset
+	}
+	problem := func(format string, args ...any) []schemaProblem {
+		return []schemaProblem{{line: node.Line, message: path + ": " + fmt.Sprintf(format, args...)}}
+	}
+
+	switch schema.kind {
+	case kindObject, kindMap:
+		if node.Kind != yaml.MappingNode {
+			return problem("must be an object, got %s", describe(node))
+		}
+		var problems []schemaProblem
+		present := make(map[string]bool)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			present[key.Value] = true
+			fieldPath := joinPath(path, key.Value)
+			if schema.kind == kindMap {
+				problems = append(problems, checkNode(value, schema.items, fieldPath)...)
+				continue
+			}
+			if schema.properties == nil {
+				continue
+			}
+			field, ok := schema.properties[key.Value]
+			if !ok {
+				problems = append(problems, schemaProblem{line: key.Line, message: fieldPath + ": unknown field"})
+				continue
+			}
+			problems = append(problems, checkNode(value, field, fieldPath)...)
+		}
+		for _, name := range schema.required {
+			if !present[name] {
+				problems = append(problems, schemaProblem{line: node.Line, message: joinPath(path, name) + ": required field is missing"})
+			}
+		}
+		return problems
+	case kindArray:
+		if node.Kind != yaml.SequenceNode {
+			return problem("must be a list, got %s", describe(node))
+		}
+		var problems []schemaProblem
+		for i, item := range node.Content {
+			problems = append(problems, checkNode(item, schema.items, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return problems
+	case kindString:
+		if node.Kind != yaml.ScalarNode {
+			return problem("must be a string, got %s", describe(node))
+		}
+		if node.Tag != "!!str" {
+			return problem("must be a string, got %s; quote the value", describe(node))
+		}
+		if schema.enum != nil && !slices.Contains(schema.enum, node.Value) {
+			return problem("must be one of %s, got %q", strings.Join(schema.enum, ", "), node.Value)
+		}
+	case kindInteger:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			return problem("must be an integer, got %s", describe(node))
+		}
+	case kindBoolean:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			return problem("must be true or false, got %s", describe(node))
+		}
+	case kindIntOrString:
+		if node.Kind != yaml.ScalarNode || (node.Tag != "!!int" && node.Tag != "!!str") {
+			return problem("must be an integer or a string, got %s", describe(node))
+		}
+	case kindQuantity:
+		if node.Kind != yaml.ScalarNode || (node.Tag != "!!int" && node.Tag != "!!float" && node.Tag != "!!str") {
+			return problem("must be a quantity, got %s", describe(node))
+		}
+		if !quantityRe.MatchString(node.Value) {
+			return problem("must be a quantity (e.g., 500m, 2, 8Gi), got %q", node.Value)
+		}
+	}
+	return nil
+}
+
+// joinPath appends a field to a field path.
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// describe names the YAML type of node for error messages.
+func describe(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "an object"
+	case yaml.SequenceNode:
+		return "a list"
+	}
+	switch node.Tag {
+	case "!!str":
+		return fmt.Sprintf("string %q", node.Value)
+	case "!!int":
+		return "integer " + node.Value
+	case "!!float":
+		return "number " + node.Value
+	case "!!bool":
+		return "boolean " + node.Value
+	}
+	return node.Value
+}
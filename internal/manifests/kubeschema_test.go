@@ -0,0 +1,75 @@
+package manifests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSchema_TemplateGoldens(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "templates", "testdata", "golden", "*.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Empty(t, CheckSchema(filepath.Base(file), content), "rendered manifests must match the Kubernetes schemas")
+	}
+}
+
+func TestCheckSchema_Problems(t *testing.T) {
+	content := `# header
+apiVersion: v1
+kind: Service
+metadata:
+  name: devenv-alice
+spec:
+  type: NodePort
+  ports:
+  - port: "22"
+    targetPort: 22
+    nodePort: 30001
+    protocl: TCP
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: devenv-alice
+spec:
+  selector: {}
+  template:
+    spec:
+      containers:
+      - name: alice
+        imagePullPolicy: Sometimes
+        resources:
+          limits:
+            memory: 8 GB
+        env:
+        - name: PORT
+          value: 8080
+        volumeMounts:
+        - name: home
+---
+apiVersion: example.com/v1
+kind: Backup
+spec:
+  anything: goes
+`
+	var messages []string
+	for _, err := range CheckSchema("alice.yaml", []byte(content)) {
+		messages = append(messages, err.Error())
+	}
+	assert.Equal(t, []string{
+		`alice.yaml:9: spec.ports[0].port: must be an integer, got string "22"`,
+		`alice.yaml:12: spec.ports[0].protocl: unknown field`,
+		`alice.yaml:24: spec.template.spec.containers[0].imagePullPolicy: must be one of Always, Never, IfNotPresent, got "Sometimes"`,
+		`alice.yaml:27: spec.template.spec.containers[0].resources.limits.memory: must be a quantity (e.g., 500m, 2, 8Gi), got "8 GB"`,
+		`alice.yaml:30: spec.template.spec.containers[0].env[0].value: must be a string, got integer 8080; quote the value`,
+		`alice.yaml:32: spec.template.spec.containers[0].volumeMounts[0].mountPath: required field is missing`,
+	}, messages)
+}
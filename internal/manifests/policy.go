@@ -0,0 +1,74 @@
+package manifests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ListPolicyHooks returns the executable files directly inside dir, sorted
+// by name. A missing directory yields no hooks.
+func ListPolicyHooks(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var hooks []string
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		if info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0 {
+			hooks = append(hooks, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return hooks, nil
+}
+
+// Stream joins rendered files into one multi-document YAML stream in
+// filename order, each preceded by a "# Source: <filename>" comment, which
+// is the input policy hooks receive.
+func Stream(files map[string][]byte) []byte {
+	var stream bytes.Buffer
+	for i, name := range sortedKeys(files) {
+		if i > 0 {
+			stream.WriteString("---\n")
+		}
+		fmt.Fprintf(&stream, "# Source: %s\n", name)
+		stream.Write(files[name])
+		if content := files[name]; len(content) > 0 && content[len(content)-1] != '\n' {
+			stream.WriteByte('\n')
+		}
+	}
+	return stream.Bytes()
+}
+
+// RunPolicyHook runs hook with stream on stdin and env added to the
+// environment. The hook rejects the manifests by exiting non-zero; its
+// output becomes part of the returned error.
+func RunPolicyHook(ctx context.Context, hook string, stream []byte, env []string) error {
+	cmd := exec.CommandContext(ctx, hook)
+	cmd.Stdin = bytes.NewReader(stream)
+	cmd.Env = append(os.Environ(), env...)
+
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	name := filepath.Base(hook)
+	if message := strings.TrimSpace(string(output)); message != "" {
+		return fmt.Errorf("policy %s failed: %s", name, message)
+	}
+	return fmt.Errorf("policy %s failed: %w", name, err)
+}
@@ -0,0 +1,47 @@
+package manifests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream(t *testing.T) {
+	stream := Stream(map[string][]byte{
+		"service.yaml":  []byte("kind: Service\n"),
+		"env-vars.yaml": []byte("kind: ConfigMap"),
+	})
+	assert.Equal(t, "# Source: env-vars.yaml\nkind: ConfigMap\n---\n# Source: service.yaml\nkind: Service\n", string(stream))
+}
+
+func TestPolicyHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("policy hooks are shell scripts in this test")
+	}
+
+	dir := t.TempDir()
+	hooks, err := ListPolicyHooks(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, hooks)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "10-no-latest"),
+		[]byte("#!/bin/sh\nif grep -q ':latest' ; then echo \"$DEVENV_TARGET uses a latest tag\"; exit 1; fi\n"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "20-silent"), []byte("#!/bin/sh\nexit 3\n"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a hook\n"), 0644))
+
+	hooks, err = ListPolicyHooks(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "10-no-latest"), filepath.Join(dir, "20-silent")}, hooks)
+
+	env := []string{"DEVENV_TARGET=alice"}
+	assert.NoError(t, RunPolicyHook(context.Background(), hooks[0], []byte("image: ubuntu:24.04\n"), env))
+	assert.EqualError(t, RunPolicyHook(context.Background(), hooks[0], []byte("image: ubuntu:latest\n"), env),
+		"policy 10-no-latest failed: alice uses a latest tag")
+	assert.EqualError(t, RunPolicyHook(context.Background(), hooks[1], nil, env),
+		"policy 20-silent failed: exit status 3")
+}
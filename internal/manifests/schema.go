@@ -0,0 +1,134 @@
+// Package manifests checks rendered Kubernetes manifests without a cluster:
+// structural schema checks, comparison against committed golden outputs, and
+// external policy hooks. It backs the devenv test command.
+package manifests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// apiVersions maps the kinds devenv generates to their expected apiVersion.
+var apiVersions = map[string]string{
+	"ConfigMap":      "v1",
+	"Ingress":        "networking.k8s.io/v1",
+	"Namespace":      "v1",
+	"PodMonitor":     "monitoring.coreos.com/v1",
+	"Role":           "rbac.authorization.k8s.io/v1",
+	"RoleBinding":    "rbac.authorization.k8s.io/v1",
+	"Service":        "v1",
+	"ServiceAccount": "v1",
+	"ServiceMonitor": "monitoring.coreos.com/v1",
+	"StatefulSet":    "apps/v1",
+}
+
+// Kubernetes object name and label formats.
+var (
+	dnsLabelRe     = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	dnsSubdomainRe = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	labelValueRe   = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?)?$`)
+)
+
+const (
+	maxNameLength       = 253
+	maxNamespaceLength  = 63
+	maxLabelValueLength = 63
+)
+
+// object is the subset of a Kubernetes object that Check inspects.
+type object struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name      string            `yaml:"name"`
+		Namespace string            `yaml:"namespace"`
+		Labels    map[string]string `yaml:"labels"`
+	} `yaml:"metadata"`
+	Data map[string]yaml.Node `yaml:"data"`
+}
+
+// Check parses a rendered manifest file, which may hold several YAML
+// documents, and returns one error per problem found. It catches mistakes
+// the API server would reject (missing kind, invalid names and labels,
+// non-string ConfigMap data) without contacting a cluster.
+func Check(filename string, content []byte) []error {
+	var problems []error
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for doc := 1; ; doc++ {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: document %d is not valid YAML: %w", filename, doc, err))
+			break
+		}
+		if len(node.Content) == 0 || node.Content[0].Kind == yaml.ScalarNode && node.Content[0].Tag == "!!null" {
+			continue // Empty document, e.g., a template that rendered nothing
+		}
+		for _, problem := range checkDocument(&node) {
+			problems = append(problems, fmt.Errorf("%s: document %d: %s", filename, doc, problem))
+		}
+	}
+	return problems
+}
+
+// checkDocument validates a single decoded YAML document.
+func checkDocument(node *yaml.Node) []string {
+	var obj object
+	if err := node.Decode(&obj); err != nil {
+		return []string{fmt.Sprintf("not a Kubernetes object: %v", err)}
+	}
+
+	var problems []string
+	if obj.Kind == "" {
+		problems = append(problems, "missing kind")
+	}
+	if obj.APIVersion == "" {
+		problems = append(problems, "missing apiVersion")
+	} else if want, ok := apiVersions[obj.Kind]; ok && obj.APIVersion != want {
+		problems = append(problems, fmt.Sprintf("%s must use apiVersion %q, not %q", obj.Kind, want, obj.APIVersion))
+	}
+
+	switch name := obj.Metadata.Name; {
+	case name == "":
+		problems = append(problems, "missing metadata.name")
+	case len(name) > maxNameLength || !dnsSubdomainRe.MatchString(name):
+		problems = append(problems, fmt.Sprintf("metadata.name %q is not a valid DNS subdomain", name))
+	}
+	if ns := obj.Metadata.Namespace; ns != "" && (len(ns) > maxNamespaceLength || !dnsLabelRe.MatchString(ns)) {
+		problems = append(problems, fmt.Sprintf("metadata.namespace %q is not a valid DNS label", ns))
+	}
+
+	for _, key := range sortedKeys(obj.Metadata.Labels) {
+		if value := obj.Metadata.Labels[key]; len(value) > maxLabelValueLength || !labelValueRe.MatchString(value) {
+			problems = append(problems, fmt.Sprintf("label %s has invalid value %q", key, value))
+		}
+	}
+
+	if obj.Kind == "ConfigMap" {
+		for _, key := range sortedKeys(obj.Data) {
+			if value := obj.Data[key]; value.Kind != yaml.ScalarNode || value.Tag != "!!str" {
+				problems = append(problems, fmt.Sprintf("ConfigMap data %q must be a string; quote the value", key))
+			}
+		}
+	}
+
+	return problems
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,63 @@
+package manifests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck_TemplateGoldens(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "templates", "testdata", "golden", "*.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Empty(t, Check(filepath.Base(file), content), "rendered manifests must pass the schema checks")
+	}
+}
+
+func TestCheck_Problems(t *testing.T) {
+	content := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: Devenv_Alice
+  namespace: devenv
+  labels:
+    developer: "alice@example.com"
+data:
+  PORT: 8080
+  NAME: "alice"
+---
+---
+apiVersion: apps/v1beta1
+kind: StatefulSet
+metadata:
+  name: devenv-alice
+---
+metadata: {}
+`
+	var messages []string
+	for _, err := range Check("env-vars.yaml", []byte(content)) {
+		messages = append(messages, err.Error())
+	}
+	assert.Equal(t, []string{
+		`env-vars.yaml: document 1: metadata.name "Devenv_Alice" is not a valid DNS subdomain`,
+		`env-vars.yaml: document 1: label developer has invalid value "alice@example.com"`,
+		`env-vars.yaml: document 1: ConfigMap data "PORT" must be a string; quote the value`,
+		`env-vars.yaml: document 3: StatefulSet must use apiVersion "apps/v1", not "apps/v1beta1"`,
+		`env-vars.yaml: document 4: missing kind`,
+		`env-vars.yaml: document 4: missing apiVersion`,
+		`env-vars.yaml: document 4: missing metadata.name`,
+	}, messages)
+}
+
+func TestCheck_InvalidYAML(t *testing.T) {
+	problems := Check("service.yaml", []byte("kind: Service\n  bad: indent\n"))
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0].Error(), "service.yaml: document 1 is not valid YAML")
+}
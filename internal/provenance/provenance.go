@@ -0,0 +1,164 @@
+// Package provenance records where generated manifests came from: the tool
+// version that rendered them and a hash of the config files they were
+// rendered from. Every rendered file starts with a comment header, and each
+// run writes an index.yaml summarizing all targets, so consumers can trace
+// an artifact back to its inputs.
+package provenance
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IndexFilename is the name of the run summary written next to the
+// generated manifests.
+const IndexFilename = "index.yaml"
+
+// Info identifies the tool run that generated the manifests.
+type Info struct {
+	Version     string
+	Commit      string
+	GeneratedAt time.Time // Zero omits the timestamp so output is reproducible
+}
+
+// Source identifies the config files a target was rendered from.
+type Source struct {
+	Files []string // Paths relative to the config directory, in merge order
+	Hash  string   // "sha256:<hex>" over the files' paths and contents
+}
+
+// HashSources hashes the config files at paths, which are layered in the
+// given order. Paths are recorded relative to configDir so the hash does not
+// depend on where the config repository is checked out. Files that do not
+// exist (e.g., an absent devenv.yaml) are skipped.
+func HashSources(configDir string, paths ...string) (Source, error) {
+	hash := sha256.New()
+	var source Source
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Source{}, err
+		}
+
+		name := path
+		if rel, err := filepath.Rel(configDir, path); err == nil {
+			name = rel
+		}
+		name = filepath.ToSlash(name)
+
+		// Length-prefix each part so different file splits never collide
+		fmt.Fprintf(hash, "%d:%s%d:", len(name), name, len(data))
+		hash.Write(data)
+		source.Files = append(source.Files, name)
+	}
+	source.Hash = "sha256:" + hex.EncodeToString(hash.Sum(nil))
+	return source, nil
+}
+
+// Header returns the YAML comment block prepended to rendered manifests.
+func Header(info Info, source Source) []byte {
+	var header bytes.Buffer
+	fmt.Fprintf(&header, "# Generated by devenv %s (commit %s). DO NOT EDIT.\n", info.Version, info.Commit)
+	fmt.Fprintf(&header, "# Source: %s\n", strings.Join(source.Files, ", "))
+	fmt.Fprintf(&header, "# Config hash: %s\n", source.Hash)
+	if !info.GeneratedAt.IsZero() {
+		fmt.Fprintf(&header, "# Generated at: %s\n", info.GeneratedAt.UTC().Format(time.RFC3339))
+	}
+	return header.Bytes()
+}
+
+// Target describes one set of manifests in the index.
+type Target struct {
+	Name        string   `yaml:"name"` // Output subdirectory, "." for the system manifests
+	Developer   string   `yaml:"developer,omitempty"`
+	Environment string   `yaml:"environment,omitempty"`
+	ConfigHash  string   `yaml:"configHash"`
+	Sources     []string `yaml:"sources"`
+	Files       []string `yaml:"files"` // Paths relative to the output directory
+}
+
+// Index summarizes a generation run. It is safe for concurrent use, so
+// batch workers can record their targets as they finish.
+type Index struct {
+	info    Info
+	mu      sync.Mutex
+	targets []Target
+}
+
+// NewIndex returns an empty index for a run.
+func NewIndex(info Info) *Index {
+	return &Index{info: info}
+}
+
+// Info returns the run information the index was created with.
+func (ix *Index) Info() Info {
+	return ix.info
+}
+
+// Add records a rendered target, replacing an earlier record with the same
+// name (e.g., when watch mode renders a developer again).
+func (ix *Index) Add(target Target) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for i := range ix.targets {
+		if ix.targets[i].Name == target.Name {
+			ix.targets[i] = target
+			return
+		}
+	}
+	ix.targets = append(ix.targets, target)
+}
+
+// Marshal renders the index as YAML with targets sorted by name.
+func (ix *Index) Marshal() ([]byte, error) {
+	ix.mu.Lock()
+	targets := append([]Target(nil), ix.targets...)
+	ix.mu.Unlock()
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+
+	doc := struct {
+		GeneratedBy string   `yaml:"generatedBy"`
+		Version     string   `yaml:"version"`
+		Commit      string   `yaml:"commit"`
+		GeneratedAt string   `yaml:"generatedAt,omitempty"`
+		Targets     []Target `yaml:"targets"`
+	}{
+		GeneratedBy: "devenv",
+		Version:     ix.info.Version,
+		Commit:      ix.info.Commit,
+		Targets:     targets,
+	}
+	if !ix.info.GeneratedAt.IsZero() {
+		doc.GeneratedAt = ix.info.GeneratedAt.UTC().Format(time.RFC3339)
+	}
+	if doc.Targets == nil {
+		doc.Targets = []Target{}
+	}
+
+	var out bytes.Buffer
+	out.WriteString("# Generated by devenv. DO NOT EDIT.\n")
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
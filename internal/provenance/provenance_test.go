@@ -0,0 +1,85 @@
+package provenance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashSources(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "alice"), 0o755))
+	developerPath := filepath.Join(configDir, "alice", "devenv-config.yaml")
+	require.NoError(t, os.WriteFile(developerPath, []byte("name: alice\n"), 0o644))
+
+	source, err := HashSources(configDir, filepath.Join(configDir, "devenv.yaml"), developerPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice/devenv-config.yaml"}, source.Files, "missing files are skipped")
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, source.Hash)
+
+	// The hash does not depend on where the config directory lives
+	otherDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(otherDir, "alice"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(otherDir, "alice", "devenv-config.yaml"), []byte("name: alice\n"), 0o644))
+	moved, err := HashSources(otherDir, filepath.Join(otherDir, "alice", "devenv-config.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, source.Hash, moved.Hash)
+
+	require.NoError(t, os.WriteFile(developerPath, []byte("name: alice\nsshPort: 30001\n"), 0o644))
+	changed, err := HashSources(configDir, developerPath)
+	require.NoError(t, err)
+	assert.NotEqual(t, source.Hash, changed.Hash)
+}
+
+func TestHeader(t *testing.T) {
+	source := Source{Files: []string{"devenv.yaml", "alice/devenv-config.yaml"}, Hash: "sha256:abc"}
+
+	assert.Equal(t, "# Generated by devenv v1.2.0 (commit 1a2b3c). DO NOT EDIT.\n"+
+		"# Source: devenv.yaml, alice/devenv-config.yaml\n"+
+		"# Config hash: sha256:abc\n",
+		string(Header(Info{Version: "v1.2.0", Commit: "1a2b3c"}, source)))
+
+	generatedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	assert.Contains(t, string(Header(Info{Version: "v1.2.0", Commit: "1a2b3c", GeneratedAt: generatedAt}, source)),
+		"# Generated at: 2026-03-01T11:00:00Z\n")
+}
+
+func TestIndex_Marshal(t *testing.T) {
+	index := NewIndex(Info{Version: "v1.2.0", Commit: "1a2b3c"})
+	index.Add(Target{
+		Name:       "alice",
+		Developer:  "alice",
+		ConfigHash: "sha256:def",
+		Sources:    []string{"devenv.yaml", "alice/devenv-config.yaml"},
+		Files:      []string{"alice/statefulset.yaml"},
+	})
+	index.Add(Target{Name: ".", ConfigHash: "sha256:old", Sources: []string{"devenv.yaml"}, Files: []string{"namespace.yaml"}})
+	index.Add(Target{Name: ".", ConfigHash: "sha256:abc", Sources: []string{"devenv.yaml"}, Files: []string{"namespace.yaml"}})
+
+	content, err := index.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, `# Generated by devenv. DO NOT EDIT.
+generatedBy: devenv
+version: v1.2.0
+commit: 1a2b3c
+targets:
+  - name: .
+    configHash: sha256:abc
+    sources:
+      - devenv.yaml
+    files:
+      - namespace.yaml
+  - name: alice
+    developer: alice
+    configHash: sha256:def
+    sources:
+      - devenv.yaml
+      - alice/devenv-config.yaml
+    files:
+      - alice/statefulset.yaml
+`, string(content))
+}
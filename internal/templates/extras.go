@@ -0,0 +1,86 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// ExtrasDir is the directory inside a developer's config directory holding
+// extra manifests, e.g. alice/extras/backup-cronjob.yaml.
+const ExtrasDir = "extras"
+
+// templateSuffix marks an extra manifest that is rendered as a template.
+const templateSuffix = ".tmpl"
+
+// extraManifest is a file of the extras directory that becomes a manifest.
+type extraManifest struct {
+	source   string // Path within the extras filesystem
+	filename string // Output filename, source without templateSuffix
+}
+
+// extraManifests lists the manifests in the extras filesystem, sorted by
+// filename. Files ending in .yaml or .yml are copied as they are, and files
+// ending in .yaml.tmpl or .yml.tmpl are rendered as templates; other files
+// and subdirectories are ignored. A missing extras directory has none.
+func (r *Renderer[T]) extraManifests() ([]extraManifest, error) {
+	if r.extras == nil {
+		return nil, nil
+	}
+	entries, err := fs.ReadDir(r.extras, ".")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list extra manifests: %w", err)
+	}
+
+	var extras []extraManifest
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		filename := strings.TrimSuffix(entry.Name(), templateSuffix)
+		if path.Ext(filename) != ".yaml" && path.Ext(filename) != ".yml" {
+			continue
+		}
+		if slices.ContainsFunc(r.targetTemplates, func(templateName string) bool { return OutputFilename(templateName) == filename }) {
+			return nil, fmt.Errorf("extra manifest %s/%s would replace the generated %s", ExtrasDir, entry.Name(), filename)
+		}
+		if i := slices.IndexFunc(extras, func(extra extraManifest) bool { return extra.filename == filename }); i >= 0 {
+			return nil, fmt.Errorf("extra manifests %s/%s and %s/%s both produce %s", ExtrasDir, extras[i].source, ExtrasDir, entry.Name(), filename)
+		}
+		extras = append(extras, extraManifest{source: entry.Name(), filename: filename})
+	}
+	return extras, nil
+}
+
+// renderExtra renders one extra manifest with the same header, functions
+// and data as the target templates.
+func (r *Renderer[T]) renderExtra(extra extraManifest, config *T) ([]byte, error) {
+	content, err := fs.ReadFile(r.extras, extra.source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read extra manifest %s: %w", extra.source, err)
+	}
+
+	var output bytes.Buffer
+	output.Write(r.header)
+	if !strings.HasSuffix(extra.source, templateSuffix) {
+		output.Write(content)
+		return output.Bytes(), nil
+	}
+
+	name := path.Join(ExtrasDir, extra.source)
+	tmpl, err := r.parseTemplate(name, string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse extra manifest %s: %w", name, err)
+	}
+	if err := tmpl.Execute(&output, config); err != nil {
+		return nil, fmt.Errorf("failed to render extra manifest %s: %w", name, err)
+	}
+	return output.Bytes(), nil
+}
@@ -0,0 +1,93 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func extrasTestConfig() *config.DevEnvConfig {
+	return &config.DevEnvConfig{
+		Name:       "alice",
+		BaseConfig: config.BaseConfig{Namespace: "devenv"},
+		SSHPort:    30001,
+	}
+}
+
+func TestRenderAllTo_Extras(t *testing.T) {
+	renderer := NewDevRenderer("")
+	renderer.SetHeader([]byte("# header\n"))
+	renderer.SetExtras(fstest.MapFS{
+		"configmap.yaml":           {Data: []byte("kind: ConfigMap\n")},
+		"backup-cronjob.yaml.tmpl": {Data: []byte("name: backup-{{ .Name }}\nnamespace: {{ .Namespace | quote }}\n")},
+		"README.md":                {Data: []byte("not a manifest\n")},
+		"nested/ignored.yaml":      {Data: []byte("kind: Secret\n")},
+	})
+
+	files := make(map[string][]byte)
+	err := renderer.RenderAllTo(extrasTestConfig(), func(filename string, content []byte) error {
+		files[filename] = content
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Raw extras are copied, templated extras are rendered with the config
+	assert.Equal(t, "# header\nkind: ConfigMap\n", string(files["configmap.yaml"]))
+	assert.Equal(t, "# header\nname: backup-alice\nnamespace: \"devenv\"\n", string(files["backup-cronjob.yaml"]))
+	assert.NotContains(t, files, "README.md")
+	assert.NotContains(t, files, "ignored.yaml")
+	assert.Contains(t, files, "statefulset.yaml")
+
+	// Extras follow the target templates
+	filenames := renderer.Filenames()
+	assert.Equal(t, []string{"backup-cronjob.yaml", "configmap.yaml"}, filenames[len(filenames)-2:])
+}
+
+func TestRenderAllTo_ExtrasErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		extras  fstest.MapFS
+		wantErr string
+	}{
+		{
+			name:    "replaces a generated manifest",
+			extras:  fstest.MapFS{"service.yaml": {Data: []byte("kind: Service\n")}},
+			wantErr: "extra manifest extras/service.yaml would replace the generated service.yaml",
+		},
+		{
+			name: "duplicate output",
+			extras: fstest.MapFS{
+				"job.yaml":      {Data: []byte("kind: Job\n")},
+				"job.yaml.tmpl": {Data: []byte("kind: Job\n")},
+			},
+			wantErr: "extra manifests extras/job.yaml and extras/job.yaml.tmpl both produce job.yaml",
+		},
+		{
+			name:    "template error",
+			extras:  fstest.MapFS{"job.yaml.tmpl": {Data: []byte("name: {{ .Nope }}\n")}},
+			wantErr: "failed to render extra manifest extras/job.yaml.tmpl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := NewDevRenderer("")
+			renderer.SetExtras(tt.extras)
+			err := renderer.RenderAllTo(extrasTestConfig(), func(string, []byte) error { return nil })
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRenderAllTo_MissingExtrasDir(t *testing.T) {
+	renderer := NewDevRenderer("")
+	renderer.SetExtras(os.DirFS(filepath.Join(t.TempDir(), ExtrasDir)))
+	extras, err := renderer.extraManifests()
+	require.NoError(t, err)
+	assert.Empty(t, extras)
+}
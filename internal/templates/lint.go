@@ -0,0 +1,318 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Rule IDs reported by Lint. They follow the "<field>:<rule>" format used by
+// config validation so they can be listed in validation.disabledRules.
+const (
+	RuleUnknownField   = "template:unknown_field"
+	RuleUndefinedExtra = "extraValues:undefined"
+	RuleUnusedExtra    = "extraValues:unused"
+)
+
+// LintIssue is a mismatch between a config and the templates that render it.
+type LintIssue struct {
+	Rule     string `json:"rule"`
+	Template string `json:"template,omitempty"` // Empty for unused config values
+	Message  string `json:"message"`
+}
+
+// lintSource is a template body together with the name used in reports.
+type lintSource struct {
+	name    string
+	content string
+}
+
+// fieldRef is a field chain evaluated against the root config, such as
+// .Git.Email (["Git", "Email"]) or $.Extra.team (["Extra", "team"]).
+type fieldRef struct {
+	template string
+	chain    []string
+}
+
+// Lint statically analyses the renderer's templates against config. It
+// reports template references to fields that do not exist on the config type,
+// .Extra keys that are not defined in extraValues, and extraValues keys that
+// no template references. Templates are parsed but not executed, so every
+// branch is checked regardless of the config's values.
+func (r *Renderer[T]) Lint(config *T) ([]LintIssue, error) {
+	sources, err := r.lintSources()
+	if err != nil {
+		return nil, err
+	}
+
+	var extra map[string]any
+	if withExtra, ok := any(config).(interface{ Extra() map[string]any }); ok {
+		extra = withExtra.Extra()
+	}
+	return lintTemplates(sources, reflect.TypeOf(config), extra, templateFuncs(r.files, r.templateRoot, r.lookup))
+}
+
+// lintSources returns the target manifest templates followed by the
+// partials and templated scripts they may include.
+func (r *Renderer[T]) lintSources() ([]lintSource, error) {
+	var sources []lintSource
+	for _, templateName := range r.targetTemplates {
+		filename := path.Join(r.templateRoot, "manifests", templateName+".tmpl")
+		content, err := fs.ReadFile(r.files, filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", templateName, err)
+		}
+		sources = append(sources, lintSource{name: templateName, content: string(content)})
+	}
+
+	partials, err := r.partials()
+	if err != nil {
+		return nil, err
+	}
+	for _, partial := range partials {
+		sources = append(sources, lintSource{name: path.Join(PartialsDir, partial.name), content: partial.content})
+	}
+
+	scriptDir := path.Join(r.templateRoot, "scripts", "templated")
+	entries, err := fs.ReadDir(r.files, scriptDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to list templated scripts: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		content, err := fs.ReadFile(r.files, path.Join(scriptDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read templated script %s: %w", entry.Name(), err)
+		}
+		sources = append(sources, lintSource{name: entry.Name(), content: string(content)})
+	}
+	return sources, nil
+}
+
+func lintTemplates(sources []lintSource, configType reflect.Type, extra map[string]any, funcs template.FuncMap) ([]LintIssue, error) {
+	var refs []fieldRef
+	for _, source := range sources {
+		tmpl, err := template.New(source.name).Funcs(funcs).Parse(source.content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", source.name, err)
+		}
+		for _, t := range tmpl.Templates() {
+			if t.Tree == nil {
+				continue
+			}
+			collector := refCollector{template: source.name}
+			collector.walk(t.Tree.Root, true)
+			refs = append(refs, collector.refs...)
+		}
+	}
+
+	var issues []LintIssue
+	seen := make(map[string]bool)
+	usedExtra := make(map[string]bool)
+	allExtraUsed := false
+	for _, ref := range refs {
+		if invalid := unresolvedPrefix(configType, ref.chain); invalid != "" {
+			key := ref.template + "\x00" + invalid
+			if !seen[key] {
+				seen[key] = true
+				issues = append(issues, LintIssue{
+					Rule:     RuleUnknownField,
+					Template: ref.template,
+					Message:  fmt.Sprintf("template %s references .%s, which is not a config field", ref.template, invalid),
+				})
+			}
+			continue
+		}
+
+		if ref.chain[0] != "Extra" && ref.chain[0] != "ExtraValues" {
+			continue
+		}
+		if len(ref.chain) == 1 {
+			// The whole map is consumed (e.g., ranged over)
+			allExtraUsed = true
+			continue
+		}
+		name := ref.chain[1]
+		usedExtra[name] = true
+		if _, defined := extra[name]; !defined {
+			key := ref.template + "\x00extra\x00" + name
+			if !seen[key] {
+				seen[key] = true
+				issues = append(issues, LintIssue{
+					Rule:     RuleUndefinedExtra,
+					Template: ref.template,
+					Message:  fmt.Sprintf("template %s references .Extra.%s, but extraValues has no %q key", ref.template, name, name),
+				})
+			}
+		}
+	}
+
+	if !allExtraUsed {
+		var unused []string
+		for name := range extra {
+			if !usedExtra[name] {
+				unused = append(unused, name)
+			}
+		}
+		sort.Strings(unused)
+		for _, name := range unused {
+			issues = append(issues, LintIssue{
+				Rule:    RuleUnusedExtra,
+				Message: fmt.Sprintf("extraValues.%s is not referenced by any template", name),
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+// refCollector gathers the field chains a template evaluates against the
+// root config. Fields inside range and with blocks are relative to a
+// different dot and are skipped unless they start from $.
+type refCollector struct {
+	template string
+	refs     []fieldRef
+}
+
+func (c *refCollector) walk(node parse.Node, rootDot bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			c.walk(child, rootDot)
+		}
+	case *parse.ActionNode:
+		c.walkPipe(n.Pipe, rootDot)
+	case *parse.IfNode:
+		c.walkPipe(n.Pipe, rootDot)
+		c.walk(n.List, rootDot)
+		c.walk(n.ElseList, rootDot)
+	case *parse.RangeNode:
+		c.walkPipe(n.Pipe, rootDot)
+		c.walk(n.List, false)
+		c.walk(n.ElseList, rootDot)
+	case *parse.WithNode:
+		c.walkPipe(n.Pipe, rootDot)
+		c.walk(n.List, false)
+		c.walk(n.ElseList, rootDot)
+	case *parse.TemplateNode:
+		c.walkPipe(n.Pipe, rootDot)
+	}
+}
+
+func (c *refCollector) walkPipe(pipe *parse.PipeNode, rootDot bool) {
+	if pipe == nil {
+		return
+	}
+	for _, cmd := range pipe.Cmds {
+		if c.walkIndexCall(cmd, rootDot) {
+			continue
+		}
+		for _, arg := range cmd.Args {
+			c.walkArg(arg, rootDot)
+		}
+	}
+}
+
+// walkIndexCall records {{index .Extra "key"}} as a reference to .Extra.key
+// and reports whether cmd was such a call.
+func (c *refCollector) walkIndexCall(cmd *parse.CommandNode, rootDot bool) bool {
+	if len(cmd.Args) != 3 {
+		return false
+	}
+	fn, ok := cmd.Args[0].(*parse.IdentifierNode)
+	if !ok || fn.Ident != "index" {
+		return false
+	}
+	key, ok := cmd.Args[2].(*parse.StringNode)
+	if !ok {
+		return false
+	}
+	var chain []string
+	switch target := cmd.Args[1].(type) {
+	case *parse.FieldNode:
+		if rootDot {
+			chain = target.Ident
+		}
+	case *parse.VariableNode:
+		if target.Ident[0] == "$" {
+			chain = target.Ident[1:]
+		}
+	}
+	if len(chain) != 1 || (chain[0] != "Extra" && chain[0] != "ExtraValues") {
+		return false
+	}
+	c.refs = append(c.refs, fieldRef{template: c.template, chain: []string{chain[0], key.Text}})
+	return true
+}
+
+func (c *refCollector) walkArg(arg parse.Node, rootDot bool) {
+	switch n := arg.(type) {
+	case *parse.FieldNode:
+		if rootDot {
+			c.refs = append(c.refs, fieldRef{template: c.template, chain: n.Ident})
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			c.refs = append(c.refs, fieldRef{template: c.template, chain: n.Ident[1:]})
+		}
+	case *parse.ChainNode:
+		c.walkArg(n.Node, rootDot)
+	case *parse.PipeNode:
+		c.walkPipe(n, rootDot)
+	}
+}
+
+// unresolvedPrefix follows chain through typ the way text/template resolves
+// methods, struct fields and map keys. It returns the dotted prefix up to
+// and including the first identifier that cannot be resolved, or "" when
+// the chain is valid. Lookups through interface values are dynamic and are
+// accepted as-is.
+func unresolvedPrefix(typ reflect.Type, chain []string) string {
+	for i, name := range chain {
+		if typ.Kind() == reflect.Interface {
+			return ""
+		}
+		ptr := typ
+		if ptr.Kind() != reflect.Pointer {
+			ptr = reflect.PointerTo(typ)
+		}
+		if method, ok := ptr.MethodByName(name); ok {
+			if method.Type.NumOut() == 0 {
+				return strings.Join(chain[:i+1], ".")
+			}
+			typ = method.Type.Out(0)
+			continue
+		}
+
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		switch typ.Kind() {
+		case reflect.Struct:
+			field, ok := typ.FieldByName(name)
+			if !ok || !field.IsExported() {
+				return strings.Join(chain[:i+1], ".")
+			}
+			typ = field.Type
+		case reflect.Map:
+			if typ.Key().Kind() != reflect.String {
+				return strings.Join(chain[:i+1], ".")
+			}
+			typ = typ.Elem()
+		default:
+			return strings.Join(chain[:i+1], ".")
+		}
+	}
+	return ""
+}
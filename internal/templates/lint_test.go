@@ -0,0 +1,78 @@
+package templates
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLint_EmbeddedTemplates verifies that the shipped templates only
+// reference fields that exist on their config types.
+func TestLint_EmbeddedTemplates(t *testing.T) {
+	devIssues, err := NewDevRenderer(t.TempDir()).Lint(&config.DevEnvConfig{Name: "testuser"})
+	require.NoError(t, err)
+	assert.Empty(t, devIssues)
+
+	systemIssues, err := NewSystemRenderer(t.TempDir()).Lint(&config.BaseConfig{})
+	require.NoError(t, err)
+	assert.Empty(t, systemIssues)
+}
+
+func TestLint_UnusedExtraValues(t *testing.T) {
+	cfg := &config.DevEnvConfig{
+		Name: "testuser",
+		BaseConfig: config.BaseConfig{
+			ExtraValues: map[string]any{"team": "ml", "costCenter": 42},
+		},
+	}
+
+	issues, err := NewDevRenderer(t.TempDir()).Lint(cfg)
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+	assert.Equal(t, RuleUnusedExtra, issues[0].Rule)
+	assert.Contains(t, issues[0].Message, "extraValues.costCenter")
+	assert.Contains(t, issues[1].Message, "extraValues.team")
+}
+
+func TestLintTemplates(t *testing.T) {
+	configType := reflect.TypeOf(&config.DevEnvConfig{})
+	extra := map[string]any{"team": "ml", "unused": true}
+
+	sources := []lintSource{
+		{name: "good", content: `{{.Name}} {{.Git.Email}} {{.CPURequest}} {{.Extra.team}}` +
+			`{{range .Volumes}}{{.ContainerPath}} {{$.Namespace}}{{end}}` +
+			`{{with .Annotations.Service}}{{.foo}}{{end}}`},
+		{name: "bad", content: `{{.Nmae}} {{.Git.Phone}} {{if .Missing}}{{end}}` +
+			`{{range .Volumes}}{{$.Oops}}{{end}} {{index .Extra "region"}} {{.Name.Length}}`},
+	}
+
+	issues, err := lintTemplates(sources, configType, extra, templateFuncs(templates, "template_files/dev", noLookup))
+	require.NoError(t, err)
+
+	var messages []string
+	for _, issue := range issues {
+		messages = append(messages, issue.Rule+" "+issue.Message)
+	}
+	assert.Equal(t, []string{
+		RuleUnknownField + " template bad references .Nmae, which is not a config field",
+		RuleUnknownField + " template bad references .Git.Phone, which is not a config field",
+		RuleUnknownField + " template bad references .Missing, which is not a config field",
+		RuleUnknownField + " template bad references .Oops, which is not a config field",
+		RuleUndefinedExtra + ` template bad references .Extra.region, but extraValues has no "region" key`,
+		RuleUnknownField + " template bad references .Name.Length, which is not a config field",
+		RuleUnusedExtra + " extraValues.unused is not referenced by any template",
+	}, messages)
+}
+
+func TestLintTemplates_WholeExtraMapUsed(t *testing.T) {
+	sources := []lintSource{
+		{name: "dump", content: `{{range $k, $v := .Extra}}{{$k}}={{$v}}{{end}}`},
+	}
+	issues, err := lintTemplates(sources, reflect.TypeOf(&config.DevEnvConfig{}),
+		map[string]any{"team": "ml"}, templateFuncs(templates, "template_files/dev", noLookup))
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+}
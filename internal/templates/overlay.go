@@ -0,0 +1,78 @@
+package templates
+
+import (
+	"errors"
+	"io/fs"
+	"sort"
+)
+
+// WithOverrides returns the embedded template files overlaid with files,
+// which must have the layout of this package's directory
+// (template_files/dev/manifests/...). A file in files replaces the embedded
+// file at the same path; every other file is read from the embedded
+// templates, so an override directory only needs the files it changes.
+// Directory listings contain the files of both.
+func WithOverrides(files fs.FS) fs.FS {
+	return overlayFS{upper: files, lower: templates}
+}
+
+// overlayFS reads files from upper, falling back to lower.
+type overlayFS struct {
+	upper fs.FS
+	lower fs.FS
+}
+
+// Open opens name from upper if it is a regular file there, and from lower
+// otherwise. Directories are opened from lower unless only upper has them;
+// use ReadDir to list the files of both.
+func (o overlayFS) Open(name string) (fs.File, error) {
+	file, err := o.upper.Open(name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		info, statErr := file.Stat()
+		if statErr == nil && !info.IsDir() {
+			return file, nil
+		}
+		if lowerFile, lowerErr := o.lower.Open(name); lowerErr == nil {
+			file.Close()
+			return lowerFile, nil
+		}
+		if statErr != nil {
+			file.Close()
+			return nil, statErr
+		}
+		return file, nil
+	}
+	return o.lower.Open(name)
+}
+
+// ReadDir lists name in both filesystems, preferring the entries of upper.
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	upperEntries, upperErr := fs.ReadDir(o.upper, name)
+	if upperErr != nil && !errors.Is(upperErr, fs.ErrNotExist) {
+		return nil, upperErr
+	}
+	lowerEntries, lowerErr := fs.ReadDir(o.lower, name)
+	if lowerErr != nil && !errors.Is(lowerErr, fs.ErrNotExist) {
+		return nil, lowerErr
+	}
+	if upperErr != nil && lowerErr != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	entries := make(map[string]fs.DirEntry, len(upperEntries)+len(lowerEntries))
+	for _, entry := range lowerEntries {
+		entries[entry.Name()] = entry
+	}
+	for _, entry := range upperEntries {
+		entries[entry.Name()] = entry
+	}
+	merged := make([]fs.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		merged = append(merged, entry)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name() < merged[j].Name() })
+	return merged, nil
+}
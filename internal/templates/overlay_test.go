@@ -0,0 +1,63 @@
+package templates
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithOverrides_Precedence(t *testing.T) {
+	files := WithOverrides(fstest.MapFS{
+		"template_files/system/manifests/namespace.tmpl":  {Data: []byte("name: {{ .Namespace }}\n")},
+		"template_files/dev/scripts/static/extra-tool.sh": {Data: []byte("#!/bin/sh\n")},
+	})
+
+	// An overridden file is read from the override
+	content, err := fs.ReadFile(files, "template_files/system/manifests/namespace.tmpl")
+	require.NoError(t, err)
+	assert.Equal(t, "name: {{ .Namespace }}\n", string(content))
+
+	// A missing file falls back to the embedded one
+	content, err = fs.ReadFile(files, "template_files/dev/manifests/service.tmpl")
+	require.NoError(t, err)
+	embedded, err := fs.ReadFile(templates, "template_files/dev/manifests/service.tmpl")
+	require.NoError(t, err)
+	assert.Equal(t, embedded, content)
+
+	// A file in neither is not found
+	_, err = fs.ReadFile(files, "template_files/dev/manifests/missing.tmpl")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	// Directory listings contain the files of both
+	entries, err := fs.ReadDir(files, "template_files/dev/scripts/static")
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"extra-tool.sh", "requirements.txt", "run_with_git.sh"}, names)
+}
+
+func TestWithOverrides_Render(t *testing.T) {
+	renderer := NewSystemRenderer("")
+	renderer.SetFS(WithOverrides(fstest.MapFS{
+		"template_files/system/manifests/namespace.tmpl": {Data: []byte("name: {{ .Namespace }}\n")},
+	}))
+	content, err := renderer.RenderToBytes("namespace", &config.BaseConfig{Namespace: "devenv"})
+	require.NoError(t, err)
+	assert.Equal(t, "name: devenv\n", string(content))
+
+	// Templates without an override render as embedded
+	devRenderer := NewDevRenderer("")
+	devRenderer.SetFS(WithOverrides(fstest.MapFS{}))
+	cfg := &config.DevEnvConfig{Name: "alice", BaseConfig: config.BaseConfig{Namespace: "devenv"}, SSHPort: 30001}
+	want, err := NewDevRenderer("").RenderToBytes("service", cfg)
+	require.NoError(t, err)
+	got, err := devRenderer.RenderToBytes("service", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
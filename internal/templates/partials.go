@@ -0,0 +1,83 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// PartialsDir is the directory next to manifests/ that holds the partials
+// manifest templates share, e.g. template_files/dev/partials/labels.tmpl.
+const PartialsDir = "partials"
+
+// partial is a shared template block, named after its file without .tmpl.
+type partial struct {
+	name    string
+	content string
+}
+
+// partials returns the partials of the renderer's template root, sorted by
+// name. A template root without a partials directory has none.
+func (r *Renderer[T]) partials() ([]partial, error) {
+	dir := path.Join(r.templateRoot, PartialsDir)
+	entries, err := fs.ReadDir(r.files, dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partials: %w", err)
+	}
+
+	var partials []partial
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), templateSuffix) {
+			continue
+		}
+		content, err := fs.ReadFile(r.files, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read partial %s: %w", entry.Name(), err)
+		}
+		partials = append(partials, partial{name: strings.TrimSuffix(entry.Name(), templateSuffix), content: string(content)})
+	}
+	sort.Slice(partials, func(i, j int) bool { return partials[i].name < partials[j].name })
+	return partials, nil
+}
+
+// parseTemplate parses a manifest template together with the partials, so
+// it can render them with include (or the template action, where no
+// indentation is needed).
+func (r *Renderer[T]) parseTemplate(name, content string) (*template.Template, error) {
+	partials, err := r.partials()
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := template.New(name)
+	funcs := templateFuncs(r.files, r.templateRoot, r.lookup)
+	// include renders a partial without the trailing newline, for use with
+	// indent: {{indent 4 (include "labels" .)}}
+	funcs["include"] = func(partialName string, data any) (string, error) {
+		var output strings.Builder
+		if err := tmpl.ExecuteTemplate(&output, partialName, data); err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(output.String(), "\n"), nil
+	}
+	if _, err := tmpl.Funcs(funcs).Parse(content); err != nil {
+		return nil, err
+	}
+
+	for _, partial := range partials {
+		if partial.name == name {
+			return nil, fmt.Errorf("partial %s has the same name as the template", partial.name)
+		}
+		if _, err := tmpl.New(partial.name).Parse(partial.content); err != nil {
+			return nil, fmt.Errorf("failed to parse partial %s: %w", partial.name, err)
+		}
+	}
+	return tmpl, nil
+}
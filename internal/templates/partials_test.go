@@ -0,0 +1,80 @@
+package templates
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderAllTo_ExtrasIncludePartials(t *testing.T) {
+	renderer := NewDevRenderer("")
+	renderer.SetExtras(fstest.MapFS{
+		"backup-cronjob.yaml.tmpl": {Data: []byte("metadata:\n  labels:\n    {{indent 4 (include \"labels\" .)}}\n    component: backup\n")},
+	})
+
+	files := make(map[string][]byte)
+	err := renderer.RenderAllTo(extrasTestConfig(), func(filename string, content []byte) error {
+		files[filename] = content
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "metadata:\n  labels:\n    app: devenv-alice\n    developer: \"alice\"\n    component: backup\n",
+		string(files["backup-cronjob.yaml"]))
+}
+
+func TestRenderToBytes_OverriddenPartials(t *testing.T) {
+	renderer := NewDevRenderer("")
+	renderer.SetFS(WithOverrides(fstest.MapFS{
+		// Overriding a partial changes every built-in template that includes it
+		"template_files/dev/partials/labels.tmpl": {Data: []byte("app: {{nameFor \"devenv\" .InstanceName}}\nteam: ml\n")},
+		// Operators can add partials for their own templates
+		"template_files/dev/partials/owner.tmpl":        {Data: []byte("owner: {{.Name}}\n")},
+		"template_files/dev/manifests/statefulset.tmpl": {Data: []byte("{{include \"owner\" .}}\n{{template \"labels\" .}}")},
+	}))
+	cfg := extrasTestConfig()
+
+	content, err := renderer.RenderToBytes("service", cfg)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "    app: devenv-alice\n    team: ml\n    service: governing\n")
+
+	content, err = renderer.RenderToBytes("statefulset", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "owner: alice\napp: devenv-alice\nteam: ml\n", string(content))
+}
+
+func TestRenderToBytes_PartialErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		wantErr string
+	}{
+		{
+			name:    "unknown partial",
+			files:   fstest.MapFS{"template_files/dev/manifests/service.tmpl": {Data: []byte("{{include \"missing\" .}}\n")}},
+			wantErr: `no template "missing"`,
+		},
+		{
+			name:    "invalid partial",
+			files:   fstest.MapFS{"template_files/dev/partials/broken.tmpl": {Data: []byte("{{ if }}\n")}},
+			wantErr: "failed to parse partial broken",
+		},
+		{
+			name:    "partial named like the template",
+			files:   fstest.MapFS{"template_files/dev/partials/service.tmpl": {Data: []byte("kind: Service\n")}},
+			wantErr: "partial service has the same name as the template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := NewDevRenderer("")
+			renderer.SetFS(WithOverrides(tt.files))
+			_, err := renderer.RenderToBytes("service", extrasTestConfig())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -1,21 +1,33 @@
 package templates
 
 import (
+	"bytes"
 	"embed"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/nauticalab/devenv-engine/internal/labels"
+	"gopkg.in/yaml.v3"
 )
 
-var devTemplatesToRender = []string{"statefulset", "service", "env-vars",
-	"startup-scripts", "ingress"}
+// The namespace comes first so that a single-file manifest creates it before
+// the objects in it.
+var devTemplatesToRender = []string{"namespace", "statefulset", "service", "env-vars",
+	"startup-scripts", "ingress", "networkpolicy", "pvc", "support-access", "monitoring", "logging"}
 
-var systemTemplatesToRender = []string{"namespace"}
+var systemTemplatesToRender = []string{"namespace", "grafana-dashboard"}
 
 // Embed all devTemplates and scripts at compile time
 //
@@ -27,6 +39,25 @@ type Renderer[T config.BaseConfig | config.DevEnvConfig] struct {
 	outputDir       string
 	templateRoot    string
 	targetTemplates []string
+	header          []byte // Prepended to every rendered manifest, if set
+	keepGoing       bool   // Write the templates that rendered even if others fail
+	files           fs.FS  // Template files; the embedded templates unless overridden
+	extras          fs.FS  // Extra manifests rendered after the target templates, if set
+	lookup          LookupFunc
+	validate        ValidateFunc // Checks every rendered manifest before anything is written, if set
+}
+
+// ValidateFunc checks a rendered manifest, e.g. against Kubernetes schemas,
+// and returns an error describing every problem found.
+type ValidateFunc func(filename string, content []byte) error
+
+// LookupFunc resolves key in the named data source for the lookup template
+// function.
+type LookupFunc func(source, key string) (any, error)
+
+// noLookup is the lookup of renderers without data sources.
+func noLookup(source, key string) (any, error) {
+	return nil, fmt.Errorf("unknown data source %q (no dataSources are declared in devenv.yaml)", source)
 }
 
 // NewRenderer creates a new template renderer
@@ -43,27 +74,68 @@ func NewRendererWithFS[T config.BaseConfig | config.DevEnvConfig](outputDir stri
 		outputDir:       outputDir,
 		templateRoot:    templateRoot,
 		targetTemplates: targetTemplates,
+		files:           templates,
+		lookup:          noLookup,
 	}
 }
 
-func templateFuncs(templateRoot string) template.FuncMap {
+func templateFuncs(files fs.FS, templateRoot string, lookup LookupFunc) template.FuncMap {
 	return template.FuncMap{
+		// lookup reads a value from a data source declared in devenv.yaml
+		"lookup": lookup,
 		"b64enc": func(s string) string {
 			return base64.StdEncoding.EncodeToString([]byte(s))
 		},
+		"nameFor":         config.ResourceName,
+		"volumeClaimName": config.VolumeClaimName,
+		// Label keys of the labels package, so templates follow its schema
+		"appLabel": func() string {
+			return labels.App
+		},
+		"componentLabel": func() string {
+			return labels.Component
+		},
+		"serviceLabel": func() string {
+			return labels.Service
+		},
+		"developerLabel": func() string {
+			return labels.Developer
+		},
+		"labelValue": labels.Value,
+		// quote renders s as a double-quoted YAML scalar (JSON strings are valid YAML)
+		"quote": func(s string) (string, error) {
+			quoted, err := json.Marshal(s)
+			return string(quoted), err
+		},
+		// toYaml renders v as a YAML block without the trailing newline, for
+		// use with indent
+		"toYaml": func(v any) (string, error) {
+			var out strings.Builder
+			encoder := yaml.NewEncoder(&out)
+			encoder.SetIndent(2)
+			if err := encoder.Encode(v); err != nil {
+				return "", err
+			}
+			return strings.TrimSuffix(out.String(), "\n"), encoder.Close()
+		},
+		// include is bound to the template being rendered by parseTemplate;
+		// this stub lets templates that use it parse on their own
+		"include": func(partialName string, data any) (string, error) {
+			return "", fmt.Errorf("partial %s cannot be included here", partialName)
+		},
 		"indent": func(spaces int, s string) string {
 			padding := strings.Repeat(" ", spaces)
 			return strings.ReplaceAll(s, "\n", "\n"+padding)
 		},
 		"getTemplatedScript": func(scriptName string, config *config.DevEnvConfig) (string, error) {
 			// Read the template content
-			content, err := templates.ReadFile(filepath.Join(templateRoot, fmt.Sprintf("scripts/templated/%s", scriptName)))
+			content, err := fs.ReadFile(files, path.Join(templateRoot, "scripts/templated", scriptName))
 			if err != nil {
 				return "", fmt.Errorf("failed to read templated script %s: %w", scriptName, err)
 			}
 
 			// Parse and execute template with config
-			tmpl, err := template.New(scriptName).Funcs(templateFuncs(templateRoot)).Parse(string(content))
+			tmpl, err := template.New(scriptName).Funcs(templateFuncs(files, templateRoot, lookup)).Parse(string(content))
 			if err != nil {
 				return "", fmt.Errorf("failed to parse script template %s: %w", scriptName, err)
 			}
@@ -76,7 +148,7 @@ func templateFuncs(templateRoot string) template.FuncMap {
 			return output.String(), nil
 		},
 		"getStaticScript": func(scriptName string) (string, error) {
-			content, err := templates.ReadFile(filepath.Join(templateRoot, fmt.Sprintf("scripts/static/%s", scriptName)))
+			content, err := fs.ReadFile(files, path.Join(templateRoot, "scripts/static", scriptName))
 			if err != nil {
 				return "", fmt.Errorf("failed to read static script %s: %w", scriptName, err)
 			}
@@ -85,49 +157,228 @@ func templateFuncs(templateRoot string) template.FuncMap {
 	}
 }
 
-func (r *Renderer[T]) RenderTemplate(templateName string, config *T) error {
+// SetHeader sets content, typically a YAML comment block, that is prepended
+// to every rendered manifest.
+func (r *Renderer[T]) SetHeader(header []byte) {
+	r.header = header
+}
+
+// SetFS replaces the embedded template files with files, which must have
+// the layout of this package's directory (template_files/dev/manifests/...).
+// Files are read on every render, so os.DirFS picks up edits without a
+// rebuild.
+func (r *Renderer[T]) SetFS(files fs.FS) {
+	r.files = files
+}
+
+// SetExtras adds the manifests in extras, typically a developer's extras/
+// directory, to the ones RenderAll and RenderAllTo produce. Extra manifests
+// get the same header and are rendered with the same functions and config
+// as the target templates when their name ends in .tmpl; see
+// extraManifests for which files are used.
+func (r *Renderer[T]) SetExtras(extras fs.FS) {
+	r.extras = extras
+}
+
+// SetLookup sets how the lookup template function resolves values, e.g.
+// from the data sources declared in devenv.yaml. Without it, lookup fails.
+func (r *Renderer[T]) SetLookup(lookup LookupFunc) {
+	r.lookup = lookup
+}
+
+// SetValidate makes RenderAll and RenderAllTo check every rendered manifest
+// with validate. A manifest that fails the check counts as a failed
+// template: nothing is written unless keep-going is set, and the problems
+// are returned with the other failures.
+func (r *Renderer[T]) SetValidate(validate ValidateFunc) {
+	r.validate = validate
+}
+
+// SetKeepGoing makes RenderAll and RenderAllTo write the templates that
+// rendered successfully even when other templates fail. The failures are
+// still returned.
+func (r *Renderer[T]) SetKeepGoing(keepGoing bool) {
+	r.keepGoing = keepGoing
+}
+
+// Filenames returns the output filenames of the target templates in render
+// order, followed by those of the extra manifests. Extra manifests that
+// cannot be listed are left out; RenderAllTo reports the error.
+func (r *Renderer[T]) Filenames() []string {
+	filenames := make([]string, len(r.targetTemplates))
+	for i, templateName := range r.targetTemplates {
+		filenames[i] = OutputFilename(templateName)
+	}
+	extras, _ := r.extraManifests()
+	for _, extra := range extras {
+		filenames = append(filenames, extra.filename)
+	}
+	return filenames
+}
+
+// Templates returns the names of the target templates in render order.
+func (r *Renderer[T]) Templates() []string {
+	return append([]string(nil), r.targetTemplates...)
+}
+
+// TemplateSource returns the source of a manifest template.
+func (r *Renderer[T]) TemplateSource(templateName string) ([]byte, error) {
+	content, err := fs.ReadFile(r.files, path.Join(r.templateRoot, "manifests", templateName+".tmpl"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", templateName, err)
+	}
+	return content, nil
+}
+
+// RenderToBytes renders a single template into memory and returns the
+// resulting manifest content.
+func (r *Renderer[T]) RenderToBytes(templateName string, config *T) ([]byte, error) {
 	// Get the template content from embedded files
-	templateContent, err := templates.ReadFile(filepath.Join(r.templateRoot, fmt.Sprintf("manifests/%s.tmpl", templateName)))
+	templateContent, err := r.TemplateSource(templateName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Parse template
-	tmpl, err := template.New(templateName).Funcs(templateFuncs(r.templateRoot)).Parse(string(templateContent))
+	tmpl, err := r.parseTemplate(templateName, string(templateContent))
 	if err != nil {
-		return fmt.Errorf("failed to parse template %s: %w", templateName, err)
+		return nil, fmt.Errorf("failed to parse template %s: %w", templateName, err)
 	}
 
-	// Create output directory if it doesn't exist
-	if err := os.MkdirAll(r.outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory %s: %w", r.outputDir, err)
+	// Execute template with DevEnvConfig - simple and clean!
+	var output bytes.Buffer
+	output.Write(r.header)
+	if err := tmpl.Execute(&output, config); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %w", templateName, err)
 	}
 
-	// Output filename is simply template name + .yaml
-	outputFilename := fmt.Sprintf("%s.yaml", templateName)
+	return output.Bytes(), nil
+}
 
-	// Create output file
-	outputPath := filepath.Join(r.outputDir, outputFilename)
-	outputFile, err := os.Create(outputPath)
+// RenderTemplate renders a single template into the renderer's output
+// directory.
+func (r *Renderer[T]) RenderTemplate(templateName string, config *T) error {
+	content, err := r.RenderToBytes(templateName, config)
 	if err != nil {
-		return fmt.Errorf("failed to create output file %s: %w", outputPath, err)
+		return err
 	}
-	defer outputFile.Close()
+	return r.writeFile(OutputFilename(templateName), content)
+}
 
-	// Execute template with DevEnvConfig - simple and clean!
-	if err := tmpl.Execute(outputFile, config); err != nil {
-		return fmt.Errorf("failed to render template %s: %w", templateName, err)
+// writeFile writes a rendered manifest into the output directory.
+func (r *Renderer[T]) writeFile(filename string, content []byte) error {
+	// Create output directory if it doesn't exist
+	if err := os.MkdirAll(r.outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", r.outputDir, err)
+	}
+
+	outputPath := filepath.Join(r.outputDir, filename)
+	if err := os.WriteFile(outputPath, content, 0644); err != nil {
+		return fmt.Errorf("failed to create output file %s: %w", outputPath, err)
 	}
 
 	fmt.Printf("✅ Generated %s\n", outputPath)
 	return nil
 }
 
+// RenderAll renders every target template into the renderer's output
+// directory. See RenderAllTo for how failures are reported.
 func (r *Renderer[T]) RenderAll(config *T) error {
-	for _, templateName := range r.targetTemplates {
-		if err := r.RenderTemplate(templateName, config); err != nil {
-			return fmt.Errorf("failed to render template %s: %w", templateName, err)
+	return r.RenderAllTo(config, r.writeFile)
+}
+
+// RenderAllTo renders every target template and extra manifest in memory
+// and hands each result to write together with its output filename (e.g.,
+// "statefulset.yaml"). Nothing is written to the renderer's output
+// directory, which lets callers stream manifests into archives or other
+// sinks.
+//
+// Templates are rendered concurrently, and every failed template is
+// reported in the returned error, not just the first. Unless keep-going is
+// set, nothing is written when any template fails; with keep-going, the
+// templates that rendered are still written.
+func (r *Renderer[T]) RenderAllTo(config *T, write func(filename string, content []byte) error) error {
+	extras, err := r.extraManifests()
+	if err != nil {
+		return err
+	}
+	filenames, contents, errs := r.renderAll(config, extras)
+	if r.validate != nil {
+		for i, filename := range filenames {
+			if errs[i] == nil {
+				errs[i] = r.validate(filename, contents[i])
+			}
 		}
 	}
-	return nil
+	if err := errors.Join(errs...); err != nil && !r.keepGoing {
+		return err
+	}
+
+	for i, filename := range filenames {
+		if errs[i] != nil {
+			continue
+		}
+		if err := write(filename, contents[i]); err != nil {
+			errs[i] = fmt.Errorf("failed to write %s: %w", filename, err)
+			if !r.keepGoing {
+				return errs[i]
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// renderAll renders the target templates and then the extra manifests
+// concurrently. The returned slices are indexed alike, with the output
+// filename, content and an error for each failed manifest.
+func (r *Renderer[T]) renderAll(config *T, extras []extraManifest) ([]string, [][]byte, []error) {
+	count := len(r.targetTemplates) + len(extras)
+	filenames := make([]string, count)
+	contents := make([][]byte, count)
+	errs := make([]error, count)
+
+	var wg sync.WaitGroup
+	for i, templateName := range r.targetTemplates {
+		filenames[i] = OutputFilename(templateName)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			contents[i], errs[i] = r.RenderToBytes(templateName, config)
+		}()
+	}
+	for j, extra := range extras {
+		i := len(r.targetTemplates) + j
+		filenames[i] = extra.filename
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			contents[i], errs[i] = r.renderExtra(extra, config)
+		}()
+	}
+	wg.Wait()
+
+	return filenames, contents, errs
+}
+
+// ErrorPosition extracts the line and column (0-based, in bytes) at which a
+// parse or execution error occurred in the named template. Parse errors
+// carry no column and report -1. ok is false if err does not point into
+// templateName.
+func ErrorPosition(err error, templateName string) (line, column int, ok bool) {
+	pattern := regexp.MustCompile(`template: ` + regexp.QuoteMeta(templateName) + `:(\d+)(?::(\d+))?:`)
+	match := pattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0, 0, false
+	}
+	line, _ = strconv.Atoi(match[1])
+	column = -1
+	if match[2] != "" {
+		column, _ = strconv.Atoi(match[2])
+	}
+	return line, column, true
+}
+
+// OutputFilename returns the manifest filename produced for a template.
+func OutputFilename(templateName string) string {
+	return fmt.Sprintf("%s.yaml", templateName)
 }
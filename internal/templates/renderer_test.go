@@ -1,14 +1,22 @@
 package templates
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"flag"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"text/template"
 
 	"github.com/nauticalab/devenv-engine/internal/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 // TestRenderTemplate tests individual template rendering with golden files
@@ -31,12 +39,42 @@ func TestRenderTemplate(t *testing.T) {
 				Python: []string{"numpy", "pandas"},
 				APT:    []string{"vim", "curl"},
 			},
+			Secrets: []config.SecretRef{
+				{Name: "api-tokens", Env: true},
+				{Name: "wandb", Env: true, MountPath: "/etc/wandb", Optional: true},
+			},
+			Env: map[string]string{
+				"LOG_LEVEL": "debug",
+				"MOTD":      "Welcome, \"testuser\"",
+			},
 			Resources: config.ResourceConfig{
 				CPU:     "4",
 				Memory:  "16Gi",
 				Storage: "100Gi",
 				GPU:     2,
 			},
+			Network: config.NetworkConfig{
+				Isolation:         config.NetworkIsolationStrict,
+				AllowedNamespaces: []string{"monitoring"},
+				AllowedPorts:      []int{8888},
+			},
+			NodeSelector: map[string]string{"nvidia.com/gpu.present": "true"},
+			Tolerations: []config.Toleration{
+				{Key: "nvidia.com/gpu", Operator: "Exists", Effect: "NoSchedule"},
+			},
+			Affinity: map[string]any{
+				"podAntiAffinity": map[string]any{
+					"preferredDuringSchedulingIgnoredDuringExecution": []any{
+						map[string]any{
+							"weight": 100,
+							"podAffinityTerm": map[string]any{
+								"topologyKey":   "kubernetes.io/hostname",
+								"labelSelector": map[string]any{"matchLabels": map[string]any{"component": "devenv"}},
+							},
+						},
+					},
+				},
+			},
 			Volumes: []config.VolumeMount{
 				{
 					Name:          "data-volume",
@@ -57,8 +95,20 @@ func TestRenderTemplate(t *testing.T) {
 			Email: "testuser@example.com",
 		},
 	}
+	testConfig.SupportAccess = true
+	testConfig.Observability = config.ObservabilityConfig{
+		MetricsPort: 9100,
+		Interval:    "30s",
+		Labels:      map[string]string{"release": "kube-prometheus-stack"},
+	}
+	testConfig.Logging = config.LoggingConfig{
+		Enabled: true,
+		Sink:    config.LoggingSinkConfig{Type: config.LogSinkLoki, Host: "loki.logging.svc", TLS: true},
+		Labels:  map[string]string{"team": "ml"},
+		Paths:   []string{"logs/*.log", "runs/*/train.log"},
+	}
 
-	templates := []string{"statefulset", "service", "env-vars", "startup-scripts", "ingress"}
+	templates := []string{"statefulset", "service", "env-vars", "startup-scripts", "ingress", "networkpolicy", "support-access", "monitoring", "logging"}
 
 	for _, templateName := range templates {
 		t.Run(templateName, func(t *testing.T) {
@@ -166,3 +216,817 @@ func TestRenderTemplate_ErrorCases(t *testing.T) {
 // Command-line flag for updating golden files
 // Usage: go test -v ./internal/templates -update-golden
 var updateGolden = flag.Bool("update-golden", false, "update golden files")
+
+// TestRenderTemplate_LongDeveloperName verifies that derived resource names
+// stay within Kubernetes limits for long developer names.
+func TestRenderTemplate_LongDeveloperName(t *testing.T) {
+	longName := "a" + strings.Repeat("b", 60)
+	testConfig := &config.DevEnvConfig{
+		Name: longName,
+		BaseConfig: config.BaseConfig{
+			SSHPublicKey: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC7... long@example.com",
+			Namespace:    "devenv-test",
+		},
+		SSHPort: 30003,
+	}
+
+	tempDir := t.TempDir()
+	renderer := NewDevRenderer(tempDir)
+	require.NoError(t, renderer.RenderTemplate("startup-scripts", testConfig))
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "startup-scripts.yaml"))
+	require.NoError(t, err)
+
+	expectedName, err := config.ResourceName(config.ResourceStartupScripts, longName)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "name: "+expectedName+"\n")
+	assert.LessOrEqual(t, len(expectedName), 63)
+}
+
+// TestRenderTemplate_NetworkPolicyOpen verifies that no NetworkPolicy is
+// generated unless isolation is strict.
+func TestRenderTemplate_NetworkPolicyOpen(t *testing.T) {
+	testConfig := &config.DevEnvConfig{
+		Name: "testuser",
+		BaseConfig: config.BaseConfig{
+			SSHPublicKey: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC7... testuser@example.com",
+			Namespace:    "devenv-test",
+		},
+		SSHPort: 30001,
+	}
+
+	content, err := NewDevRenderer(t.TempDir()).RenderToBytes("networkpolicy", testConfig)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "kind:")
+}
+
+func TestRenderTemplate_Namespace(t *testing.T) {
+	testConfig := &config.DevEnvConfig{
+		Name: "testuser",
+		BaseConfig: config.BaseConfig{
+			SSHPublicKey: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC7... testuser@example.com",
+			Namespace:    "devenv-test",
+		},
+		SSHPort: 30001,
+	}
+	renderer := NewDevRenderer(t.TempDir())
+
+	// Shared namespaces are created by the system manifests
+	content, err := renderer.RenderToBytes("namespace", testConfig)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "kind:")
+
+	testConfig.NamespaceStrategy = config.NamespacePerDeveloper
+	testConfig.Namespace = "devenv-testuser"
+	content, err = renderer.RenderToBytes("namespace", testConfig)
+	require.NoError(t, err)
+	var namespace struct {
+		Kind     string `yaml:"kind"`
+		Metadata struct {
+			Name   string            `yaml:"name"`
+			Labels map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+	}
+	require.NoError(t, yaml.Unmarshal(content, &namespace))
+	assert.Equal(t, "Namespace", namespace.Kind)
+	assert.Equal(t, "devenv-testuser", namespace.Metadata.Name)
+	assert.Equal(t, "testuser", namespace.Metadata.Labels["developer"])
+}
+
+// TestRenderTemplate_IngressOptions verifies that ingress.className,
+// extraHosts and tlsSecret reach the rendered Ingress.
+func TestRenderTemplate_IngressOptions(t *testing.T) {
+	testConfig := &config.DevEnvConfig{
+		Name: "testuser",
+		BaseConfig: config.BaseConfig{
+			SSHPublicKey: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC7... testuser@example.com",
+			Namespace:    "devenv-test",
+			HostName:     "dev.example.com",
+			Ingress: config.IngressConfig{
+				ClassName:  "traefik",
+				ExtraHosts: []string{"{name}.lab.example.com"},
+				TLSSecret:  "lab-wildcard-tls",
+			},
+		},
+		SSHPort:  30001,
+		HTTPPort: 8080,
+	}
+
+	content, err := NewDevRenderer(t.TempDir()).RenderToBytes("ingress", testConfig)
+	require.NoError(t, err)
+
+	var ingress struct {
+		Spec struct {
+			IngressClassName string `yaml:"ingressClassName"`
+			Rules            []struct {
+				Host string `yaml:"host"`
+			} `yaml:"rules"`
+			TLS []struct {
+				Hosts      []string `yaml:"hosts"`
+				SecretName string   `yaml:"secretName"`
+			} `yaml:"tls"`
+		} `yaml:"spec"`
+	}
+	require.NoError(t, yaml.Unmarshal(content, &ingress))
+	assert.Equal(t, "traefik", ingress.Spec.IngressClassName)
+	require.Len(t, ingress.Spec.Rules, 2)
+	assert.Equal(t, "testuser.dev.example.com", ingress.Spec.Rules[0].Host)
+	assert.Equal(t, "testuser.lab.example.com", ingress.Spec.Rules[1].Host)
+	require.Len(t, ingress.Spec.TLS, 1)
+	assert.Equal(t, []string{"*.dev.example.com", "testuser.lab.example.com"}, ingress.Spec.TLS[0].Hosts)
+	assert.Equal(t, "lab-wildcard-tls", ingress.Spec.TLS[0].SecretName)
+}
+
+func TestRenderTemplate_Arch(t *testing.T) {
+	testConfig := &config.DevEnvConfig{
+		Name: "testuser",
+		BaseConfig: config.BaseConfig{
+			SSHPublicKey: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC7... testuser@example.com",
+			Namespace:    "devenv-test",
+			Image:        "ghcr.io/example/devenv:1.4",
+			Arch:         "arm64",
+			NodeSelector: map[string]string{"pool": "general"},
+			ImageVariants: map[string]map[string]string{
+				"ghcr.io/example/devenv:1.4": {"arm64": "ghcr.io/example/devenv:1.4-arm64"},
+			},
+		},
+		SSHPort: 30001,
+	}
+
+	content, err := NewDevRenderer(t.TempDir()).RenderToBytes("statefulset", testConfig)
+	require.NoError(t, err)
+
+	var statefulSet struct {
+		Spec struct {
+			Template struct {
+				Spec struct {
+					NodeSelector map[string]string `yaml:"nodeSelector"`
+					Containers   []struct {
+						Image string `yaml:"image"`
+					} `yaml:"containers"`
+				} `yaml:"spec"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}
+	require.NoError(t, yaml.Unmarshal(content, &statefulSet))
+	podSpec := statefulSet.Spec.Template.Spec
+	assert.Equal(t, map[string]string{"pool": "general", "kubernetes.io/arch": "arm64"}, podSpec.NodeSelector)
+	require.NotEmpty(t, podSpec.Containers)
+	assert.Equal(t, "ghcr.io/example/devenv:1.4-arm64", podSpec.Containers[0].Image)
+}
+
+func TestRenderTemplate_Preemptible(t *testing.T) {
+	testConfig := &config.DevEnvConfig{
+		Name: "testuser",
+		BaseConfig: config.BaseConfig{
+			SSHPublicKey: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC7... testuser@example.com",
+			Namespace:    "devenv-test",
+			Preemptible:  true,
+			PreemptibleNodes: config.PreemptibleNodesConfig{
+				NodeSelector: map[string]string{"cloud.google.com/gke-spot": "true"},
+				Tolerations:  []config.Toleration{{Key: "cloud.google.com/gke-spot", Operator: "Equal", Value: "true", Effect: "NoSchedule"}},
+			},
+		},
+		SSHPort: 30001,
+	}
+	renderer := NewDevRenderer(t.TempDir())
+
+	content, err := renderer.RenderToBytes("statefulset", testConfig)
+	require.NoError(t, err)
+	var statefulSet struct {
+		Spec struct {
+			Template struct {
+				Spec struct {
+					NodeSelector map[string]string   `yaml:"nodeSelector"`
+					Tolerations  []config.Toleration `yaml:"tolerations"`
+					Containers   []struct {
+						Lifecycle struct {
+							PreStop struct {
+								Exec struct {
+									Command []string `yaml:"command"`
+								} `yaml:"exec"`
+							} `yaml:"preStop"`
+						} `yaml:"lifecycle"`
+					} `yaml:"containers"`
+				} `yaml:"spec"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}
+	require.NoError(t, yaml.Unmarshal(content, &statefulSet))
+	podSpec := statefulSet.Spec.Template.Spec
+	assert.Equal(t, map[string]string{"cloud.google.com/gke-spot": "true"}, podSpec.NodeSelector)
+	assert.Equal(t, testConfig.PreemptibleNodes.Tolerations, podSpec.Tolerations)
+	require.NotEmpty(t, podSpec.Containers)
+	assert.Equal(t, []string{"/bin/bash", "/scripts/pre-stop.sh"}, podSpec.Containers[0].Lifecycle.PreStop.Exec.Command)
+
+	content, err = renderer.RenderToBytes("startup-scripts", testConfig)
+	require.NoError(t, err)
+	var configMap struct {
+		Data map[string]string `yaml:"data"`
+	}
+	require.NoError(t, yaml.Unmarshal(content, &configMap))
+	assert.Contains(t, configMap.Data["pre-stop.sh"], `DEV_USERNAME="testuser"`)
+
+	// Without preemptible, neither the hook nor the script is rendered
+	testConfig.Preemptible = false
+	content, err = renderer.RenderToBytes("statefulset", testConfig)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "preStop")
+	content, err = renderer.RenderToBytes("startup-scripts", testConfig)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "pre-stop.sh")
+}
+
+func TestRenderTemplate_GPUType(t *testing.T) {
+	testConfig := &config.DevEnvConfig{
+		Name: "testuser",
+		BaseConfig: config.BaseConfig{
+			SSHPublicKey: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC7... testuser@example.com",
+			Namespace:    "devenv-test",
+			Resources:    config.ResourceConfig{GPU: 2, GPUType: "a100", MIGProfile: "1g.10gb"},
+			GPUTypes: map[string]config.GPUTypeConfig{
+				"a100": {
+					NodeSelector: map[string]string{"nvidia.com/gpu.product": "NVIDIA-A100-SXM4-80GB"},
+					Tolerations:  []config.Toleration{{Key: "nvidia.com/gpu", Operator: "Exists", Effect: "NoSchedule"}},
+					MIGProfiles:  []string{"1g.10gb"},
+				},
+			},
+		},
+		SSHPort: 30001,
+	}
+	renderer := NewDevRenderer(t.TempDir())
+
+	content, err := renderer.RenderToBytes("statefulset", testConfig)
+	require.NoError(t, err)
+	var statefulSet struct {
+		Spec struct {
+			Template struct {
+				Spec struct {
+					NodeSelector map[string]string   `yaml:"nodeSelector"`
+					Tolerations  []config.Toleration `yaml:"tolerations"`
+					Containers   []struct {
+						Resources struct {
+							Limits   map[string]string `yaml:"limits"`
+							Requests map[string]string `yaml:"requests"`
+						} `yaml:"resources"`
+					} `yaml:"containers"`
+				} `yaml:"spec"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}
+	require.NoError(t, yaml.Unmarshal(content, &statefulSet))
+	podSpec := statefulSet.Spec.Template.Spec
+	assert.Equal(t, map[string]string{"nvidia.com/gpu.product": "NVIDIA-A100-SXM4-80GB"}, podSpec.NodeSelector)
+	assert.Equal(t, testConfig.GPUTypes["a100"].Tolerations, podSpec.Tolerations)
+	require.NotEmpty(t, podSpec.Containers)
+	assert.Equal(t, "2", podSpec.Containers[0].Resources.Limits["nvidia.com/mig-1g.10gb"])
+	assert.Equal(t, "2", podSpec.Containers[0].Resources.Requests["nvidia.com/mig-1g.10gb"])
+	assert.NotContains(t, string(content), "nvidia.com/gpu:")
+}
+
+func TestRenderTemplate_Probes(t *testing.T) {
+	testConfig := &config.DevEnvConfig{
+		Name: "testuser",
+		BaseConfig: config.BaseConfig{
+			SSHPublicKey: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC7... testuser@example.com",
+			Namespace:    "devenv-test",
+			Probes: config.ProbesConfig{
+				Liveness:  &config.Probe{Exec: &config.ExecAction{Command: []string{"pgrep", "sshd"}}, PeriodSeconds: 30},
+				Readiness: &config.Probe{HTTPGet: &config.HTTPGetAction{Path: "/healthz", Port: 8080}},
+				Startup:   &config.Probe{TCPSocket: &config.TCPSocketAction{Port: 22}, FailureThreshold: 60},
+			},
+		},
+		SSHPort: 30001,
+	}
+
+	content, err := NewDevRenderer(t.TempDir()).RenderToBytes("statefulset", testConfig)
+	require.NoError(t, err)
+	var statefulSet struct {
+		Spec struct {
+			Template struct {
+				Spec struct {
+					Containers []struct {
+						LivenessProbe  config.Probe `yaml:"livenessProbe"`
+						ReadinessProbe config.Probe `yaml:"readinessProbe"`
+						StartupProbe   config.Probe `yaml:"startupProbe"`
+					} `yaml:"containers"`
+				} `yaml:"spec"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}
+	require.NoError(t, yaml.Unmarshal(content, &statefulSet))
+	require.NotEmpty(t, statefulSet.Spec.Template.Spec.Containers)
+	container := statefulSet.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, *testConfig.Probes.Liveness, container.LivenessProbe)
+	assert.Equal(t, *testConfig.Probes.Readiness, container.ReadinessProbe)
+	assert.Equal(t, *testConfig.Probes.Startup, container.StartupProbe)
+}
+
+func TestRenderTemplate_Lifecycle(t *testing.T) {
+	testConfig := &config.DevEnvConfig{
+		Name: "testuser",
+		BaseConfig: config.BaseConfig{
+			SSHPublicKey: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC7... testuser@example.com",
+			Namespace:    "devenv-test",
+			Lifecycle: config.LifecycleConfig{
+				PostStart: []string{"/bin/sh", "-c", "make warm-cache"},
+				PreStop:   []string{"/bin/sh", "-c", "flush-state --all"},
+			},
+		},
+		SSHPort: 30001,
+	}
+	renderer := NewDevRenderer(t.TempDir())
+
+	type execHook struct {
+		Exec struct {
+			Command []string `yaml:"command"`
+		} `yaml:"exec"`
+	}
+	lifecycle := func() (postStart, preStop []string) {
+		content, err := renderer.RenderToBytes("statefulset", testConfig)
+		require.NoError(t, err)
+		var statefulSet struct {
+			Spec struct {
+				Template struct {
+					Spec struct {
+						Containers []struct {
+							Lifecycle struct {
+								PostStart execHook `yaml:"postStart"`
+								PreStop   execHook `yaml:"preStop"`
+							} `yaml:"lifecycle"`
+						} `yaml:"containers"`
+					} `yaml:"spec"`
+				} `yaml:"template"`
+			} `yaml:"spec"`
+		}
+		require.NoError(t, yaml.Unmarshal(content, &statefulSet))
+		require.NotEmpty(t, statefulSet.Spec.Template.Spec.Containers)
+		container := statefulSet.Spec.Template.Spec.Containers[0]
+		return container.Lifecycle.PostStart.Exec.Command, container.Lifecycle.PreStop.Exec.Command
+	}
+
+	postStart, preStop := lifecycle()
+	assert.Equal(t, testConfig.Lifecycle.PostStart, postStart)
+	assert.Equal(t, testConfig.Lifecycle.PreStop, preStop)
+
+	// Preemptible environments run the command from the pre-stop script
+	testConfig.Preemptible = true
+	_, preStop = lifecycle()
+	assert.Equal(t, []string{"/bin/bash", "/scripts/pre-stop.sh", "/bin/sh", "-c", "flush-state --all"}, preStop)
+	content, err := renderer.RenderToBytes("startup-scripts", testConfig)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `timeout "${CHECKPOINT_TIMEOUT}" "$@"`)
+}
+
+func TestRenderTemplate_Observability(t *testing.T) {
+	testConfig := &config.DevEnvConfig{
+		Name: "testuser",
+		BaseConfig: config.BaseConfig{
+			SSHPublicKey:  "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC7... testuser@example.com",
+			Namespace:     "devenv-test",
+			Observability: config.ObservabilityConfig{MetricsPort: 9100, MetricsPath: "/stats", Mode: config.ScrapePodMonitor},
+		},
+		SSHPort: 30001,
+	}
+	renderer := NewDevRenderer(t.TempDir())
+
+	content, err := renderer.RenderToBytes("monitoring", testConfig)
+	require.NoError(t, err)
+	var monitor struct {
+		Kind string `yaml:"kind"`
+		Spec struct {
+			Selector struct {
+				MatchLabels map[string]string `yaml:"matchLabels"`
+			} `yaml:"selector"`
+			PodMetricsEndpoints []map[string]string `yaml:"podMetricsEndpoints"`
+		} `yaml:"spec"`
+	}
+	require.NoError(t, yaml.Unmarshal(content, &monitor))
+	assert.Equal(t, "PodMonitor", monitor.Kind)
+	assert.Equal(t, map[string]string{"app": "devenv-testuser"}, monitor.Spec.Selector.MatchLabels)
+	assert.Equal(t, []map[string]string{{"port": "metrics", "path": "/stats"}}, monitor.Spec.PodMetricsEndpoints)
+
+	// With annotations, the pod is annotated and no monitor is generated
+	testConfig.Observability.Mode = config.ScrapeAnnotations
+	content, err = renderer.RenderToBytes("monitoring", testConfig)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "#"), "no monitor expected, got:\n%s", content)
+	content, err = renderer.RenderToBytes("statefulset", testConfig)
+	require.NoError(t, err)
+	var statefulSet struct {
+		Spec struct {
+			Template struct {
+				Metadata struct {
+					Annotations map[string]string `yaml:"annotations"`
+				} `yaml:"metadata"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}
+	require.NoError(t, yaml.Unmarshal(content, &statefulSet))
+	assert.Equal(t, map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   "9100",
+		"prometheus.io/path":   "/stats",
+	}, statefulSet.Spec.Template.Metadata.Annotations)
+
+	// Without a metrics port, nothing is scraped
+	testConfig.Observability = config.ObservabilityConfig{}
+	content, err = renderer.RenderToBytes("monitoring", testConfig)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "#"), "no monitor expected, got:\n%s", content)
+	content, err = renderer.RenderToBytes("statefulset", testConfig)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "prometheus.io/")
+	assert.NotContains(t, string(content), "name: metrics")
+}
+
+func TestRenderTemplate_GrafanaDashboard(t *testing.T) {
+	baseConfig := &config.BaseConfig{Namespace: "devenv"}
+	renderer := NewSystemRenderer(t.TempDir())
+
+	content, err := renderer.RenderToBytes("grafana-dashboard", baseConfig)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "#"), "no dashboard expected, got:\n%s", content)
+
+	baseConfig.Observability.GrafanaDashboard = true
+	content, err = renderer.RenderToBytes("grafana-dashboard", baseConfig)
+	require.NoError(t, err)
+	var configMap struct {
+		Metadata struct {
+			Namespace string            `yaml:"namespace"`
+			Labels    map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+		Data map[string]string `yaml:"data"`
+	}
+	require.NoError(t, yaml.Unmarshal(content, &configMap))
+	assert.Equal(t, "devenv", configMap.Metadata.Namespace)
+	assert.Equal(t, "1", configMap.Metadata.Labels["grafana_dashboard"])
+
+	var dashboard struct {
+		UID    string `json:"uid"`
+		Panels []struct {
+			Targets []struct {
+				LegendFormat string `json:"legendFormat"`
+			} `json:"targets"`
+		} `json:"panels"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(configMap.Data["devenv-environments.json"]), &dashboard))
+	assert.Equal(t, "devenv-environments", dashboard.UID)
+	require.NotEmpty(t, dashboard.Panels)
+	assert.Equal(t, "{{pod}}", dashboard.Panels[0].Targets[0].LegendFormat)
+}
+
+func TestRenderTemplate_Logging(t *testing.T) {
+	testConfig := &config.DevEnvConfig{
+		Name: "testuser",
+		BaseConfig: config.BaseConfig{
+			SSHPublicKey: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC7... testuser@example.com",
+			Namespace:    "devenv-test",
+			Resources:    config.ResourceConfig{StorageClass: "fast-ssd"},
+			Logging: config.LoggingConfig{
+				Enabled: true,
+				Sink:    config.LoggingSinkConfig{Type: config.LogSinkElasticsearch, Host: "es.logging.svc"},
+				Labels:  map[string]string{"team": "ml"},
+			},
+		},
+		SSHPort: 30001,
+	}
+	renderer := NewDevRenderer(t.TempDir())
+
+	type statefulSet struct {
+		Spec struct {
+			Template struct {
+				Metadata struct {
+					Labels map[string]string `yaml:"labels"`
+				} `yaml:"metadata"`
+				Spec struct {
+					Containers []struct {
+						Name         string           `yaml:"name"`
+						VolumeMounts []map[string]any `yaml:"volumeMounts"`
+					} `yaml:"containers"`
+				} `yaml:"spec"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}
+
+	content, err := renderer.RenderToBytes("logging", testConfig)
+	require.NoError(t, err)
+	var configMap struct {
+		Data map[string]string `yaml:"data"`
+	}
+	require.NoError(t, yaml.Unmarshal(content, &configMap))
+	fluentBitConf := configMap.Data["fluent-bit.conf"]
+	assert.Contains(t, fluentBitConf, "Path             /home/testuser/logs/*.log\n")
+	assert.Contains(t, fluentBitConf, "Name               es\n")
+	assert.Contains(t, fluentBitConf, "Port               9200\n")
+	assert.Contains(t, fluentBitConf, "Index              devenv-logs\n")
+	assert.NotContains(t, fluentBitConf, "tls")
+
+	// The sidecar reads the home directory from the claim's homedir subPath
+	content, err = renderer.RenderToBytes("statefulset", testConfig)
+	require.NoError(t, err)
+	var withSidecar statefulSet
+	require.NoError(t, yaml.Unmarshal(content, &withSidecar))
+	containers := withSidecar.Spec.Template.Spec.Containers
+	require.Len(t, containers, 2)
+	assert.Equal(t, "log-shipper", containers[1].Name)
+	assert.Equal(t, map[string]any{"name": "dev-storage", "mountPath": "/home/testuser", "subPath": "homedir", "readOnly": true},
+		containers[1].VolumeMounts[0])
+	assert.Equal(t, "ml", withSidecar.Spec.Template.Metadata.Labels["team"])
+
+	// Without a sink, only the pod is labeled for the cluster's log agent
+	testConfig.Logging.Sink = config.LoggingSinkConfig{}
+	content, err = renderer.RenderToBytes("logging", testConfig)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "#"), "no sidecar config expected, got:\n%s", content)
+	content, err = renderer.RenderToBytes("statefulset", testConfig)
+	require.NoError(t, err)
+	var withoutSidecar statefulSet
+	require.NoError(t, yaml.Unmarshal(content, &withoutSidecar))
+	assert.Len(t, withoutSidecar.Spec.Template.Spec.Containers, 1)
+	assert.Equal(t, "ml", withoutSidecar.Spec.Template.Metadata.Labels["team"])
+	assert.NotContains(t, string(content), "logging-config")
+
+	// Disabled logging leaves the pod alone
+	testConfig.Logging.Enabled = false
+	content, err = renderer.RenderToBytes("statefulset", testConfig)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "team:")
+}
+
+// TestRenderTemplate_ExtraAnnotations verifies that configured annotations
+// are rendered onto every Service and the Ingress as valid YAML.
+func TestRenderTemplate_ExtraAnnotations(t *testing.T) {
+	testConfig := &config.DevEnvConfig{
+		Name: "testuser",
+		BaseConfig: config.BaseConfig{
+			SSHPublicKey: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC7... testuser@example.com",
+			Namespace:    "devenv-test",
+			HostName:     "dev.example.com",
+			Annotations: config.AnnotationsConfig{
+				Service: map[string]string{"example.com/owner": "platform"},
+				Ingress: map[string]string{
+					"nginx.ingress.kubernetes.io/limit-rps":             "10",
+					"nginx.ingress.kubernetes.io/configuration-snippet": "more_set_headers \"X-Env: dev\";\n",
+				},
+			},
+		},
+		SSHPort:  30001,
+		HTTPPort: 8080,
+	}
+	renderer := NewDevRenderer(t.TempDir())
+
+	type object struct {
+		Metadata struct {
+			Annotations map[string]string `yaml:"annotations"`
+		} `yaml:"metadata"`
+	}
+	decodeAll := func(content []byte) []object {
+		var objects []object
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		for {
+			var obj object
+			if err := decoder.Decode(&obj); err != nil {
+				require.ErrorIs(t, err, io.EOF)
+				return objects
+			}
+			objects = append(objects, obj)
+		}
+	}
+
+	content, err := renderer.RenderToBytes("service", testConfig)
+	require.NoError(t, err)
+	services := decodeAll(content)
+	require.Len(t, services, 3)
+	for _, svc := range services {
+		assert.Equal(t, "platform", svc.Metadata.Annotations["example.com/owner"])
+	}
+
+	content, err = renderer.RenderToBytes("ingress", testConfig)
+	require.NoError(t, err)
+	ingress := decodeAll(content)
+	require.Len(t, ingress, 1)
+	annotations := ingress[0].Metadata.Annotations
+	assert.Equal(t, "10", annotations["nginx.ingress.kubernetes.io/limit-rps"])
+	assert.Equal(t, "more_set_headers \"X-Env: dev\";\n", annotations["nginx.ingress.kubernetes.io/configuration-snippet"])
+	assert.Equal(t, "true", annotations["nginx.ingress.kubernetes.io/force-ssl-redirect"])
+}
+
+// TestRenderTemplate_VolumeClaims verifies that the home directory and sized
+// volumes are backed by generated PersistentVolumeClaims.
+func TestRenderTemplate_VolumeClaims(t *testing.T) {
+	testConfig := &config.DevEnvConfig{
+		Name: "testuser",
+		BaseConfig: config.BaseConfig{
+			SSHPublicKey: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC7... testuser@example.com",
+			Namespace:    "devenv-test",
+			Resources:    config.ResourceConfig{Storage: "100", StorageClass: "fast-ssd"},
+			Volumes: []config.VolumeMount{
+				{Name: "datasets", ContainerPath: "/datasets", Size: "1Ti", StorageClass: "bulk"},
+				{Name: "scratch", ContainerPath: "/scratch", Size: "50Gi"},
+				{Name: "shared", LocalPath: "/mnt/shared", ContainerPath: "/shared"},
+			},
+		},
+		SSHPort: 30001,
+	}
+	renderer := NewDevRenderer(t.TempDir())
+
+	type claim struct {
+		Metadata struct {
+			Name   string            `yaml:"name"`
+			Labels map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+		Spec struct {
+			AccessModes      []string `yaml:"accessModes"`
+			StorageClassName string   `yaml:"storageClassName"`
+			Resources        struct {
+				Requests map[string]string `yaml:"requests"`
+			} `yaml:"resources"`
+		} `yaml:"spec"`
+	}
+	renderClaims := func() []claim {
+		content, err := renderer.RenderToBytes("pvc", testConfig)
+		require.NoError(t, err)
+		var claims []claim
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		for {
+			var c claim
+			if err := decoder.Decode(&c); err != nil {
+				require.ErrorIs(t, err, io.EOF)
+				break
+			}
+			claims = append(claims, c)
+		}
+		return claims
+	}
+	claims := renderClaims()
+	require.Len(t, claims, 3)
+	assert.Equal(t, []string{"ReadWriteOnce"}, claims[0].Spec.AccessModes)
+	assert.Equal(t, "devenv-home-testuser", claims[0].Metadata.Name)
+	assert.Equal(t, "fast-ssd", claims[0].Spec.StorageClassName)
+	assert.Equal(t, "100Gi", claims[0].Spec.Resources.Requests["storage"])
+	assert.Equal(t, "devenv-testuser-datasets", claims[1].Metadata.Name)
+	assert.Equal(t, "bulk", claims[1].Spec.StorageClassName)
+	assert.Equal(t, "fast-ssd", claims[2].Spec.StorageClassName, "volumes default to resources.storageClass")
+
+	// The access mode applies to every claim
+	testConfig.Resources.StorageAccessMode = config.AccessModeReadWriteMany
+	for _, c := range renderClaims() {
+		assert.Equal(t, []string{"ReadWriteMany"}, c.Spec.AccessModes, c.Metadata.Name)
+	}
+
+	// Named environments share the developer's claims, labeled like the
+	// environment's other objects
+	testConfig.Environment = "gpu"
+	for _, c := range renderClaims() {
+		assert.Equal(t, map[string]string{"app": "devenv-testuser-gpu", "developer": "testuser"}, c.Metadata.Labels, c.Metadata.Name)
+	}
+	assert.Equal(t, "devenv-home-testuser", renderClaims()[0].Metadata.Name)
+	testConfig.Environment = ""
+
+	content, err := renderer.RenderToBytes("statefulset", testConfig)
+	require.NoError(t, err)
+	statefulSet := string(content)
+	assert.Contains(t, statefulSet, "claimName: devenv-home-testuser\n")
+	assert.Contains(t, statefulSet, "subPath: linuxbrew\n")
+	assert.Contains(t, statefulSet, "claimName: devenv-testuser-scratch\n")
+	assert.Contains(t, statefulSet, "path: /mnt/shared\n")
+	assert.NotContains(t, statefulSet, "/mnt/devenv/testuser")
+}
+
+// TestRenderAllTo_Header verifies that a header is prepended to every
+// rendered manifest.
+func TestRenderAllTo_Header(t *testing.T) {
+	renderer := NewSystemRenderer("")
+	renderer.SetHeader([]byte("# Generated by devenv test\n"))
+	assert.Equal(t, []string{"namespace.yaml", "grafana-dashboard.yaml"}, renderer.Filenames())
+
+	rendered := map[string]string{}
+	err := renderer.RenderAllTo(&config.BaseConfig{Namespace: "devenv"}, func(filename string, content []byte) error {
+		rendered[filename] = string(content)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rendered["namespace.yaml"], "# Generated by devenv test\napiVersion: v1\n"))
+}
+
+// TestRenderAllTo_AggregatesErrors verifies that every failing template is
+// reported and that keep-going still writes the templates that rendered.
+func TestRenderAllTo_AggregatesErrors(t *testing.T) {
+	renderer := NewRendererWithFS[config.BaseConfig]("", "template_files/system", []string{"missing-a", "namespace", "missing-b"})
+	cfg := &config.BaseConfig{Namespace: "devenv"}
+
+	var written []string
+	write := func(filename string, content []byte) error {
+		written = append(written, filename)
+		return nil
+	}
+
+	err := renderer.RenderAllTo(cfg, write)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read template missing-a")
+	assert.Contains(t, err.Error(), "failed to read template missing-b")
+	assert.Empty(t, written, "nothing is written when a template fails")
+
+	renderer.SetKeepGoing(true)
+	err = renderer.RenderAllTo(cfg, write)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read template missing-a")
+	assert.Contains(t, err.Error(), "failed to read template missing-b")
+	assert.Equal(t, []string{"namespace.yaml"}, written)
+}
+
+// TestErrorPosition verifies that template error positions are extracted
+// for the named template only.
+func TestErrorPosition(t *testing.T) {
+	tmpl := template.Must(template.New("statefulset").Parse("kind: StatefulSet\nname: {{ .Missing }}\n"))
+	err := tmpl.Execute(io.Discard, struct{}{})
+	require.Error(t, err)
+
+	line, column, ok := ErrorPosition(err, "statefulset")
+	require.True(t, ok)
+	assert.Equal(t, 2, line)
+	assert.Equal(t, 9, column)
+
+	_, err = template.New("service").Parse("kind: Service\n\n{{ if }}\n")
+	require.Error(t, err)
+	line, column, ok = ErrorPosition(err, "service")
+	require.True(t, ok)
+	assert.Equal(t, 3, line)
+	assert.Equal(t, -1, column, "parse errors have no column")
+
+	_, _, ok = ErrorPosition(err, "statefulset")
+	assert.False(t, ok)
+}
+
+// TestRenderer_SetFS verifies that templates are read from an override
+// filesystem with the embedded layout.
+func TestRenderer_SetFS(t *testing.T) {
+	files := fstest.MapFS{
+		"template_files/system/manifests/namespace.tmpl": {Data: []byte("name: {{ .Namespace }}\n")},
+	}
+	renderer := NewSystemRenderer("")
+	renderer.SetFS(files)
+
+	content, err := renderer.RenderToBytes("namespace", &config.BaseConfig{Namespace: "devenv"})
+	require.NoError(t, err)
+	assert.Equal(t, "name: devenv\n", string(content))
+
+	// Edits are picked up by the next render
+	files["template_files/system/manifests/namespace.tmpl"] = &fstest.MapFile{Data: []byte("namespace: {{ .Namespace }}\n")}
+	content, err = renderer.RenderToBytes("namespace", &config.BaseConfig{Namespace: "devenv"})
+	require.NoError(t, err)
+	assert.Equal(t, "namespace: devenv\n", string(content))
+}
+
+// TestRenderer_SetLookup verifies that templates read data source values
+// through the renderer's lookup function.
+func TestRenderer_SetLookup(t *testing.T) {
+	files := fstest.MapFS{
+		"template_files/system/manifests/namespace.tmpl": {Data: []byte(`costCenter: {{ lookup "cmdb" .Namespace }}` + "\n")},
+	}
+	renderer := NewSystemRenderer("")
+	renderer.SetFS(files)
+
+	_, err := renderer.RenderToBytes("namespace", &config.BaseConfig{Namespace: "devenv"})
+	assert.ErrorContains(t, err, `unknown data source "cmdb"`)
+
+	renderer.SetLookup(func(source, key string) (any, error) {
+		return source + "-" + key, nil
+	})
+	content, err := renderer.RenderToBytes("namespace", &config.BaseConfig{Namespace: "devenv"})
+	require.NoError(t, err)
+	assert.Equal(t, "costCenter: cmdb-devenv\n", string(content))
+}
+
+// TestRenderer_SetValidate verifies that a failed check keeps every
+// manifest from being written, unless keep-going is set.
+func TestRenderer_SetValidate(t *testing.T) {
+	testConfig := &config.DevEnvConfig{
+		Name:       "testuser",
+		BaseConfig: config.BaseConfig{Namespace: "devenv-test"},
+		SSHPort:    30001,
+	}
+	renderer := NewDevRenderer("")
+	renderer.SetValidate(func(filename string, content []byte) error {
+		if filename == "service.yaml" {
+			return errors.New(filename + ":3: invalid")
+		}
+		return nil
+	})
+
+	var written []string
+	write := func(filename string, content []byte) error {
+		written = append(written, filename)
+		return nil
+	}
+	err := renderer.RenderAllTo(testConfig, write)
+	assert.EqualError(t, err, "service.yaml:3: invalid")
+	assert.Empty(t, written)
+
+	renderer.SetKeepGoing(true)
+	err = renderer.RenderAllTo(testConfig, write)
+	assert.EqualError(t, err, "service.yaml:3: invalid")
+	assert.Contains(t, written, "statefulset.yaml")
+	assert.NotContains(t, written, "service.yaml")
+}
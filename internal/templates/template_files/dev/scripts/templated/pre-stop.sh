@@ -0,0 +1,28 @@
+#!/bin/bash
+# Pre-stop hook for developer environment: {{.Name}}
+# Runs when the pod is stopped, e.g., because its preemptible node is being
+# reclaimed. Kubernetes kills the container once the grace period ends, so
+# everything here must finish quickly.
+
+DEV_USERNAME="{{.Name}}"
+DEVENV_DIR="/home/${DEV_USERNAME}/.devenv"
+# Developer checkpoint hook, e.g. saving model checkpoints or pushing work in progress
+CHECKPOINT_HOOK="${DEVENV_DIR}/pre-stop.sh"
+CHECKPOINT_TIMEOUT=20
+
+echo "devenv: this environment is stopping (node reclaimed or pod deleted); save your work now" | wall 2>/dev/null || true
+
+# 'lifecycle.preStop' command, passed as arguments by the preStop hook
+if [ "$#" -gt 0 ]; then
+    echo "Running lifecycle.preStop command (up to ${CHECKPOINT_TIMEOUT}s)"
+    timeout "${CHECKPOINT_TIMEOUT}" "$@" || echo "lifecycle.preStop command failed or timed out"
+fi
+
+if [ -x "${CHECKPOINT_HOOK}" ]; then
+    echo "Running ${CHECKPOINT_HOOK} (up to ${CHECKPOINT_TIMEOUT}s)"
+    timeout "${CHECKPOINT_TIMEOUT}" sudo -u "${DEV_USERNAME}" -H "${CHECKPOINT_HOOK}" || echo "${CHECKPOINT_HOOK} failed or timed out"
+fi
+
+# Record the stop so the next session can tell state may be incomplete
+sudo -u "${DEV_USERNAME}" mkdir -p "${DEVENV_DIR}" && date -u +%Y-%m-%dT%H:%M:%SZ | sudo -u "${DEV_USERNAME}" tee "${DEVENV_DIR}/last-stop" >/dev/null
+sync
@@ -0,0 +1,192 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/nauticalab/devenv-engine/internal/templates"
+)
+
+// skippedRules are reported by PortValidator with NodePort-specific
+// guidance (sshPort range) or by NameValidator across developers
+// (environment names), so ConfigValidator skips them to avoid duplicate
+// findings. Keys are lowercase since rule IDs match case-insensitively.
+var skippedRules = map[string]bool{
+	"sshport:min":                true,
+	"sshport:max":                true,
+	"name:environment_collision": true,
+}
+
+// ConfigValidator runs the full config pipeline (load, merge with devenv.yaml,
+// schema and semantic checks) for developer configurations, then lints the
+// templates against each merged config.
+type ConfigValidator struct {
+	configDir string
+}
+
+// NewConfigValidator creates a new config validator
+func NewConfigValidator(configDir string) *ConfigValidator {
+	return &ConfigValidator{configDir: configDir}
+}
+
+// ValidateAll validates the global config and every developer config.
+func (cv *ConfigValidator) ValidateAll() (*ValidationResult, error) {
+	developers, err := config.ListDeveloperDirs(cv.configDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan developer directories in %s: %w", cv.configDir, err)
+	}
+	return cv.validate(developers)
+}
+
+// ValidateSingle validates the global config and one developer config.
+func (cv *ConfigValidator) ValidateSingle(developerName string) (*ValidationResult, error) {
+	return cv.validate([]string{developerName})
+}
+
+func (cv *ConfigValidator) validate(developers []string) (*ValidationResult, error) {
+	result := &ValidationResult{
+		Errors:   []ValidationError{},
+		Warnings: []ValidationWarning{},
+		IsValid:  true,
+	}
+
+	globalPath := filepath.Join(cv.configDir, "devenv.yaml")
+	globalConfig, err := config.LoadGlobalConfig(cv.configDir)
+	if err != nil {
+		result.Errors = append(result.Errors, ValidationError{
+			Type:     "invalid",
+			Message:  fmt.Sprintf("Failed to load global config: %v", err),
+			FilePath: globalPath,
+		})
+		result.IsValid = false
+		return result, nil
+	}
+	cv.addIssues(result, config.CheckBaseConfig(globalConfig).Issues, "", globalPath)
+
+	for _, developerName := range developers {
+		configPath := filepath.Join(cv.configDir, developerName, config.DeveloperConfigFile)
+
+		devConfig, report, err := config.CheckDeveloperConfig(cv.configDir, developerName, globalConfig)
+		if err != nil {
+			result.Errors = append(result.Errors, ValidationError{
+				Type:     "invalid",
+				Users:    []string{developerName},
+				Message:  fmt.Sprintf("Failed to load config: %v", err),
+				FilePath: configPath,
+			})
+			result.IsValid = false
+			continue
+		}
+		cv.addIssues(result, report.Issues, developerName, configPath)
+
+		lintIssues, err := templates.NewDevRenderer("").Lint(devConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lint templates for %s: %w", developerName, err)
+		}
+		cv.addLintIssues(result, lintIssues, globalConfig.Validation, developerName, configPath)
+
+		cv.validateEnvironments(result, developerName, globalConfig, report.Issues)
+	}
+
+	return result, nil
+}
+
+// addLintIssues reports template lint findings as warnings; they never make
+// a config invalid. Rules listed in validation.disabledRules are skipped.
+func (cv *ConfigValidator) addLintIssues(result *ValidationResult, issues []templates.LintIssue, settings config.ValidationConfig, developerName, filePath string) {
+	for _, issue := range issues {
+		if settings.IsDisabled(issue.Rule) {
+			continue
+		}
+		result.Warnings = append(result.Warnings, ValidationWarning{
+			Type:     "template",
+			Rule:     issue.Rule,
+			User:     developerName,
+			Message:  issue.Message,
+			FilePath: filePath,
+		})
+	}
+}
+
+// validateEnvironments checks each named environment of a developer with the
+// same pipeline generate --env uses. Findings are attributed to the developer
+// and point at the environment file; developerIssues, already reported for
+// devenv-config.yaml, are not repeated.
+func (cv *ConfigValidator) validateEnvironments(result *ValidationResult, developerName string, globalConfig *config.BaseConfig, developerIssues []config.ValidationIssue) {
+	inherited := make(map[config.ValidationIssue]bool, len(developerIssues))
+	for _, issue := range developerIssues {
+		inherited[issue] = true
+	}
+
+	environments, err := config.ListEnvironments(cv.configDir, developerName)
+	if err != nil {
+		result.Errors = append(result.Errors, ValidationError{
+			Type:     "invalid",
+			Users:    []string{developerName},
+			Message:  fmt.Sprintf("Failed to list environments: %v", err),
+			FilePath: filepath.Join(cv.configDir, developerName, config.EnvironmentsDir),
+		})
+		result.IsValid = false
+		return
+	}
+
+	for _, environment := range environments {
+		envPath := config.EnvironmentConfigPath(cv.configDir, developerName, environment)
+		_, report, err := config.CheckDeveloperEnvironment(cv.configDir, developerName, environment, globalConfig)
+		if err != nil {
+			result.Errors = append(result.Errors, ValidationError{
+				Type:     "invalid",
+				Users:    []string{developerName},
+				Message:  fmt.Sprintf("Failed to load environment %s: %v", environment, err),
+				FilePath: envPath,
+			})
+			result.IsValid = false
+			continue
+		}
+
+		var issues []config.ValidationIssue
+		for _, issue := range report.Issues {
+			if inherited[issue] {
+				continue
+			}
+			issue.Message = fmt.Sprintf("environment %s: %s", environment, issue.Message)
+			issues = append(issues, issue)
+		}
+		cv.addIssues(result, issues, developerName, envPath)
+	}
+}
+
+// addIssues converts config validation issues into result entries. An empty
+// developerName marks issues in the global config.
+func (cv *ConfigValidator) addIssues(result *ValidationResult, issues []config.ValidationIssue, developerName, filePath string) {
+	for _, issue := range issues {
+		if skippedRules[strings.ToLower(issue.Rule)] {
+			continue
+		}
+		if issue.Severity == config.SeverityWarning {
+			result.Warnings = append(result.Warnings, ValidationWarning{
+				Type:     "config",
+				Rule:     issue.Rule,
+				User:     developerName,
+				Message:  issue.Message,
+				FilePath: filePath,
+			})
+			continue
+		}
+
+		var users []string
+		if developerName != "" {
+			users = []string{developerName}
+		}
+		result.Errors = append(result.Errors, ValidationError{
+			Type:     "config",
+			Rule:     issue.Rule,
+			Users:    users,
+			Message:  issue.Message,
+			FilePath: filePath,
+		})
+		result.IsValid = false
+	}
+}
@@ -0,0 +1,111 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nauticalab/devenv-engine/internal/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigDir writes devenv.yaml and one config file per path, relative
+// to the returned config directory.
+func writeConfigDir(t *testing.T, globalYAML string, files map[string]string) string {
+	t.Helper()
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "devenv.yaml"), []byte(globalYAML), 0o644))
+	for path, content := range files {
+		path = filepath.Join(configDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return configDir
+}
+
+func TestConfigValidator_ValidateSingle(t *testing.T) {
+	configDir := writeConfigDir(t, "", map[string]string{
+		// sshPort is out of range, git.name and git.email are unset, and
+		// extraValues.team is not used by any template
+		"alice/devenv-config.yaml": `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+sshPort: 80
+extraValues:
+  team: ml
+`,
+		"alice/environments/gpu.yaml": "sshPort: 30002\nresources:\n  cpu: lots\n",
+	})
+	configPath := filepath.Join(configDir, "alice", "devenv-config.yaml")
+	envPath := filepath.Join(configDir, "alice", "environments", "gpu.yaml")
+
+	result, err := NewConfigValidator(configDir).ValidateSingle("alice")
+	require.NoError(t, err)
+	assert.False(t, result.IsValid)
+
+	// The port range is left to PortValidator, and the developer's issues
+	// are not repeated for the environment
+	var rules []string
+	for _, err := range result.Errors {
+		rules = append(rules, err.Rule)
+		assert.Equal(t, []string{"alice"}, err.Users)
+		assert.Equal(t, envPath, err.FilePath)
+		assert.Contains(t, err.Message, "environment gpu: ")
+	}
+	assert.ElementsMatch(t, []string{"resources.cpu:k8s_cpu", "resources.cpu:quantity"}, rules)
+
+	warnings := make(map[string]ValidationWarning)
+	for _, warning := range result.Warnings {
+		assert.NotContains(t, warnings, warning.Rule, "%s is reported twice", warning.Rule)
+		warnings[warning.Rule] = warning
+	}
+	assert.Equal(t, configPath, warnings["git.email:recommended"].FilePath)
+	assert.Equal(t, "config", warnings["git.email:recommended"].Type)
+
+	// Template lint findings are warnings
+	unused := warnings[templates.RuleUnusedExtra]
+	assert.Equal(t, "template", unused.Type)
+	assert.Equal(t, "alice", unused.User)
+	assert.Equal(t, "extraValues.team is not referenced by any template", unused.Message)
+}
+
+func TestConfigValidator_DisabledLintRules(t *testing.T) {
+	configDir := writeConfigDir(t, "validation:\n  disabledRules: [extraValues:unused]\n", map[string]string{
+		"alice/devenv-config.yaml": `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+sshPort: 30001
+extraValues:
+  team: ml
+`,
+	})
+
+	result, err := NewConfigValidator(configDir).ValidateAll()
+	require.NoError(t, err)
+	assert.True(t, result.IsValid)
+	for _, warning := range result.Warnings {
+		assert.NotEqual(t, templates.RuleUnusedExtra, warning.Rule)
+	}
+}
+
+func TestConfigValidator_EnvironmentNameCollision(t *testing.T) {
+	configDir := writeConfigDir(t, "", map[string]string{
+		"alice/devenv-config.yaml": `name: alice
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+sshPort: 30001
+`,
+		"alice/environments/gpu.yaml": "sshPort: 30002\n",
+		"alice-gpu/devenv-config.yaml": `name: alice-gpu
+sshPublicKey: "ssh-rsa AAAAB3NzaC1yc2E alice@example.com"
+sshPort: 30003
+`,
+	})
+
+	// The collision is reported once, by NameValidator
+	result, err := NewConfigValidator(configDir).ValidateAll()
+	require.NoError(t, err)
+	assert.True(t, result.IsValid)
+
+	result, err = NewNameValidator(configDir).ValidateAll()
+	require.NoError(t, err)
+	assert.False(t, result.IsValid)
+}
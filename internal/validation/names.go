@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+)
+
+// NameValidator detects developer names that would produce conflicting
+// Kubernetes resource names across developer configurations, including the
+// "<developer>-<environment>" names of named environments.
+type NameValidator struct {
+	configDir string
+}
+
+// NewNameValidator creates a new name validator
+func NewNameValidator(configDir string) *NameValidator {
+	return &NameValidator{configDir: configDir}
+}
+
+// ValidateAll scans all developer configs and their environments and
+// reports names that collide case-insensitively (e.g., "Alice" and "alice",
+// two directories that declare the same name, or developer "alice-gpu" and
+// alice's environment "gpu").
+func (nv *NameValidator) ValidateAll() (*ValidationResult, error) {
+	result := &ValidationResult{
+		Errors:   []ValidationError{},
+		Warnings: []ValidationWarning{},
+		IsValid:  true,
+	}
+
+	// Unparseable configs are left out and reported by the config validator
+	instances, err := config.ListInstances(nv.configDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan developer directories in %s: %w", nv.configDir, err)
+	}
+
+	instancesByKey := make(map[string][]config.Instance) // canonical name -> instances
+	for _, instance := range instances {
+		key := config.CanonicalDeveloperName(instance.Name)
+		instancesByKey[key] = append(instancesByKey[key], instance)
+	}
+
+	keys := make([]string, 0, len(instancesByKey))
+	for key := range instancesByKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		instances := instancesByKey[key]
+		if len(instances) < 2 {
+			continue
+		}
+		var users, described []string
+		for _, instance := range instances {
+			if !slices.Contains(users, instance.Developer) {
+				users = append(users, instance.Developer)
+			}
+			if instance.Environment == "" {
+				described = append(described, fmt.Sprintf("%s (name: %q)", instance.Developer, instance.Name))
+			} else {
+				described = append(described, instance.String())
+			}
+		}
+		result.Errors = append(result.Errors, ValidationError{
+			Type:    "name_collision",
+			Users:   users,
+			Message: fmt.Sprintf("Name %q is used by multiple configurations: %s", key, strings.Join(described, ", ")),
+		})
+		result.IsValid = false
+	}
+
+	return result, nil
+}
+
+// ValidateSingle reports collisions that involve the specified developer.
+func (nv *NameValidator) ValidateSingle(developerName string) (*ValidationResult, error) {
+	fullResult, err := nv.ValidateAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ValidationResult{
+		Errors:   []ValidationError{},
+		Warnings: []ValidationWarning{},
+		IsValid:  true,
+	}
+	for _, err := range fullResult.Errors {
+		for _, user := range err.Users {
+			if user == developerName {
+				result.Errors = append(result.Errors, err)
+				result.IsValid = false
+				break
+			}
+		}
+	}
+	return result, nil
+}
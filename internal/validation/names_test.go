@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameValidator_ValidateAll(t *testing.T) {
+	configDir := writeConfigDir(t, "", map[string]string{
+		"alice/devenv-config.yaml":       "name: alice\n",
+		"alice/environments/gpu.yaml":    "sshPort: 30011\n",
+		"alice/environments/gpu-x.yaml":  "sshPort: 30012\n",
+		"alice-gpu/devenv-config.yaml":   "name: Alice-GPU\n",
+		"alice-gpu/environments/x.yaml":  "sshPort: 30021\n",
+		"bob/devenv-config.yaml":         "name: bob\n",
+		"bob2/devenv-config.yaml":        "name: Bob\n",
+		"carol/devenv-config.yaml":       "name: carol\n",
+		"carol/environments/train.yaml":  "sshPort: 30031\n",
+		"broken/devenv-config.yaml":      "name: [unterminated\n",
+		"broken/environments/stuck.yaml": "sshPort: 30041\n",
+	})
+
+	result, err := NewNameValidator(configDir).ValidateAll()
+	require.NoError(t, err)
+	assert.False(t, result.IsValid)
+	assert.Equal(t, []ValidationError{
+		{
+			Type:    "name_collision",
+			Users:   []string{"alice", "alice-gpu"},
+			Message: `Name "alice-gpu" is used by multiple configurations: alice (environment gpu), alice-gpu (name: "Alice-GPU")`,
+		},
+		{
+			Type:    "name_collision",
+			Users:   []string{"alice", "alice-gpu"},
+			Message: `Name "alice-gpu-x" is used by multiple configurations: alice (environment gpu-x), alice-gpu (environment x)`,
+		},
+		{
+			Type:    "name_collision",
+			Users:   []string{"bob", "bob2"},
+			Message: `Name "bob" is used by multiple configurations: bob (name: "bob"), bob2 (name: "Bob")`,
+		},
+	}, result.Errors)
+}
+
+func TestNameValidator_ValidateSingle(t *testing.T) {
+	configDir := writeConfigDir(t, "", map[string]string{
+		"alice/devenv-config.yaml":     "name: alice\n",
+		"alice/environments/gpu.yaml":  "sshPort: 30011\n",
+		"alice-gpu/devenv-config.yaml": "name: alice-gpu\n",
+		"carol/devenv-config.yaml":     "name: carol\n",
+	})
+	validator := NewNameValidator(configDir)
+
+	result, err := validator.ValidateSingle("alice-gpu")
+	require.NoError(t, err)
+	assert.False(t, result.IsValid)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, []string{"alice", "alice-gpu"}, result.Errors[0].Users)
+
+	result, err = validator.ValidateSingle("carol")
+	require.NoError(t, err)
+	assert.True(t, result.IsValid)
+}
@@ -2,8 +2,8 @@ package validation
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/nauticalab/devenv-engine/internal/config"
@@ -22,26 +22,38 @@ type PortValidator struct {
 
 // ValidationResult contains all validation results
 type ValidationResult struct {
-	Errors   []ValidationError
-	Warnings []ValidationWarning
-	IsValid  bool
+	Errors   []ValidationError   `json:"errors"`
+	Warnings []ValidationWarning `json:"warnings"`
+	IsValid  bool                `json:"valid"`
+}
+
+// Merge appends the errors and warnings of other into r.
+func (r *ValidationResult) Merge(other *ValidationResult) {
+	if other == nil {
+		return
+	}
+	r.Errors = append(r.Errors, other.Errors...)
+	r.Warnings = append(r.Warnings, other.Warnings...)
+	r.IsValid = r.IsValid && other.IsValid
 }
 
 // ValidationError represents a validation failure
 type ValidationError struct {
-	Type     string // "conflict", "out_of_range", "invalid"
-	Port     int
-	Users    []string
-	Message  string
-	FilePath string
+	Type     string   `json:"type"`           // "conflict", "reserved", "out_of_range", "invalid", "config", "name_collision"
+	Rule     string   `json:"rule,omitempty"` // Config rule ID for "config" errors (see config.ValidationIssue)
+	Port     int      `json:"port,omitempty"`
+	Users    []string `json:"users,omitempty"`
+	Message  string   `json:"message"`
+	FilePath string   `json:"filePath,omitempty"`
 }
 
 // ValidationWarning represents a non-fatal validation issue
 type ValidationWarning struct {
-	Type     string
-	User     string
-	Message  string
-	FilePath string
+	Type     string `json:"type"`
+	Rule     string `json:"rule,omitempty"` // Config rule ID for "config" warnings
+	User     string `json:"user,omitempty"`
+	Message  string `json:"message"`
+	FilePath string `json:"filePath,omitempty"`
 }
 
 // NewPortValidator creates a new port validator
@@ -49,7 +61,8 @@ func NewPortValidator(configDir string) *PortValidator {
 	return &PortValidator{configDir: configDir}
 }
 
-// ValidateAll scans all developer configs and validates SSH ports
+// ValidateAll scans all developer configs and validates the SSH ports of
+// developers and their named environments
 func (pv *PortValidator) ValidateAll() (*ValidationResult, error) {
 	result := &ValidationResult{
 		Errors:   []ValidationError{},
@@ -57,12 +70,12 @@ func (pv *PortValidator) ValidateAll() (*ValidationResult, error) {
 		IsValid:  true,
 	}
 
-	// Find all developer configuration directories
-	developers, err := pv.findDeveloperDirs()
+	// Index all developer configurations; only name and port fields are decoded
+	index, err := config.IndexDevelopers(pv.configDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan developer directories in %s: %w", pv.configDir, err)
 	}
-	if len(developers) == 0 {
+	if len(index) == 0 {
 		result.Warnings = append(result.Warnings, ValidationWarning{
 			Type:    "no_configs",
 			Message: fmt.Sprintf("No developer configurations found in %s", pv.configDir),
@@ -70,135 +83,265 @@ func (pv *PortValidator) ValidateAll() (*ValidationResult, error) {
 		return result, nil
 	}
 
-	// Load all configurations and collect port assignments
-	portAssignments := make(map[int][]string) // port -> []users
-	for _, developerName := range developers {
-		port, validationError, validationWarning := pv.validateSingleDeveloper(developerName)
+	for _, entry := range index {
+		if entry.Err != nil {
+			// Unparseable configs are reported by the config validator
+			continue
+		}
+		_, validationError, validationWarning := pv.validateSingleDeveloper(entry)
 		if validationError != nil {
 			result.Errors = append(result.Errors, *validationError)
 			result.IsValid = false
-			continue
 		}
 		if validationWarning != nil {
 			result.Warnings = append(result.Warnings, *validationWarning)
-			continue
 		}
+	}
+
+	if err := pv.addSharedPortIssues(result, ""); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
 
-		// Track port assignments for conflict detection
-		portAssignments[port] = append(portAssignments[port], developerName)
+// addSharedPortIssues reports SSH ports used by more than one developer or
+// environment and ports reserved in devenv.yaml. When developer is set,
+// only issues involving that developer are added. Ports outside the
+// NodePort range are left to the range checks.
+func (pv *PortValidator) addSharedPortIssues(result *ValidationResult, developer string) error {
+	assignments, err := ListSSHPorts(pv.configDir)
+	if err != nil {
+		return err
 	}
+	reserved := pv.reservedPorts()
 
-	// Check for port conflicts
-	for port, users := range portAssignments {
-		if len(users) > 1 {
+	byPort := make(map[int][]SSHPortAssignment)
+	var ports []int
+	for _, assignment := range assignments {
+		if assignment.Port < NodePortMin || assignment.Port > NodePortMax {
+			continue
+		}
+		if reserved[assignment.Port] && (developer == "" || assignment.Developer == developer) {
 			result.Errors = append(result.Errors, ValidationError{
-				Type:    "conflict",
-				Port:    port,
-				Users:   users,
-				Message: fmt.Sprintf("Port %d is assigned to multiple developers: %s", port, strings.Join(users, ", ")),
+				Type:    "reserved",
+				Port:    assignment.Port,
+				Users:   []string{assignment.Developer},
+				Message: fmt.Sprintf("Port %d of %s is reserved in devenv.yaml (sshPortRange.reserved)", assignment.Port, assignment),
 			})
 			result.IsValid = false
 		}
+		if _, seen := byPort[assignment.Port]; !seen {
+			ports = append(ports, assignment.Port)
+		}
+		byPort[assignment.Port] = append(byPort[assignment.Port], assignment)
 	}
 
-	return result, nil
+	for _, port := range ports {
+		owners := byPort[port]
+		if len(owners) < 2 || reportedAsEnvironmentConflict(owners) {
+			continue
+		}
+
+		var users, labels []string
+		for _, owner := range owners {
+			if !slices.Contains(users, owner.Developer) {
+				users = append(users, owner.Developer)
+			}
+			labels = append(labels, owner.String())
+		}
+		if developer != "" && !slices.Contains(users, developer) {
+			continue
+		}
+		result.Errors = append(result.Errors, ValidationError{
+			Type:    "conflict",
+			Port:    port,
+			Users:   users,
+			Message: fmt.Sprintf("Port %d is assigned to multiple developers: %s", port, strings.Join(labels, ", ")),
+		})
+		result.IsValid = false
+	}
+	return nil
 }
 
-func (pv *PortValidator) validateSingleDeveloper(developerName string) (int, *ValidationError, *ValidationWarning) {
-	cfg, err := config.LoadDeveloperConfig(pv.configDir, developerName)
+// reportedAsEnvironmentConflict reports whether owners are a developer's
+// default environment and some of its named environments. The config
+// validator already rejects those (sshPort:environment_unique).
+func reportedAsEnvironmentConflict(owners []SSHPortAssignment) bool {
+	hasDefault := false
+	for _, owner := range owners {
+		if owner.Developer != owners[0].Developer {
+			return false
+		}
+		hasDefault = hasDefault || owner.Environment == ""
+	}
+	return hasDefault
+}
+
+// reservedPorts returns sshPortRange.reserved from devenv.yaml. A global
+// config that cannot be loaded is reported by the config validator.
+func (pv *PortValidator) reservedPorts() map[int]bool {
+	globalConfig, err := config.LoadGlobalConfig(pv.configDir)
 	if err != nil {
-		return 0, &ValidationError{
-			Type:     "invalid",
-			Users:    []string{developerName},
-			Message:  fmt.Sprintf("Failed to load config: %v", err),
-			FilePath: filepath.Join(pv.configDir, developerName, "devenv-config.yaml"),
-		}, nil
+		return nil
+	}
+	reserved := make(map[int]bool, len(globalConfig.SSHPortRange.Reserved))
+	for _, port := range globalConfig.SSHPortRange.Reserved {
+		reserved[port] = true
 	}
+	return reserved
+}
+
+func (pv *PortValidator) validateSingleDeveloper(entry config.DeveloperIndexEntry) (int, *ValidationError, *ValidationWarning) {
+	developerName := entry.Developer
 
 	// Check if SSH port is configured
-	if cfg.SSHPort == 0 {
+	if entry.SSHPort == 0 {
 		return 0, nil, &ValidationWarning{
 			Type:     "no_ssh_port",
 			User:     developerName,
-			Message:  fmt.Sprintf("No SSH port configured for developer %s", developerName),
-			FilePath: filepath.Join(pv.configDir, developerName, "devenv-config.yaml"),
+			Message:  fmt.Sprintf("No SSH port configured for developer %s (assign one with devenv ports assign %s)", developerName, developerName),
+			FilePath: entry.ConfigPath,
 		}
 	}
 
 	// Validate port range
-	if cfg.SSHPort < NodePortMin || cfg.SSHPort > NodePortMax {
-		return cfg.SSHPort, &ValidationError{
+	if entry.SSHPort < NodePortMin || entry.SSHPort > NodePortMax {
+		return entry.SSHPort, &ValidationError{
 			Type:     "out_of_range",
-			Port:     cfg.SSHPort,
+			Port:     entry.SSHPort,
 			Users:    []string{developerName},
-			Message:  fmt.Sprintf("SSH port %d for developer %s is out of valid range (%d-%d)", cfg.SSHPort, developerName, NodePortMin, NodePortMax),
-			FilePath: filepath.Join(pv.configDir, developerName, "devenv-config.yaml"),
+			Message:  fmt.Sprintf("SSH port %d for developer %s is out of valid range (%d-%d)", entry.SSHPort, developerName, NodePortMin, NodePortMax),
+			FilePath: entry.ConfigPath,
 		}, nil
 	}
 
-	return cfg.SSHPort, nil, nil
+	return entry.SSHPort, nil, nil
 }
 
-// ValidateSingle validates a single developer by running full validation and filtering results
+// ValidateSingle validates a single developer. Conflicts are detected
+// against the lightweight index of the other developers' SSH ports, so
+// checking one developer never fully loads every config in a large repo.
 func (pv *PortValidator) ValidateSingle(developerName string) (*ValidationResult, error) {
-	// Run full validation to catch all conflicts
-	fullResult, err := pv.ValidateAll()
-	if err != nil {
-		return nil, err
-	}
-
-	// Filter results to only those relevant to the target developer
 	result := &ValidationResult{
 		Errors:   []ValidationError{},
 		Warnings: []ValidationWarning{},
 		IsValid:  true,
 	}
 
-	// Filter errors - include if target user is involved
-	for _, err := range fullResult.Errors {
-		if pv.errorInvolvesUser(err, developerName) {
-			result.Errors = append(result.Errors, err)
-			result.IsValid = false
-		}
+	index, err := config.IndexDevelopers(pv.configDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan developer directories in %s: %w", pv.configDir, err)
 	}
 
-	// Filter warnings - include if it's about the target user
-	for _, warning := range fullResult.Warnings {
-		if warning.User == developerName {
-			result.Warnings = append(result.Warnings, warning)
-		}
+	i := slices.IndexFunc(index, func(entry config.DeveloperIndexEntry) bool { return entry.Developer == developerName })
+	if i < 0 || index[i].Err != nil {
+		// Missing and unparseable configs are reported by the config validator
+		return result, nil
 	}
 
+	_, validationError, validationWarning := pv.validateSingleDeveloper(index[i])
+	if validationError != nil {
+		result.Errors = append(result.Errors, *validationError)
+		result.IsValid = false
+	}
+	if validationWarning != nil {
+		result.Warnings = append(result.Warnings, *validationWarning)
+	}
+
+	if err := pv.addSharedPortIssues(result, developerName); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
-// errorInvolvesUser checks if a validation error involves the specified user
-func (pv *PortValidator) errorInvolvesUser(err ValidationError, targetUser string) bool {
-	for _, user := range err.Users {
-		if user == targetUser {
-			return true
+// SSHPortAssignment is an SSH port used by a developer's default
+// environment (Environment is empty) or by one of its named environments.
+type SSHPortAssignment struct {
+	Port        int    `json:"port"`
+	Developer   string `json:"developer"`
+	Environment string `json:"environment,omitempty"`
+}
+
+// String names the owner of the port, e.g. "alice" or
+// "alice (environment gpu)".
+func (a SSHPortAssignment) String() string {
+	if a.Environment == "" {
+		return a.Developer
+	}
+	return a.Developer + " (environment " + a.Environment + ")"
+}
+
+// ListSSHPorts returns the SSH ports declared in configDir, sorted by port
+// and then by developer and environment. Developers without an sshPort and
+// unparseable configs are left out.
+func ListSSHPorts(configDir string) ([]SSHPortAssignment, error) {
+	index, err := config.IndexDevelopers(configDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan developer directories in %s: %w", configDir, err)
+	}
+
+	var assignments []SSHPortAssignment
+	for _, entry := range index {
+		if entry.Err == nil && entry.SSHPort != 0 {
+			assignments = append(assignments, SSHPortAssignment{Port: entry.SSHPort, Developer: entry.Developer})
+		}
+		environmentPorts, err := config.IndexEnvironmentPorts(configDir, entry.Developer)
+		if err != nil {
+			return nil, err
+		}
+		for environment, port := range environmentPorts {
+			assignments = append(assignments, SSHPortAssignment{Port: port, Developer: entry.Developer, Environment: environment})
 		}
 	}
-	return false
+
+	sort.Slice(assignments, func(i, j int) bool {
+		a, b := assignments[i], assignments[j]
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		if a.Developer != b.Developer {
+			return a.Developer < b.Developer
+		}
+		return a.Environment < b.Environment
+	})
+	return assignments, nil
 }
 
-func (pv *PortValidator) findDeveloperDirs() ([]string, error) {
-	var developers []string
+// SSHPortBounds returns the first and last port of portRange, defaulting
+// unset bounds to the NodePort range.
+func SSHPortBounds(portRange config.PortRangeConfig) (int, int) {
+	first, last := NodePortMin, NodePortMax
+	if portRange.Min != 0 {
+		first = portRange.Min
+	}
+	if portRange.Max != 0 {
+		last = portRange.Max
+	}
+	return first, last
+}
 
-	entries, err := os.ReadDir(pv.configDir)
+// AllocateSSHPort returns the lowest port in portRange (see SSHPortBounds)
+// that is neither reserved nor used as an SSH port by any developer or
+// named environment in configDir.
+func AllocateSSHPort(configDir string, portRange config.PortRangeConfig) (int, error) {
+	assignments, err := ListSSHPorts(configDir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config directory %s: %w", pv.configDir, err)
+		return 0, err
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			// Check if devenv-config.yaml exists in the directory
-			configPath := filepath.Join(pv.configDir, entry.Name(), "devenv-config.yaml")
-			if _, err := os.Stat(configPath); err == nil {
-				developers = append(developers, entry.Name())
-			}
-		}
+	used := make(map[int]bool, len(assignments)+len(portRange.Reserved))
+	for _, assignment := range assignments {
+		used[assignment.Port] = true
+	}
+	for _, port := range portRange.Reserved {
+		used[port] = true
 	}
 
-	return developers, nil
+	first, last := SSHPortBounds(portRange)
+	for port := first; port <= last; port++ {
+		if !used[port] {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("all SSH ports in %d-%d are in use", first, last)
 }
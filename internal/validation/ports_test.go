@@ -0,0 +1,143 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/nauticalab/devenv-engine/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errorsOfType returns the errors of result with the given type.
+func errorsOfType(result *ValidationResult, errorType string) []ValidationError {
+	var errors []ValidationError
+	for _, err := range result.Errors {
+		if err.Type == errorType {
+			errors = append(errors, err)
+		}
+	}
+	return errors
+}
+
+func TestPortValidator_ValidateAll(t *testing.T) {
+	configDir := writeConfigDir(t, "sshPortRange:\n  reserved: [30010]\n", map[string]string{
+		// A named environment sharing the default port is reported by the
+		// config validator (sshPort:environment_unique), not here
+		"alice/devenv-config.yaml":      "name: alice\nsshPort: 30001\n",
+		"alice/environments/gpu.yaml":   "sshPort: 30001\n",
+		"bob/devenv-config.yaml":        "name: bob\nsshPort: 30002\n",
+		"bob/environments/train.yaml":   "sshPort: 30005\n",
+		"bob/environments/eval.yaml":    "sshPort: 30005\n",
+		"carol/devenv-config.yaml":      "name: carol\nsshPort: 30003\n",
+		"carol/environments/bench.yaml": "sshPort: 30002\n",
+		"dave/devenv-config.yaml":       "name: dave\nsshPort: 30010\n",
+		"erin/devenv-config.yaml":       "name: erin\nsshPort: 80\n",
+		"frank/devenv-config.yaml":      "name: frank\n",
+	})
+
+	result, err := NewPortValidator(configDir).ValidateAll()
+	require.NoError(t, err)
+	assert.False(t, result.IsValid)
+
+	assert.Equal(t, []ValidationError{
+		{
+			Type:    "conflict",
+			Port:    30002,
+			Users:   []string{"bob", "carol"},
+			Message: "Port 30002 is assigned to multiple developers: bob, carol (environment bench)",
+		},
+		{
+			Type:    "conflict",
+			Port:    30005,
+			Users:   []string{"bob"},
+			Message: "Port 30005 is assigned to multiple developers: bob (environment eval), bob (environment train)",
+		},
+	}, errorsOfType(result, "conflict"))
+
+	reserved := errorsOfType(result, "reserved")
+	require.Len(t, reserved, 1)
+	assert.Equal(t, 30010, reserved[0].Port)
+	assert.Equal(t, []string{"dave"}, reserved[0].Users)
+
+	outOfRange := errorsOfType(result, "out_of_range")
+	require.Len(t, outOfRange, 1)
+	assert.Equal(t, []string{"erin"}, outOfRange[0].Users)
+
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, "no_ssh_port", result.Warnings[0].Type)
+	assert.Equal(t, "frank", result.Warnings[0].User)
+}
+
+func TestPortValidator_ValidateSingle(t *testing.T) {
+	configDir := writeConfigDir(t, "sshPortRange:\n  reserved: [30010]\n", map[string]string{
+		"alice/devenv-config.yaml":      "name: alice\nsshPort: 30001\n",
+		"bob/devenv-config.yaml":        "name: bob\nsshPort: 30002\n",
+		"carol/devenv-config.yaml":      "name: carol\nsshPort: 30010\n",
+		"carol/environments/bench.yaml": "sshPort: 30002\n",
+	})
+	validator := NewPortValidator(configDir)
+
+	// Issues of other developers are left out
+	result, err := validator.ValidateSingle("alice")
+	require.NoError(t, err)
+	assert.True(t, result.IsValid)
+	assert.Empty(t, result.Errors)
+
+	result, err = validator.ValidateSingle("bob")
+	require.NoError(t, err)
+	assert.False(t, result.IsValid)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "conflict", result.Errors[0].Type)
+	assert.Equal(t, []string{"bob", "carol"}, result.Errors[0].Users)
+
+	result, err = validator.ValidateSingle("carol")
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 2)
+	assert.ElementsMatch(t, []string{"reserved", "conflict"}, []string{result.Errors[0].Type, result.Errors[1].Type})
+
+	// Missing developers are reported by the config validator
+	result, err = validator.ValidateSingle("nobody")
+	require.NoError(t, err)
+	assert.True(t, result.IsValid)
+}
+
+func TestListSSHPorts(t *testing.T) {
+	configDir := writeConfigDir(t, "", map[string]string{
+		"alice/devenv-config.yaml":    "name: alice\nsshPort: 30002\n",
+		"alice/environments/gpu.yaml": "sshPort: 30001\n",
+		"bob/devenv-config.yaml":      "name: bob\nsshPort: 30002\n",
+		"carol/devenv-config.yaml":    "name: carol\n",
+		"broken/devenv-config.yaml":   "name: [unterminated\n",
+	})
+
+	assignments, err := ListSSHPorts(configDir)
+	require.NoError(t, err)
+	assert.Equal(t, []SSHPortAssignment{
+		{Port: 30001, Developer: "alice", Environment: "gpu"},
+		{Port: 30002, Developer: "alice"},
+		{Port: 30002, Developer: "bob"},
+	}, assignments)
+	assert.Equal(t, "alice (environment gpu)", assignments[0].String())
+}
+
+func TestAllocateSSHPort(t *testing.T) {
+	configDir := writeConfigDir(t, "", map[string]string{
+		"alice/devenv-config.yaml":    "name: alice\nsshPort: 30101\n",
+		"alice/environments/gpu.yaml": "sshPort: 30103\n",
+	})
+
+	// 30100 and 30102 are reserved, 30101 and 30103 are used
+	portRange := config.PortRangeConfig{Min: 30100, Max: 30104, Reserved: []int{30100, 30102}}
+	port, err := AllocateSSHPort(configDir, portRange)
+	require.NoError(t, err)
+	assert.Equal(t, 30104, port)
+
+	portRange.Max = 30103
+	_, err = AllocateSSHPort(configDir, portRange)
+	assert.EqualError(t, err, "all SSH ports in 30100-30103 are in use")
+
+	// Unset bounds default to the NodePort range
+	port, err = AllocateSSHPort(configDir, config.PortRangeConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, NodePortMin, port)
+}